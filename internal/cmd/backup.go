@@ -0,0 +1,85 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var backupOut string
+var backupRestore string
+var backupYes bool
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up or restore your study database",
+	Long: `Writes a consistent snapshot of your notes and review history.
+Without --out the snapshot goes to a backups folder next to the database.
+
+Use --restore <file> to replace the current database with a backup.
+You will be asked to confirm unless --yes is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if backupRestore != "" {
+			return restoreBackup(backupRestore)
+		}
+
+		dest := backupOut
+		if dest == "" {
+			dbPath, err := db.GetDatabasePath()
+			if err != nil {
+				return err
+			}
+			dest = filepath.Join(filepath.Dir(dbPath), "backups", "neuron-"+time.Now().Format("20060102-150405")+".db")
+		}
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		if err := db.Backup(database, dest); err != nil {
+			return fmt.Errorf("backup failed: %w", err)
+		}
+		fmt.Printf("✓ Backed up database to %s\n", dest)
+		return nil
+	},
+}
+
+// restoreBackup swaps the backup at src in for the current database after confirmation.
+func restoreBackup(src string) error {
+	count, err := db.ValidateBackup(src)
+	if err != nil {
+		return fmt.Errorf("cannot restore: %w", err)
+	}
+
+	if !backupYes {
+		fmt.Printf("⚠️  This replaces your current database, including all review history, with %s (%d notes).\n", src, count)
+		fmt.Print("Continue? (y/n): ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.TrimSpace(strings.ToLower(answer))
+		if answer != "y" && answer != "yes" {
+			fmt.Println("Restore cancelled.")
+			return nil
+		}
+	}
+
+	if err := db.Restore(src); err != nil {
+		return fmt.Errorf("restore failed: %w", err)
+	}
+	fmt.Printf("✓ Restored %d notes from %s\n", count, src)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.Flags().StringVarP(&backupOut, "out", "o", "", "Write the backup to this file (default: a timestamped file in the backups folder)")
+	backupCmd.Flags().StringVar(&backupRestore, "restore", "", "Replace the current database with this backup")
+	backupCmd.Flags().BoolVarP(&backupYes, "yes", "y", false, "Restore without asking for confirmation")
+}
@@ -0,0 +1,69 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the generated question/answer cache",
+	Long: `Review and mix can reuse previously generated questions and answers
+when run with --cache. Use the subcommands here to manage that cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached questions and answers",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		cleared, err := db.ClearQACache(database)
+		if err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+		fmt.Printf("✓ Cleared %d cached question(s).\n", cleared)
+		return nil
+	},
+}
+
+// cachedQuestionAnswer looks up a cached question/answer pair for the note.
+// Pairs are keyed by the note's content hash, so editing a note invalidates them.
+// The boolean result reports whether a usable pair was found.
+func cachedQuestionAnswer(database *sql.DB, n *note.Note, qType study.QuestionType) (string, string, bool) {
+	question, answer, err := db.GetCachedQA(database, n.ID, string(qType), note.ContentHash(n.Content))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Error reading question cache for %s: %v", n.Title, err)
+		}
+		return "", "", false
+	}
+	return question, answer, true
+}
+
+// storeQuestionAnswer saves a freshly generated pair. Failures are logged, not fatal,
+// since the cache is only an optimization.
+func storeQuestionAnswer(database *sql.DB, n *note.Note, qType study.QuestionType, question, answer string) {
+	if err := db.SaveCachedQA(database, n.ID, string(qType), note.ContentHash(n.Content), question, answer); err != nil {
+		log.Printf("Error saving question cache for %s: %v", n.Title, err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
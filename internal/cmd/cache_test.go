@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+func TestCachedQuestionAnswer(t *testing.T) {
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/cache.md", "# Cache\nbody")
+
+	if _, _, ok := cachedQuestionAnswer(database, n, study.QuestionTypeFactual); ok {
+		t.Fatal("empty cache reported a hit")
+	}
+	storeQuestionAnswer(database, n, study.QuestionTypeFactual, "Q?", "A.")
+	question, answer, ok := cachedQuestionAnswer(database, n, study.QuestionTypeFactual)
+	if !ok || question != "Q?" || answer != "A." {
+		t.Fatalf("got %q, %q, %v; want the stored pair", question, answer, ok)
+	}
+
+	n.Content += "\nedited"
+	if _, _, ok := cachedQuestionAnswer(database, n, study.QuestionTypeFactual); ok {
+		t.Error("editing the note should invalidate its cached pair")
+	}
+}
+
+func TestBatchReviewUsesCache(t *testing.T) {
+	database := testDB(t)
+	addTestNote(t, database, "/notes/batch.md", "# Batch\nbody")
+	provider := useFakeProvider(t, "Generated.")
+
+	if err := runBatchReview(database, study.QuestionTypeFactual, study.AnswerMedium, true); err != nil {
+		t.Fatal(err)
+	}
+	if provider.generates != 2 {
+		t.Fatalf("cache miss made %d model calls, want 2 (question and answer)", provider.generates)
+	}
+	if err := runBatchReview(database, study.QuestionTypeFactual, study.AnswerMedium, true); err != nil {
+		t.Fatal(err)
+	}
+	if provider.generates != 2 {
+		t.Errorf("cache hit made %d more model calls, want none", provider.generates-2)
+	}
+}
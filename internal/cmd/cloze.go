@@ -0,0 +1,96 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var clozeCount int
+var clozeExport string
+var clozeOut string
+
+var clozeCmd = &cobra.Command{
+	Use:   "cloze [topic]",
+	Short: "Generate fill-in-the-blank flashcards from a note",
+	Long: `Asks the AI for cloze-deletion cards built around the key terms of a note
+and prints them. Use --export anki to write them as Anki cloze notes instead
+(to stdout, or to a file with --out).`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if clozeExport != "" && clozeExport != "anki" {
+			return fmt.Errorf("unknown export format %q (valid formats: anki)", clozeExport)
+		}
+
+		_, n, err := findTopicNote(args[0])
+		if err != nil || n == nil {
+			return err
+		}
+
+		// Progress goes to stderr so it never mixes with cards written to stdout.
+		fmt.Fprintf(os.Stderr, "🧠 Generating %d cloze cards for %s...\n", clozeCount, n.Title)
+		cards, err := study.GenerateClozes(n, clozeCount)
+		if err != nil {
+			return fmt.Errorf("failed to generate cloze cards: %w", err)
+		}
+
+		if clozeExport == "" {
+			frontColor := color.New(color.FgCyan)
+			answerColor := color.New(color.FgGreen)
+			for i, card := range cards {
+				frontColor.Printf("\n%d. %s\n", i+1, card.Front)
+				answerColor.Printf("   → %s\n", card.Answer)
+			}
+			return nil
+		}
+
+		var out io.Writer = os.Stdout
+		if clozeOut != "" {
+			file, err := os.Create(clozeOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", clozeOut, err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := writeAnkiClozes(out, cards, n.Tags); err != nil {
+			return fmt.Errorf("failed to export cloze cards: %w", err)
+		}
+		if clozeOut != "" {
+			fmt.Printf("✓ Exported %d cloze cards to %s\n", len(cards), clozeOut)
+		}
+		return nil
+	},
+}
+
+// writeAnkiClozes writes cards for Anki's built-in Cloze note type, whose
+// Text field marks the hidden term as {{c1::term}}.
+func writeAnkiClozes(w io.Writer, cards []study.ClozeCard, tags []string) error {
+	if _, err := io.WriteString(w, "#separator:comma\n#html:true\n#notetype:Cloze\n#tags column:3\n"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	for _, card := range cards {
+		text := strings.Replace(ankiHTML(card.Front), study.ClozeBlank, "{{c1::"+ankiHTML(card.Answer)+"}}", 1)
+		if err := writer.Write([]string{text, "", ankiTags(tags)}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func init() {
+	rootCmd.AddCommand(clozeCmd)
+	clozeCmd.Flags().IntVar(&clozeCount, "count", 5, "Number of cloze cards to generate")
+	clozeCmd.Flags().StringVar(&clozeExport, "export", "", "Write the cards in this format instead of printing them: anki")
+	clozeCmd.Flags().StringVarP(&clozeOut, "out", "o", "", "With --export, write to this file instead of stdout")
+}
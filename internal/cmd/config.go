@@ -0,0 +1,133 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configInitForce bool
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the config file",
+	Long: `Settings are read from config.yaml, next to the database. Environment
+variables (NEURON_MODEL, NEURON_OLLAMA_HOST, NEURON_NOTES_DIR) override the
+file, and command-line flags override both.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// isConfigCommand reports whether cmd is config or one of its subcommands.
+func isConfigCommand(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd == configCmd {
+			return true
+		}
+	}
+	return false
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented config file with the default settings",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err == nil && !configInitForce {
+			return fmt.Errorf("%s already exists; pass --force to overwrite it", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("could not create config directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(config.Template), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Wrote %s\n", path)
+		return nil
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the location of the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := config.Path()
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a setting, or all settings",
+	Long: `Prints the value of a setting as currently in effect, including environment
+overrides. Without a key, lists every setting; unset ones are blank and use the
+built-in default.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			setting, err := findConfigSetting(args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Println(setting.get(cfg))
+			return nil
+		}
+		for _, setting := range configSettings {
+			fmt.Printf("%s: %s\n", setting.key, setting.get(cfg))
+		}
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the config file",
+	Long: `Validates value and saves it to the config file. An empty value ("")
+clears the setting so the default applies again. Lists such as
+summary_sections and import.ignore_dirs are comma-separated.
+The file is rewritten, so comments in it are dropped.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		setting, err := findConfigSetting(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := config.LoadFile()
+		if err != nil {
+			return err
+		}
+		if err := setting.set(cfg, strings.TrimSpace(args[1])); err != nil {
+			return err
+		}
+		if err := config.Save(cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ %s = %s\n", setting.key, setting.get(cfg))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configInitCmd, configPathCmd, configGetCmd, configSetCmd)
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false, "Overwrite an existing config file")
+}
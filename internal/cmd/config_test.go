@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+)
+
+// savedSetting returns key as stored in the config file.
+func savedSetting(t *testing.T, key string) string {
+	t.Helper()
+	setting, err := findConfigSetting(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return setting.get(cfg)
+}
+
+func TestConfigSetAndGet(t *testing.T) {
+	tests := []struct {
+		key, value, want string
+	}{
+		{"model", "llama3.2:3b", "llama3.2:3b"},
+		{"temperature", "0.3", "0.3"},
+		{"target_retention", "0.85", "0.85"},
+		{"leech_threshold", "0", "0"},
+		{"fuzz", "true", "true"},
+		{"learning_steps", "1m, 10m,1d", "1m, 10m, 1d"},
+		{"lapse_interval", "10m", "10m"},
+		{"models", "review=llama3.2:3b", "review=llama3.2:3b"},
+		{"database.busy_timeout_ms", "2000", "2000"},
+		{"import.ignore_dirs", ".git,node_modules", ".git, node_modules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			useConfigFile(t, &config.Config{})
+			if err := executeRoot(t, "config", "set", tt.key, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if got := savedSetting(t, tt.key); got != tt.want {
+				t.Errorf("after set %s %q, got %q, want %q", tt.key, tt.value, got, tt.want)
+			}
+
+			if err := executeRoot(t, "config", "set", tt.key, ""); err != nil {
+				t.Fatal(err)
+			}
+			if got := savedSetting(t, tt.key); got != "" {
+				t.Errorf("after clearing %s, got %q, want it unset", tt.key, got)
+			}
+		})
+	}
+}
+
+func TestConfigSetRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"target_retention", "1.5"},
+		{"temperature", "-1"},
+		{"leech_threshold", "-1"},
+		{"fuzz", "sometimes"},
+		{"learning_steps", "1m, soon"},
+		{"again_penalty", "3"},
+		{"ollama_host", "localhost:11434"},
+		{"no_such_key", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			useConfigFile(t, &config.Config{})
+			if err := executeRoot(t, "config", "set", tt.key, tt.value); err == nil {
+				t.Errorf("config set %s %q succeeded, want an error", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestConfigSetFixesAnInvalidConfig(t *testing.T) {
+	useConfigFile(t, &config.Config{LapseInterval: "soon"})
+	if err := executeRoot(t, "tune"); err == nil {
+		t.Fatal("tune ran with an invalid lapse_interval, want an error")
+	}
+	if err := executeRoot(t, "config", "set", "lapse_interval", "10m"); err != nil {
+		t.Fatalf("config set with an invalid config file: %v", err)
+	}
+	if err := executeRoot(t, "tune"); err != nil {
+		t.Errorf("tune after fixing the config: %v", err)
+	}
+}
@@ -0,0 +1,396 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+// configSetting is one key that "config get" and "config set" understand.
+// An empty value passed to set clears the setting, restoring its default.
+type configSetting struct {
+	key string
+	get func(*config.Config) string
+	set func(*config.Config, string) error
+}
+
+// configSettings lists the settings in the order "config get" prints them.
+var configSettings = []configSetting{
+	{"notes_dir",
+		func(c *config.Config) string { return c.NotesDir },
+		func(c *config.Config, v string) error { c.NotesDir = v; return nil }},
+	{"model",
+		func(c *config.Config) string { return c.Model },
+		func(c *config.Config, v string) error { c.Model = v; return nil }},
+	{"ollama_host",
+		func(c *config.Config) string { return c.OllamaHost },
+		func(c *config.Config, v string) error {
+			if v != "" {
+				u, err := url.Parse(v)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("ollama_host must be an http or https URL such as http://localhost:11434, got %q", v)
+				}
+			}
+			c.OllamaHost = v
+			return nil
+		}},
+	{"temperature",
+		func(c *config.Config) string { return formatFloatPtr(c.Temperature) },
+		func(c *config.Config, v string) error {
+			t, err := parseFloatPtr(v)
+			if err != nil {
+				return err
+			}
+			if t != nil && *t < 0 {
+				return fmt.Errorf("temperature must not be negative, got %s", v)
+			}
+			c.Temperature = t
+			return nil
+		}},
+	{"seed",
+		func(c *config.Config) string {
+			if c.Seed == nil {
+				return ""
+			}
+			return strconv.Itoa(*c.Seed)
+		},
+		func(c *config.Config, v string) error {
+			if v == "" {
+				c.Seed = nil
+				return nil
+			}
+			seed, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("seed must be a whole number, got %q", v)
+			}
+			c.Seed = &seed
+			return nil
+		}},
+	{"models",
+		func(c *config.Config) string { return formatPairs(c.Models) },
+		func(c *config.Config, v string) (err error) {
+			c.Models, err = parseModels(v)
+			return err
+		}},
+	{"max_concurrency",
+		func(c *config.Config) string { return formatInt(c.MaxConcurrency) },
+		func(c *config.Config, v string) (err error) {
+			c.MaxConcurrency, err = parseNonNegativeInt("max_concurrency", v)
+			return err
+		}},
+	{"stream",
+		func(c *config.Config) string { return formatBoolPtr(c.Stream) },
+		func(c *config.Config, v string) (err error) {
+			c.Stream, err = parseBoolPtr("stream", v)
+			return err
+		}},
+	{"target_retention",
+		func(c *config.Config) string { return formatFloat(c.TargetRetention) },
+		func(c *config.Config, v string) error {
+			r, err := parseFloatPtr(v)
+			if err != nil {
+				return err
+			}
+			if r == nil {
+				c.TargetRetention = 0
+				return nil
+			}
+			if err := study.ValidateTargetRetention(*r); err != nil {
+				return err
+			}
+			c.TargetRetention = *r
+			return nil
+		}},
+	{"leech_threshold",
+		func(c *config.Config) string { return formatIntPtr(c.LeechThreshold) },
+		func(c *config.Config, v string) (err error) {
+			c.LeechThreshold, err = parseNonNegativeIntPtr("leech_threshold", v)
+			return err
+		}},
+	{"fuzz",
+		func(c *config.Config) string { return formatBoolPtr(c.Fuzz) },
+		func(c *config.Config, v string) (err error) {
+			c.Fuzz, err = parseBoolPtr("fuzz", v)
+			return err
+		}},
+	{"learning_steps",
+		func(c *config.Config) string { return strings.Join(c.LearningSteps, ", ") },
+		func(c *config.Config, v string) error {
+			steps := splitList(v)
+			if _, err := study.ParseLearningSteps(steps); err != nil {
+				return err
+			}
+			c.LearningSteps = steps
+			return nil
+		}},
+	{"lapse_interval",
+		func(c *config.Config) string { return c.LapseInterval },
+		func(c *config.Config, v string) error {
+			if v != "" {
+				if _, err := study.ParseDelay(v); err != nil {
+					return fmt.Errorf("invalid lapse_interval %q: %w", v, err)
+				}
+			}
+			c.LapseInterval = v
+			return nil
+		}},
+	{"again_penalty",
+		func(c *config.Config) string { return formatFloatPtr(c.AgainPenalty) },
+		func(c *config.Config, v string) (err error) {
+			c.AgainPenalty, err = parseEaseAdjustment("again_penalty", v)
+			return err
+		}},
+	{"easy_bonus",
+		func(c *config.Config) string { return formatFloatPtr(c.EasyBonus) },
+		func(c *config.Config, v string) (err error) {
+			c.EasyBonus, err = parseEaseAdjustment("easy_bonus", v)
+			return err
+		}},
+	{"tag_ease",
+		func(c *config.Config) string { return formatPairs(c.TagEase) },
+		func(c *config.Config, v string) error {
+			byTag, err := parseTagEase(v)
+			if err != nil {
+				return err
+			}
+			if _, err := study.DefaultSchedulerConfig().ParseTagEase(byTag); err != nil {
+				return err
+			}
+			c.TagEase = byTag
+			return nil
+		}},
+	{"language",
+		func(c *config.Config) string { return c.Language },
+		func(c *config.Config, v string) error { c.Language = v; return nil }},
+	{"reflection_persona",
+		func(c *config.Config) string { return c.ReflectionPersona },
+		func(c *config.Config, v string) error { c.ReflectionPersona = v; return nil }},
+	{"max_prompt_chars",
+		func(c *config.Config) string { return formatInt(c.MaxPromptChars) },
+		func(c *config.Config, v string) (err error) {
+			c.MaxPromptChars, err = parseNonNegativeInt("max_prompt_chars", v)
+			return err
+		}},
+	{"summary_sections",
+		func(c *config.Config) string { return strings.Join(c.SummarySections, ", ") },
+		func(c *config.Config, v string) error { c.SummarySections = splitList(v); return nil }},
+	{"database.wal",
+		func(c *config.Config) string { return formatBoolPtr(c.Database.WAL) },
+		func(c *config.Config, v string) (err error) {
+			c.Database.WAL, err = parseBoolPtr("database.wal", v)
+			return err
+		}},
+	{"database.foreign_keys",
+		func(c *config.Config) string { return formatBoolPtr(c.Database.ForeignKeys) },
+		func(c *config.Config, v string) (err error) {
+			c.Database.ForeignKeys, err = parseBoolPtr("database.foreign_keys", v)
+			return err
+		}},
+	{"database.busy_timeout_ms",
+		func(c *config.Config) string { return formatInt(c.Database.BusyTimeoutMS) },
+		func(c *config.Config, v string) (err error) {
+			c.Database.BusyTimeoutMS, err = parseNonNegativeInt("database.busy_timeout_ms", v)
+			return err
+		}},
+	{"import.ignore_dirs",
+		func(c *config.Config) string {
+			if c.Import.IgnoreDirs == nil {
+				return ""
+			}
+			return strings.Join(c.Import.IgnoreDirs, ", ")
+		},
+		func(c *config.Config, v string) error { c.Import.IgnoreDirs = splitList(v); return nil }},
+	{"import.warn_size_kb",
+		func(c *config.Config) string { return formatInt(c.Import.WarnSizeKB) },
+		func(c *config.Config, v string) (err error) {
+			c.Import.WarnSizeKB, err = parseNonNegativeInt("import.warn_size_kb", v)
+			return err
+		}},
+}
+
+// findConfigSetting looks up a key, listing the valid ones when it is unknown.
+func findConfigSetting(key string) (configSetting, error) {
+	for _, s := range configSettings {
+		if s.key == key {
+			return s, nil
+		}
+	}
+	keys := make([]string, len(configSettings))
+	for i, s := range configSettings {
+		keys[i] = s.key
+	}
+	return configSetting{}, fmt.Errorf("unknown config key %q (valid keys: %s)", key, strings.Join(keys, ", "))
+}
+
+// splitList parses a comma-separated value, dropping blank items. An empty value yields nil.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func parseFloatPtr(v string) (*float64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, fmt.Errorf("expected a number, got %q", v)
+	}
+	return &f, nil
+}
+
+// parseEaseAdjustment parses an Again penalty or Easy bonus; "" means the default.
+func parseEaseAdjustment(key, v string) (*float64, error) {
+	f, err := parseFloatPtr(v)
+	if f == nil || err != nil {
+		return nil, err
+	}
+	if err := study.ValidateEaseAdjustment(key, *f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseNonNegativeInt(key, v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a whole number of 0 or more, got %q", key, v)
+	}
+	return n, nil
+}
+
+// parseNonNegativeIntPtr is parseNonNegativeInt for settings where 0 differs
+// from unset. An empty value yields nil.
+func parseNonNegativeIntPtr(key, v string) (*int, error) {
+	if v == "" {
+		return nil, nil
+	}
+	n, err := parseNonNegativeInt(key, v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func parseBoolPtr(key, v string) (*bool, error) {
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false, got %q", key, v)
+	}
+	return &b, nil
+}
+
+// parsePairs parses a comma-separated list of name=value pairs, such as
+// "languages=2.0, trivia=2.7". example is shown when an entry is malformed.
+func parsePairs(key, v, example string) (map[string]string, error) {
+	items := splitList(v)
+	if items == nil {
+		return nil, nil
+	}
+	pairs := make(map[string]string, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: expected name=value, e.g. %s", key, item, example)
+		}
+		pairs[name] = value
+	}
+	return pairs, nil
+}
+
+// formatPairs prints name=value pairs sorted by name, the form parsePairs reads.
+func formatPairs[V any](pairs map[string]V) string {
+	items := make([]string, 0, len(pairs))
+	for name, value := range pairs {
+		items = append(items, fmt.Sprintf("%s=%v", name, value))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
+
+// parseTagEase parses "languages=2.0, trivia=2.7" into ease factors by tag.
+func parseTagEase(v string) (map[string]float64, error) {
+	pairs, err := parsePairs("tag_ease", v, "languages=2.0")
+	if pairs == nil || err != nil {
+		return nil, err
+	}
+	byTag := make(map[string]float64, len(pairs))
+	for tag, value := range pairs {
+		ease, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tag_ease entry for %q: expected a number, got %q", tag, value)
+		}
+		byTag[tag] = ease
+	}
+	return byTag, nil
+}
+
+// parseModels parses "review=llama3.2:3b, teach=llama3:70b", checking that each
+// name is a command.
+func parseModels(v string) (map[string]string, error) {
+	models, err := parsePairs("models", v, "review=llama3.2:3b")
+	if err != nil {
+		return nil, err
+	}
+	for command := range models {
+		if found, _, err := rootCmd.Find([]string{command}); err != nil || found == rootCmd {
+			return nil, fmt.Errorf("invalid models entry: %q is not a neuron command", command)
+		}
+	}
+	return models, nil
+}
+
+// The format helpers print unset (zero or nil) values as "".
+
+func formatFloat(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatFloatPtr(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'g', -1, 64)
+}
+
+func formatInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func formatIntPtr(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func formatBoolPtr(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
@@ -0,0 +1,147 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var cramTag string
+var cramQuestionType string
+
+var cramCmd = &cobra.Command{
+	Use:   "cram [topic]",
+	Short: "Drill a topic hard without touching your review schedule",
+	Long: `Repeatedly asks questions on every note matching a topic (or --tag),
+compares your answers with the AI's, and reshuffles after each round.
+Cramming never changes your spaced repetition schedule, so it is safe to use
+before an exam. Type 'quit' at any time to stop.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: usesLLM,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (len(args) == 1) == (cramTag != "") {
+			return fmt.Errorf("specify either a topic or --tag")
+		}
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		var notes []*note.Note
+		if cramTag != "" {
+			notes, err = db.GetNotesByTag(database, cramTag, -1)
+		} else {
+			notes, err = db.SearchNotes(database, args[0])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes: %w", err)
+		}
+		if len(notes) == 0 {
+			fmt.Println("Sorry, I couldn't find any notes to cram.")
+			return nil
+		}
+
+		qType := study.QuestionType(cramQuestionType)
+		if qType == "" {
+			qType = study.QuestionTypeMixed
+		}
+
+		fmt.Printf("--- Cram Session: %d note(s) ---\n", len(notes))
+		fmt.Println("Your review schedule won't change. Type 'help' anytime to see available commands.")
+		fmt.Println("---------------------------------------------------------------------------------")
+
+		reader := bufio.NewReader(os.Stdin)
+		attempts := make(map[int]int) // question count per note, so repeated questions vary
+		asked := 0
+		for round := 1; ; round++ {
+			rand.Shuffle(len(notes), func(i, j int) { notes[i], notes[j] = notes[j], notes[i] })
+			color.New(color.FgCyan, color.Bold).Printf("\n🔁 Round %d\n", round)
+
+			for _, n := range notes {
+				quit, err := cramNote(reader, n, qType, attempts)
+				if err != nil {
+					return err
+				}
+				if quit {
+					fmt.Printf("Cram session ended after %d question(s). Your schedule is unchanged.\n", asked)
+					return nil
+				}
+				asked++
+			}
+		}
+	},
+}
+
+// cramNote asks questions on one note until the user answers or skips.
+// It returns true when the user wants to end the session.
+func cramNote(reader *bufio.Reader, n *note.Note, qType study.QuestionType, attempts map[int]int) (bool, error) {
+	attempts[n.ID]++
+	fmt.Printf("\n🧠 Generating %s question on '%s'...\n", qType, n.Title)
+	question, err := study.GenerateQuestionWithVariation(n, qType, attempts[n.ID])
+	if err != nil {
+		return false, fmt.Errorf("failed to generate question: %w", err)
+	}
+
+	for {
+		color.New(color.FgCyan).Printf("\n🤔 Question: %s\n", question)
+		fmt.Print("\nType your answer (or 'help' for commands): ")
+		userInput, err := readLine(reader)
+		if err != nil {
+			fmt.Println("\nInput closed.")
+			return true, nil
+		}
+		userInput = strings.TrimSpace(userInput)
+
+		switch strings.ToLower(userInput) {
+		case "help", "?":
+			color.New(color.FgGreen).Println("\n🛠️  Available Commands:")
+			fmt.Println("  • 'note' or 'show note' - Display the full note content")
+			fmt.Println("  • 'hint' - Get a short hint for this question")
+			fmt.Println("  • 'skip' - Move on to the next note")
+			fmt.Println("  • 'quit' or 'exit' - End the session")
+			fmt.Println()
+			continue
+		case "quit", "exit":
+			return true, nil
+		case "note", "show note":
+			fmt.Println("\n📖 Full Note Content:")
+			fmt.Println("-----------------------------------------------------------")
+			printMarkdown(n.Content)
+			fmt.Println("-----------------------------------------------------------")
+			continue
+		case "hint":
+			fmt.Println("\n🔎 Generating hint...")
+			hint, err := study.GenerateHint(question, n)
+			if err != nil {
+				fmt.Printf("Could not generate a hint: %v\n", err)
+			} else {
+				color.New(color.FgYellow).Printf("\n💭 Hint: %s\n", hint)
+			}
+			continue
+		case "skip":
+			fmt.Println("Skipped.")
+			return false, nil
+		case "":
+			fmt.Println("Please provide an answer or type a command.")
+			continue
+		}
+
+		return false, showComparison(question, userInput, n)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(cramCmd)
+	cramCmd.Flags().StringVar(&cramTag, "tag", "", "Cram every note with this tag instead of a topic")
+	cramCmd.Flags().StringVar(&cramQuestionType, "question-type", "mixed", "Type of question to generate: factual, conceptual, application, mixed")
+}
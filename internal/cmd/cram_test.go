@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+func TestCramLeavesScheduleAlone(t *testing.T) {
+	database := testDB(t)
+	scheduledNote(t, database, "first", "exam")
+	scheduledNote(t, database, "second", "exam")
+	before, err := db.GetNotesByTag(database, "exam", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := useFakeProvider(t, "A fine answer.")
+	cramTag = "exam"
+	t.Cleanup(func() { cramTag = "" })
+
+	// Answer the first note, skip the second, then ask for a hint and quit in round two.
+	withStdin(t, "my answer\nskip\nhint\nquit\n")
+	var runErr error
+	output := captureStdout(t, func() { runErr = cramCmd.RunE(cramCmd, nil) })
+	if runErr != nil {
+		t.Fatal(runErr)
+	}
+	if !strings.Contains(output, "Round 2") || !strings.Contains(output, "ended after 2 question(s)") {
+		t.Errorf("cram didn't run the input through to round two:\n%s", output)
+	}
+	if provider.generates == 0 {
+		t.Error("cram made no LLM calls")
+	}
+
+	for _, want := range before {
+		got, err := db.GetNoteByTitleOrFilename(database, want.Title)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.DueDate.Equal(want.DueDate) || got.Interval != want.Interval || got.EaseFactor != want.EaseFactor ||
+			got.State != want.State || got.Lapses != want.Lapses {
+			t.Errorf("cram changed the schedule of %s: due %s, interval %v, ease %v, state %q; was due %s, interval %v, ease %v, state %q",
+				want.Title, got.DueDate, got.Interval, got.EaseFactor, got.State, want.DueDate, want.Interval, want.EaseFactor, want.State)
+		}
+	}
+	if reviewed := reviewedNotes(t, database); len(reviewed) != 0 {
+		t.Errorf("cram logged reviews of notes %v", reviewed)
+	}
+}
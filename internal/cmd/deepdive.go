@@ -3,7 +3,6 @@ package cmd
 
 import (
 	"bufio"
-	"database/sql"
 	"fmt"
 	"os"
 	"strings"
@@ -14,13 +13,17 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var deepDiveSave string
+var deepDiveResume string
+
 var deepDiveCmd = &cobra.Command{
 	Use:   "deep-dive [topic]",
 	Short: "Explore a topic's connections using Socratic questioning",
 	Long: `Starts an interactive session where the AI acts as a Socratic tutor.
 It will ask you "why" and "how" questions about a specific note to help you
 explore its connections and deepen your understanding.`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		topic := args[0]
 
@@ -29,12 +32,8 @@ explore its connections and deepen your understanding.`,
 			return err
 		}
 
-		noteToExplore, err := db.GetNoteByTitleOrFilename(database, topic)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				fmt.Printf("Sorry, I couldn't find a note matching '%s'.\n", topic)
-				return nil
-			}
+		noteToExplore, err := lookupNote(database, topic)
+		if err != nil || noteToExplore == nil {
 			return err
 		}
 
@@ -53,6 +52,18 @@ explore its connections and deepen your understanding.`,
 			},
 		}
 
+		// Continue an earlier conversation instead of starting fresh
+		awaitingUser := false
+		if deepDiveResume != "" {
+			messages, err = study.LoadTranscript(deepDiveResume)
+			if err != nil {
+				return fmt.Errorf("failed to resume conversation: %w", err)
+			}
+			fmt.Printf("Resuming conversation from %s (%d messages).\n", deepDiveResume, len(messages))
+			awaitingUser = messages[len(messages)-1].Role == "assistant"
+		}
+		defer saveConversation(deepDiveSave, &messages)
+
 		aiColor := color.New(color.FgCyan)
 		userColor := color.New(color.FgYellow, color.Bold)
 
@@ -60,19 +71,28 @@ explore its connections and deepen your understanding.`,
 
 		// Show available commands at start
 		helpColor := color.New(color.FgGreen)
-		helpColor.Println("\n💡 Tip: Type 'help' anytime to see available commands\n")
+		helpColor.Print("\n💡 Tip: Type 'help' anytime to see available commands\n\n")
 
 		for {
-			aiResponse, err := study.SendChatMessage(messages)
-			if err != nil {
-				return err
+			if awaitingUser {
+				// The resumed transcript ends with a question we haven't answered yet
+				awaitingUser = false
+				aiColor.Printf("\n🤔 Tutor: %s\n", messages[len(messages)-1].Content)
+			} else {
+				aiResponse, err := chatReply(messages, aiColor, "🤔 Tutor: ")
+				if err != nil {
+					return err
+				}
+				messages = append(messages, aiResponse)
 			}
-			messages = append(messages, aiResponse)
 
-			aiColor.Printf("\n🤔 Tutor: %s\n", aiResponse.Content)
 			userColor.Print("Your Thoughts: ")
 
-			userInput, _ := reader.ReadString('\n')
+			userInput, err := readLine(reader)
+			if err != nil {
+				fmt.Println("\nInput closed; ending the deep dive.")
+				break
+			}
 			userInput = strings.TrimSpace(userInput)
 
 			// Check for special commands
@@ -97,4 +117,8 @@ explore its connections and deepen your understanding.`,
 
 func init() {
 	rootCmd.AddCommand(deepDiveCmd)
+	addIndexFlag(deepDiveCmd)
+	deepDiveCmd.Flags().StringVar(&deepDiveSave, "save", "", "Write the conversation to this file when the session ends (.json or Markdown)")
+	deepDiveCmd.Flags().BoolVar(&noStream, "no-stream", false, "Print each reply once it is complete instead of as it is generated")
+	deepDiveCmd.Flags().StringVar(&deepDiveResume, "resume", "", "Continue a conversation saved with --save")
 }
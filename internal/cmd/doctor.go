@@ -0,0 +1,148 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+// doctorConfigErr is what is wrong with the config file, found before doctor runs.
+var doctorConfigErr error
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that the database, LLM server, model, and notes folder are usable",
+	Long: `Runs a quick health check of your setup and prints a ✓/✗ report:
+- the config file is valid
+- the database can be opened and written to
+- the LLM server (--provider) is reachable
+- the model is available on that server (pulled, for Ollama)
+- the notes folder exists: notes_dir (or $NEURON_NOTES_DIR), else the folder of the last import
+
+Exits with a non-zero status if any check fails.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		okColor := color.New(color.FgGreen)
+		failColor := color.New(color.FgRed)
+		warnColor := color.New(color.FgYellow)
+		failed := 0
+		pass := func(format string, a ...any) {
+			okColor.Print("✓ ")
+			fmt.Printf(format+"\n", a...)
+		}
+		fail := func(format string, a ...any) {
+			failColor.Print("✗ ")
+			fmt.Printf(format+"\n", a...)
+			failed++
+		}
+		warn := func(format string, a ...any) {
+			warnColor.Print("! ")
+			fmt.Printf(format+"\n", a...)
+		}
+
+		fmt.Println("--- Neuron Doctor ---")
+
+		configPath, _ := config.Path()
+		if doctorConfigErr != nil {
+			fail("Config: %v", doctorConfigErr)
+		} else {
+			pass("Config %s is valid", configPath)
+		}
+
+		dbPath, dbErr := db.CheckDatabase()
+		if dbErr != nil {
+			fail("Database %s: %v", dbPath, dbErr)
+		} else {
+			pass("Database %s is writable", dbPath)
+		}
+
+		model := study.ActiveModel()
+		models, err := study.ListModels()
+		switch {
+		case err != nil:
+			fail("LLM server (%s): %v", providerName, err)
+			fail("Model %s: can't be checked while the server is unreachable", model)
+		case study.HasModel(models, model):
+			pass("LLM server (%s) is reachable with %d model(s)", providerName, len(models))
+			pass("Model %s is available", model)
+		default:
+			pass("LLM server (%s) is reachable with %d model(s)", providerName, len(models))
+			if providerName == study.ProviderOllama {
+				fail("Model %s is not pulled. Run 'ollama pull %s'", model, model)
+			} else {
+				fail("Model %s is not offered by the server", model)
+			}
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = &config.Config{}
+		}
+		// GetDB exits on a database that can't be opened, so only look up the last import in a healthy one.
+		checkNotesDir(cfg, dbErr == nil, pass, fail, warn)
+
+		if failed > 0 {
+			return fmt.Errorf("%d check(s) failed", failed)
+		}
+		fmt.Println("\nEverything looks good. Happy studying!")
+		return nil
+	},
+}
+
+// loadDoctorConfig applies the config file like every other command does, but
+// returns what is wrong with it instead of stopping doctor. The settings before an
+// invalid one still apply, and a file that can't be read at all is replaced by the
+// built-in defaults, so the remaining checks can run.
+func loadDoctorConfig(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		if flagErr := applyConfig(cmd, &config.Config{}); flagErr != nil {
+			return flagErr
+		}
+		return err
+	}
+	return applyConfig(cmd, cfg)
+}
+
+// checkNotesDir reports whether the notes directory exists: the configured one
+// that import syncs by default, or else the directory of the last import. The
+// last import is only looked up when lastImport is true.
+func checkNotesDir(cfg *config.Config, lastImport bool, pass, fail, warn func(format string, a ...any)) {
+	notesPath := cfg.NotesDirectory()
+	if notesPath == "" {
+		if !lastImport {
+			return
+		}
+		database, err := db.GetDB()
+		if err != nil {
+			fail("Notes directory: %v", err)
+			return
+		}
+		var ok bool
+		notesPath, ok, err = db.GetLastImportPath(database)
+		switch {
+		case err != nil:
+			fail("Notes directory: %v", err)
+			return
+		case !ok:
+			warn("Notes directory: none configured and no import recorded yet. Set notes_dir or run 'neuron import <path>'")
+			return
+		}
+	}
+	if info, err := os.Stat(notesPath); err != nil || !info.IsDir() {
+		fail("Notes directory %s does not exist", notesPath)
+	} else {
+		pass("Notes directory %s exists", notesPath)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
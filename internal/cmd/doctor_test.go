@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+// doctorReport collects the lines a check prints, prefixed with its outcome.
+type doctorReport []string
+
+func (r *doctorReport) pass(format string, a ...any) {
+	*r = append(*r, "pass: "+fmt.Sprintf(format, a...))
+}
+func (r *doctorReport) fail(format string, a ...any) {
+	*r = append(*r, "fail: "+fmt.Sprintf(format, a...))
+}
+func (r *doctorReport) warn(format string, a ...any) {
+	*r = append(*r, "warn: "+fmt.Sprintf(format, a...))
+}
+
+func TestCheckNotesDir(t *testing.T) {
+	database := testDB(t)
+	configured := t.TempDir()
+	imported := t.TempDir()
+	if err := db.SetLastImportPath(database, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		want string
+	}{
+		{"configured directory first", &config.Config{NotesDir: configured}, "pass: Notes directory " + configured + " exists"},
+		{"missing configured directory", &config.Config{NotesDir: filepath.Join(configured, "missing")}, "fail: Notes directory " + filepath.Join(configured, "missing") + " does not exist"},
+		{"last import otherwise", &config.Config{}, "pass: Notes directory " + imported + " exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var report doctorReport
+			checkNotesDir(tt.cfg, true, report.pass, report.fail, report.warn)
+			if len(report) != 1 || report[0] != tt.want {
+				t.Errorf("report = %q, want %q", report, tt.want)
+			}
+		})
+	}
+
+	testDB(t)
+	var report doctorReport
+	checkNotesDir(&config.Config{}, true, report.pass, report.fail, report.warn)
+	if len(report) != 1 || !strings.HasPrefix(report[0], "warn: ") {
+		t.Errorf("without a directory or an import, report = %q, want a warning", report)
+	}
+}
+
+func TestDoctorReportsAnInvalidConfig(t *testing.T) {
+	testDB(t)
+	path, err := config.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"unparseable", "learning_steps: [1m\n", "Config: could not parse config"},
+		{"invalid setting", "lapse_interval: soon\n", `Config: invalid config: lapse_interval "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfigFile(t, &config.Config{})
+			if err := os.WriteFile(path, []byte(tt.file), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var runErr error
+			output := captureStdout(t, func() { runErr = executeRoot(t, "doctor", "--no-color") })
+			if runErr == nil || !strings.Contains(runErr.Error(), "check(s) failed") {
+				t.Errorf("doctor err = %v, want the failed checks counted", runErr)
+			}
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("the config error isn't reported as a check:\n%s", output)
+			}
+			if !strings.Contains(output, "Database") {
+				t.Errorf("doctor stopped before the other checks:\n%s", output)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var dueList bool
+
+// dueSummary is due's JSON output. DueNow is only filled in with --list.
+type dueSummary struct {
+	Today     int        `json:"today"`
+	Tomorrow  int        `json:"tomorrow"`
+	Next7Days int        `json:"next_7_days"`
+	DueNow    []dueEntry `json:"due_now,omitempty"`
+}
+
+// dueEntry is a note that is due now.
+type dueEntry struct {
+	Title    string    `json:"title"`
+	Filename string    `json:"filename"`
+	DueDate  time.Time `json:"due_date"`
+}
+
+var dueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "Show how many notes are due without starting a session",
+	Long: `Prints how many notes are due today, tomorrow, and over the next week.
+Use --list to also see the titles of the notes that are due right now.
+This never talks to the LLM, so it returns instantly.`,
+	Args:        cobra.NoArgs,
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		now := time.Now()
+		endOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1).Add(-time.Second)
+
+		dueToday, err := db.CountDueWithin(database, endOfToday.Sub(now))
+		if err != nil {
+			return fmt.Errorf("failed to count due notes: %w", err)
+		}
+		dueByTomorrow, err := db.CountDueWithin(database, endOfToday.AddDate(0, 0, 1).Sub(now))
+		if err != nil {
+			return fmt.Errorf("failed to count due notes: %w", err)
+		}
+		dueThisWeek, err := db.CountDueWithin(database, endOfToday.AddDate(0, 0, 6).Sub(now))
+		if err != nil {
+			return fmt.Errorf("failed to count due notes: %w", err)
+		}
+
+		summary := dueSummary{Today: dueToday, Tomorrow: dueByTomorrow - dueToday, Next7Days: dueThisWeek}
+		if dueList {
+			dueNow, err := db.CountDueNotes(database)
+			if err != nil {
+				return fmt.Errorf("failed to count due notes: %w", err)
+			}
+			notes, err := db.GetDueNotes(database, dueNow)
+			if err != nil {
+				return fmt.Errorf("failed to load due notes: %w", err)
+			}
+			sort.Slice(notes, func(i, j int) bool {
+				return notes[i].DueDate.Before(notes[j].DueDate)
+			})
+			summary.DueNow = make([]dueEntry, 0, len(notes))
+			for _, n := range notes {
+				summary.DueNow = append(summary.DueNow, dueEntry{Title: n.Title, Filename: n.Filename, DueDate: n.DueDate})
+			}
+		}
+
+		return newOutputter().Print(summary, func() {
+			fmt.Println("--- Upcoming Reviews ---")
+			fmt.Printf("  %-20s %5d\n", "Today", summary.Today)
+			fmt.Printf("  %-20s %5d\n", "Tomorrow", summary.Tomorrow)
+			fmt.Printf("  %-20s %5d\n", "Next 7 days (total)", summary.Next7Days)
+
+			if !dueList {
+				return
+			}
+			color.New(color.FgCyan, color.Bold).Printf("\n📚 Due now (%d):\n", len(summary.DueNow))
+			if len(summary.DueNow) == 0 {
+				fmt.Println("  (none)")
+			}
+			for _, n := range summary.DueNow {
+				fmt.Printf("  • %s (due %s)\n", n.Title, n.DueDate.Local().Format("2006-01-02"))
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dueCmd)
+	dueCmd.Flags().BoolVar(&dueList, "list", false, "List the titles of notes that are due now, oldest first")
+}
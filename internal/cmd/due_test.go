@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+func TestDueBuckets(t *testing.T) {
+	database := testDB(t)
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	startOfTomorrow := startOfToday.AddDate(0, 0, 1)
+	due := map[string]time.Time{
+		"overdue":     now.Add(-48 * time.Hour),
+		"later today": now.Add(startOfTomorrow.Sub(now) / 2),
+		"tomorrow":    startOfTomorrow.Add(12 * time.Hour),
+		"in 3 days":   startOfToday.AddDate(0, 0, 3).Add(12 * time.Hour),
+		"in 6 days":   startOfToday.AddDate(0, 0, 6).Add(12 * time.Hour),
+		"in 10 days":  startOfToday.AddDate(0, 0, 10),
+		"suspended":   now.Add(-time.Hour),
+		"retired":     now.Add(-time.Hour),
+	}
+	for name, date := range due {
+		n := addCardNote(t, database, name, date.Sub(now))
+		n.DueDate = date
+		if err := db.UpdateNoteSRS(database, n); err != nil {
+			t.Fatal(err)
+		}
+		switch name {
+		case "suspended":
+			if err := db.SetSuspended(database, n.ID, true); err != nil {
+				t.Fatal(err)
+			}
+		case "retired":
+			if err := db.SetRetired(database, n.ID, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var err error
+	output := captureStdout(t, func() { err = executeRoot(t, "due", "--list", "--json") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary dueSummary
+	if err := json.Unmarshal([]byte(output), &summary); err != nil {
+		t.Fatalf("due --json printed invalid JSON: %v\n%s", err, output)
+	}
+	if summary.Today != 2 || summary.Tomorrow != 1 || summary.Next7Days != 5 {
+		t.Errorf("due today/tomorrow/next 7 days = %d/%d/%d, want 2/1/5", summary.Today, summary.Tomorrow, summary.Next7Days)
+	}
+	if len(summary.DueNow) != 1 || summary.DueNow[0].Title != "overdue.md" {
+		t.Errorf("due now = %+v, want only the overdue note", summary.DueNow)
+	}
+}
+
+func TestDueListIsOldestFirst(t *testing.T) {
+	database := testDB(t)
+	addCardNote(t, database, "one day late", -24*time.Hour)
+	addCardNote(t, database, "a week late", -7*24*time.Hour)
+	addCardNote(t, database, "an hour late", -time.Hour)
+
+	var err error
+	output := captureStdout(t, func() { err = executeRoot(t, "due", "--list", "--no-color") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	week, day, hour := strings.Index(output, "a week late"), strings.Index(output, "one day late"), strings.Index(output, "an hour late")
+	if week < 0 || !(week < day && day < hour) {
+		t.Errorf("due notes aren't listed oldest first:\n%s", output)
+	}
+	if !strings.Contains(output, "Due now (3)") {
+		t.Errorf("output doesn't count the 3 notes due now:\n%s", output)
+	}
+}
@@ -0,0 +1,224 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var exportFormat string
+var exportOut string
+var exportWithQuestions bool
+
+// ankiCard is a single front/back card ready to be written for Anki's CSV importer.
+type ankiCard struct {
+	Front string
+	Back  string
+	Tags  []string
+}
+
+// exportCSVHeader lists the CSV columns in the same order as the notes table,
+// so an export can be read back field by field.
+var exportCSVHeader = []string{"id", "filename", "title", "tags", "content", "created_at", "due_date", "interval", "ease_factor", "aliases", "modified_at", "lapses", "suspended", "state", "learning_step", "retired"}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export all notes (including scheduling data) to JSON, CSV, or Anki",
+	Long: `Exports every note in the database, including its spaced repetition
+fields, so you can back up or analyze your collection externally.
+
+Formats:
+- json: An array of note objects (default)
+- csv: A header row followed by one row per note. Tags and aliases are stored as JSON arrays.
+- anki: A CSV that Anki can import, with front, back, and tags columns.
+  The front is the note title, or an AI-generated question with --with-questions.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if exportWithQuestions {
+			if err := preflightModel(); err != nil {
+				return err
+			}
+		}
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		notes, err := db.AllNotes(database)
+		if err != nil {
+			return fmt.Errorf("failed to load notes: %w", err)
+		}
+
+		var out io.Writer = os.Stdout
+		if exportOut != "" {
+			file, err := os.Create(exportOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", exportOut, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		switch exportFormat {
+		case "json":
+			err = writeNotesJSON(out, notes)
+		case "csv":
+			err = writeNotesCSV(out, notes)
+		case "anki":
+			cards, buildErr := buildAnkiCards(notes, exportWithQuestions)
+			if buildErr != nil {
+				return buildErr
+			}
+			err = writeAnkiCSV(out, cards)
+		default:
+			return fmt.Errorf("unknown export format %q (valid formats: json, csv, anki)", exportFormat)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export notes: %w", err)
+		}
+
+		if exportOut != "" {
+			fmt.Printf("✓ Exported %d notes to %s\n", len(notes), exportOut)
+		}
+		return nil
+	},
+}
+
+// writeNotesJSON writes notes as an indented JSON array.
+func writeNotesJSON(w io.Writer, notes []*note.Note) error {
+	if notes == nil {
+		notes = []*note.Note{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(notes)
+}
+
+// writeNotesCSV writes notes as CSV. encoding/csv quotes fields containing
+// commas, quotes, or newlines, so multi-line note content survives intact.
+func writeNotesCSV(w io.Writer, notes []*note.Note) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportCSVHeader); err != nil {
+		return err
+	}
+	for _, n := range notes {
+		tags := n.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		tagsJSON, err := json.Marshal(tags)
+		if err != nil {
+			return err
+		}
+		aliases := n.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+		aliasesJSON, err := json.Marshal(aliases)
+		if err != nil {
+			return err
+		}
+		record := []string{
+			strconv.Itoa(n.ID),
+			n.Filename,
+			n.Title,
+			string(tagsJSON),
+			n.Content,
+			n.CreatedAt.Format(time.RFC3339),
+			n.DueDate.Format(time.RFC3339),
+			strconv.FormatFloat(n.Interval, 'f', -1, 64),
+			strconv.FormatFloat(n.EaseFactor, 'f', -1, 64),
+			string(aliasesJSON),
+			n.ModifiedAt.Format(time.RFC3339),
+			strconv.Itoa(n.Lapses),
+			strconv.FormatBool(n.Suspended),
+			n.State,
+			strconv.Itoa(n.LearningStep),
+			strconv.FormatBool(n.Retired),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// buildAnkiCards turns notes into cards. The back is always the note summary;
+// the front is either the title or a freshly generated question.
+func buildAnkiCards(notes []*note.Note, withQuestions bool) ([]ankiCard, error) {
+	cards := make([]ankiCard, 0, len(notes))
+	for i, n := range notes {
+		front := n.Title
+		if withQuestions {
+			// Progress goes to stderr so it never mixes with CSV written to stdout.
+			fmt.Fprintf(os.Stderr, "🧠 Generating question %d of %d: %s\n", i+1, len(notes), n.Title)
+			question, err := study.GenerateQuestion(n, study.QuestionTypeMixed)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate question for %s: %w", n.Title, err)
+			}
+			front = question
+		}
+		cards = append(cards, ankiCard{
+			Front: front,
+			Back:  strings.TrimSpace(study.ExtractSummary(n.Content)),
+			Tags:  n.Tags,
+		})
+	}
+	return cards, nil
+}
+
+// writeAnkiCSV writes cards in a format Anki's "Import File" dialog understands.
+// Fields are HTML since Anki renders them as HTML, and the header lines tell
+// Anki which column holds the tags.
+func writeAnkiCSV(w io.Writer, cards []ankiCard) error {
+	if _, err := io.WriteString(w, "#separator:comma\n#html:true\n#tags column:3\n"); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	for _, card := range cards {
+		record := []string{ankiHTML(card.Front), ankiHTML(card.Back), ankiTags(card.Tags)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ankiHTML escapes text for an HTML field and keeps line breaks visible.
+func ankiHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// ankiTags joins tags with semicolons. Anki tags cannot contain spaces, so those become underscores.
+func ankiTags(tags []string) string {
+	cleaned := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(tag), "_")
+		if tag != "" {
+			cleaned = append(cleaned, tag)
+		}
+	}
+	return strings.Join(cleaned, ";")
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", "json", "Output format: json, csv, anki")
+	exportCmd.Flags().BoolVar(&exportWithQuestions, "with-questions", false, "For anki: generate one question per note to use as the card front")
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Write to this file instead of stdout")
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+func TestWriteNotesCSVEscapesContent(t *testing.T) {
+	content := "# Title\nFirst line, with a comma\n\"Quoted\" second line\n\nLast line"
+	notes := []*note.Note{{
+		ID:       7,
+		Filename: "/notes/a, b.md",
+		Title:    "A, \"B\"",
+		Tags:     []string{"go", "csv"},
+		Content:  content,
+		DueDate:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+
+	var buf bytes.Buffer
+	if err := writeNotesCSV(&buf, notes); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want a header and one note", len(records))
+	}
+	if len(records[0]) != len(exportCSVHeader) || len(records[1]) != len(exportCSVHeader) {
+		t.Fatalf("got %d and %d fields, want %d", len(records[0]), len(records[1]), len(exportCSVHeader))
+	}
+
+	field := func(name string) string {
+		for i, column := range records[0] {
+			if column == name {
+				return records[1][i]
+			}
+		}
+		t.Fatalf("no %q column", name)
+		return ""
+	}
+	if got := field("content"); got != content {
+		t.Errorf("content = %q, want %q", got, content)
+	}
+	if got := field("title"); got != notes[0].Title {
+		t.Errorf("title = %q, want %q", got, notes[0].Title)
+	}
+	if got := field("filename"); got != notes[0].Filename {
+		t.Errorf("filename = %q, want %q", got, notes[0].Filename)
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(field("tags")), &tags); err != nil || len(tags) != 2 || tags[1] != "csv" {
+		t.Errorf("tags = %q, want a JSON array of the note's tags", field("tags"))
+	}
+	if got := field("due_date"); got != "2026-01-02T03:04:05Z" {
+		t.Errorf("due_date = %q, want RFC 3339", got)
+	}
+}
+
+func TestAnkiHTML(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"plain text", "plain text"},
+		{"<b>bold</b> & more", "&lt;b&gt;bold&lt;/b&gt; &amp; more"},
+		{`"quoted" 'single'`, "&#34;quoted&#34; &#39;single&#39;"},
+		{"first line\nsecond line", "first line<br>second line"},
+		{"a < b\n\nc", "a &lt; b<br><br>c"},
+	}
+	for _, tt := range tests {
+		if got := ankiHTML(tt.text); got != tt.want {
+			t.Errorf("ankiHTML(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestAnkiTags(t *testing.T) {
+	tests := []struct {
+		tags []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"go"}, "go"},
+		{[]string{"go", "networking"}, "go;networking"},
+		{[]string{"machine learning", " spaced  out "}, "machine_learning;spaced_out"},
+		{[]string{"go", "", "  ", "sql"}, "go;sql"},
+	}
+	for _, tt := range tests {
+		if got := ankiTags(tt.tags); got != tt.want {
+			t.Errorf("ankiTags(%q) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestWriteAnkiCSV(t *testing.T) {
+	cards := []ankiCard{{Front: "What is <T>?", Back: "A type, \"generic\"\nover T", Tags: []string{"go", "type params"}}}
+	var buf bytes.Buffer
+	if err := writeAnkiCSV(&buf, cards); err != nil {
+		t.Fatal(err)
+	}
+	header, body, _ := strings.Cut(buf.String(), "#tags column:3\n")
+	if header != "#separator:comma\n#html:true\n" {
+		t.Errorf("header = %q, want Anki's file headers", header)
+	}
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	want := []string{"What is &lt;T&gt;?", "A type, &#34;generic&#34;<br>over T", "go;type_params"}
+	if len(records) != 1 || !slices.Equal(records[0], want) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
@@ -0,0 +1,190 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/spf13/cobra"
+)
+
+var graphFormat string
+var graphOut string
+
+// graphNode is a note in the link graph. Dangling nodes are link targets
+// that don't match any imported note.
+type graphNode struct {
+	Title    string   `json:"title"`
+	Dangling bool     `json:"dangling,omitempty"`
+	Links    []string `json:"links"`
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the note link graph as DOT (Graphviz) or JSON",
+	Long: `Exports the connections between your notes for visualization.
+Nodes are notes, identified by title, and edges are their [[wikilinks]].
+Links are resolved against note titles, filenames, and aliases, like the
+links command; targets that match no note appear as dangling nodes.
+
+Formats:
+- dot: A Graphviz digraph (default). Render it with e.g. 'dot -Tsvg'.
+  Dangling nodes are drawn dashed.
+- json: An adjacency list, one object per node with the titles it links to.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if graphFormat != "dot" && graphFormat != "json" {
+			return fmt.Errorf("unknown graph format %q (valid formats: dot, json)", graphFormat)
+		}
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		notes, err := db.AllNotes(database)
+		if err != nil {
+			return fmt.Errorf("failed to load notes: %w", err)
+		}
+		links, err := db.AllLinks(database)
+		if err != nil {
+			return fmt.Errorf("failed to load links: %w", err)
+		}
+		nodes := buildLinkGraph(notes, links)
+
+		var out io.Writer = os.Stdout
+		if graphOut != "" {
+			file, err := os.Create(graphOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", graphOut, err)
+			}
+			defer file.Close()
+			out = file
+		}
+
+		if graphFormat == "json" {
+			err = writeGraphJSON(out, nodes)
+		} else {
+			err = writeGraphDOT(out, nodes)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export graph: %w", err)
+		}
+
+		if graphOut != "" {
+			fmt.Printf("✓ Exported a graph of %d nodes to %s\n", len(nodes), graphOut)
+		}
+		return nil
+	},
+}
+
+// buildLinkGraph resolves each note's link targets (keyed by note ID) to note titles.
+// Targets are matched case-insensitively against titles, then filenames without
+// their extension, then aliases. Unresolved targets become dangling nodes after the notes.
+func buildLinkGraph(notes []*note.Note, links map[int][]string) []*graphNode {
+	lookup := make(map[string]string)
+	addKey := func(key, title string) {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if _, taken := lookup[key]; key != "" && !taken {
+			lookup[key] = title
+		}
+	}
+	for _, n := range notes {
+		addKey(n.Title, n.Title)
+	}
+	for _, n := range notes {
+		withoutExt := strings.TrimSuffix(n.Filename, filepath.Ext(n.Filename))
+		addKey(withoutExt, n.Title)
+		addKey(filepath.Base(withoutExt), n.Title)
+	}
+	for _, n := range notes {
+		for _, alias := range n.Aliases {
+			addKey(alias, n.Title)
+		}
+	}
+
+	var nodes []*graphNode
+	byTitle := make(map[string]*graphNode)
+	for _, n := range notes {
+		if _, seen := byTitle[n.Title]; seen {
+			continue // notes sharing a title share a node
+		}
+		node := &graphNode{Title: n.Title, Links: []string{}}
+		byTitle[n.Title] = node
+		nodes = append(nodes, node)
+	}
+
+	var dangling []*graphNode
+	danglingByKey := make(map[string]*graphNode)
+	for _, n := range notes {
+		source := byTitle[n.Title]
+		for _, target := range links[n.ID] {
+			title, ok := lookup[strings.ToLower(target)]
+			if !ok {
+				key := strings.ToLower(target)
+				node, seen := danglingByKey[key]
+				if !seen {
+					node = &graphNode{Title: target, Dangling: true, Links: []string{}}
+					danglingByKey[key] = node
+					dangling = append(dangling, node)
+				}
+				title = node.Title
+			}
+			if !slices.Contains(source.Links, title) {
+				source.Links = append(source.Links, title)
+			}
+		}
+	}
+	return append(nodes, dangling...)
+}
+
+// writeGraphJSON writes the graph as an indented JSON adjacency list.
+func writeGraphJSON(w io.Writer, nodes []*graphNode) error {
+	if nodes == nil {
+		nodes = []*graphNode{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(nodes)
+}
+
+// writeGraphDOT writes the graph as a Graphviz digraph.
+func writeGraphDOT(w io.Writer, nodes []*graphNode) error {
+	var b strings.Builder
+	b.WriteString("digraph notes {\n")
+	for _, node := range nodes {
+		if node.Dangling {
+			fmt.Fprintf(&b, "  %s [style=dashed];\n", dotQuote(node.Title))
+		} else {
+			fmt.Fprintf(&b, "  %s;\n", dotQuote(node.Title))
+		}
+	}
+	for _, node := range nodes {
+		for _, target := range node.Links {
+			fmt.Fprintf(&b, "  %s -> %s;\n", dotQuote(node.Title), dotQuote(target))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote returns s as a quoted DOT identifier.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format: dot, json")
+	graphCmd.Flags().StringVarP(&graphOut, "out", "o", "", "Write to this file instead of stdout")
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// graphTestNotes returns three linked notes: Alpha links to Beta by title and to a
+// missing note, Beta back to Alpha, and Gamma to Beta by alias and by filename.
+func graphTestNotes() ([]*note.Note, map[int][]string) {
+	notes := []*note.Note{
+		{ID: 1, Title: "Alpha", Filename: "/notes/alpha.md"},
+		{ID: 2, Title: "Beta", Filename: "/notes/b-file.markdown", Aliases: []string{"Second"}},
+		{ID: 3, Title: "Gamma", Filename: "/notes/gamma.md"},
+	}
+	links := map[int][]string{
+		1: {"beta", "Missing", "missing"},
+		2: {"alpha", "Alpha"},
+		3: {"second", "b-file"},
+	}
+	return notes, links
+}
+
+func TestBuildLinkGraph(t *testing.T) {
+	notes, links := graphTestNotes()
+	nodes := buildLinkGraph(notes, links)
+	want := []graphNode{
+		{Title: "Alpha", Links: []string{"Beta", "Missing"}},
+		{Title: "Beta", Links: []string{"Alpha"}},
+		{Title: "Gamma", Links: []string{"Beta"}},
+		{Title: "Missing", Dangling: true, Links: []string{}},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("got %d nodes, want %d", len(nodes), len(want))
+	}
+	for i, w := range want {
+		got := nodes[i]
+		if got.Title != w.Title || got.Dangling != w.Dangling || !slices.Equal(got.Links, w.Links) {
+			t.Errorf("node %d = %+v, want %+v", i, *got, w)
+		}
+	}
+}
+
+func TestBuildLinkGraphMergesSharedTitles(t *testing.T) {
+	notes := []*note.Note{
+		{ID: 1, Title: "Index", Filename: "/a/index.md"},
+		{ID: 2, Title: "Index", Filename: "/b/index.md"},
+		{ID: 3, Title: "Target", Filename: "/target.md"},
+	}
+	nodes := buildLinkGraph(notes, map[int][]string{1: {"Target"}, 2: {"target"}})
+	if len(nodes) != 2 || !slices.Equal(nodes[0].Links, []string{"Target"}) {
+		t.Errorf("nodes = %+v, want one Index node with a single edge to Target", nodes)
+	}
+}
+
+func TestWriteGraphDOT(t *testing.T) {
+	notes, links := graphTestNotes()
+	notes[2].Title = `Say "hi"`
+	var buf bytes.Buffer
+	if err := writeGraphDOT(&buf, buildLinkGraph(notes, links)); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph notes {
+  "Alpha";
+  "Beta";
+  "Say \"hi\"";
+  "Missing" [style=dashed];
+  "Alpha" -> "Beta";
+  "Alpha" -> "Missing";
+  "Beta" -> "Alpha";
+  "Say \"hi\"" -> "Beta";
+}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("DOT output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteGraphJSON(t *testing.T) {
+	notes, links := graphTestNotes()
+	var buf bytes.Buffer
+	if err := writeGraphJSON(&buf, buildLinkGraph(notes, links)); err != nil {
+		t.Fatal(err)
+	}
+	var nodes []graphNode
+	if err := json.Unmarshal(buf.Bytes(), &nodes); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if len(nodes) != 4 || !slices.Equal(nodes[0].Links, []string{"Beta", "Missing"}) || !nodes[3].Dangling {
+		t.Errorf("nodes = %+v", nodes)
+	}
+
+	buf.Reset()
+	if err := writeGraphJSON(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Errorf("an empty graph = %q, %v; want []", buf.String(), err)
+	}
+}
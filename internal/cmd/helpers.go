@@ -2,12 +2,23 @@
 package cmd
 
 import (
+	"bufio"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
 	"github.com/soyomarvaldezg/neuron-cli/internal/note"
 	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
 )
 
 // ProcessSpecialCommand checks if the user input is a special command
@@ -23,12 +34,7 @@ func ProcessSpecialCommand(input string, currentNote *note.Note, messages *[]stu
 		// Show the full note
 		fmt.Println("\n📖 Current Note Content:")
 		fmt.Println("-----------------------------------------------------------")
-		rendered, err := renderMarkdown(currentNote.Content)
-		if err != nil {
-			fmt.Println(currentNote.Content)
-		} else {
-			fmt.Println(rendered)
-		}
+		printMarkdown(currentNote.Content)
 		fmt.Println("-----------------------------------------------------------")
 		return true, true, nil
 
@@ -41,14 +47,12 @@ func ProcessSpecialCommand(input string, currentNote *note.Note, messages *[]stu
 		}
 		*messages = append(*messages, explainMsg)
 
-		aiResponse, err := study.SendChatMessage(*messages)
+		aiResponse, err := chatReply(*messages, color.New(color.FgMagenta), "🧠 Explanation: ")
 		if err != nil {
 			return true, true, err
 		}
 		*messages = append(*messages, aiResponse)
-
-		aiColor := color.New(color.FgMagenta)
-		aiColor.Printf("\n🧠 Explanation: %s\n\n", aiResponse.Content)
+		fmt.Println()
 		return true, true, nil
 
 	case input == "help" || input == "?":
@@ -64,3 +68,299 @@ func ProcessSpecialCommand(input string, currentNote *note.Note, messages *[]stu
 
 	return false, true, nil
 }
+
+// noStream is set by --no-stream; streamReplies is the resolved setting, which
+// the config file's stream key can also turn off.
+var (
+	noStream      bool
+	streamReplies = true
+)
+
+// chatReply sends the conversation and prints the reply after label in c, typing it
+// out as it is generated unless streaming is off.
+func chatReply(messages []study.OllamaMessage, c *color.Color, label string) (study.OllamaMessage, error) {
+	if !streamReplies {
+		reply, err := study.SendChatMessage(messages)
+		if err != nil {
+			return study.OllamaMessage{}, err
+		}
+		c.Printf("\n%s%s\n", label, reply.Content)
+		return reply, nil
+	}
+	c.Printf("\n%s", label)
+	c.SetWriter(os.Stdout)
+	reply, err := study.SendChatMessageStream(messages, os.Stdout)
+	c.UnsetWriter(os.Stdout)
+	fmt.Println()
+	return reply, err
+}
+
+// readLine reads one line of input without its trailing newline.
+// It returns io.EOF once input is exhausted, e.g. when piped input runs out.
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line != "" {
+		// A final line without a newline still counts; the next read reports EOF.
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// ratingShortcuts maps the accepted rating inputs to ratings.
+var ratingShortcuts = map[string]int{
+	"1": study.RatingAgain, "a": study.RatingAgain, "again": study.RatingAgain,
+	"2": study.RatingGood, "g": study.RatingGood, "good": study.RatingGood,
+	"3": study.RatingEasy, "e": study.RatingEasy, "easy": study.RatingEasy,
+}
+
+// ratingRetire is what readRating returns when the user chooses to retire the note.
+const ratingRetire = -1
+
+// readRating prompts until the user enters a valid recall rating: 1-3, or a/g/e,
+// and also r to retire the note when retire is true.
+// It returns io.EOF if input runs out before a rating is given.
+func readRating(reader *bufio.Reader, retire bool) (int, error) {
+	prompt, invalid := "1/a=Again, 2/g=Good, 3/e=Easy", "1, 2, 3 or a, g, e"
+	if retire {
+		prompt, invalid = prompt+", r=Retire", invalid+", or r"
+	}
+	for {
+		fmt.Printf("\nHow well did you recall this? (%s): ", prompt)
+		input, err := readLine(reader)
+		if err != nil {
+			fmt.Println()
+			return 0, err
+		}
+		input = strings.ToLower(strings.TrimSpace(input))
+		if rating, ok := ratingShortcuts[input]; ok {
+			return rating, nil
+		}
+		if retire && (input == "r" || input == "retire") {
+			return ratingRetire, nil
+		}
+		fmt.Printf("Invalid input. Please enter %s.\n", invalid)
+	}
+}
+
+// saveRating applies a recall rating to the note's schedule and saves it,
+// warning when the note has become (or already is) a leech. think is how long
+// the question was shown before the answer was revealed, or zero when the
+// review wasn't timed, as in quiz and self-test.
+func saveRating(database *sql.DB, n *note.Note, rating int, think time.Duration) error {
+	headline, advice, err := rateNote(database, n, rating, think)
+	if err != nil {
+		return err
+	}
+	if headline != "" {
+		color.New(color.FgYellow, color.Bold).Printf("\n%s\n", headline)
+		fmt.Println(advice)
+	}
+	return nil
+}
+
+// rateNote does the work of saveRating without printing, returning the leech
+// warning instead (empty unless the note is a leech rated "Again") so the TUI
+// can show it on its own screen.
+func rateNote(database *sql.DB, n *note.Note, rating int, think time.Duration) (headline, advice string, err error) {
+	study.UpdateSRSData(n, rating)
+	newLeech := study.MarkLeech(n)
+	if err := db.UpdateNoteSRS(database, n); err != nil {
+		return "", "", fmt.Errorf("failed to update note schedule: %w", err)
+	}
+	// The log only feeds statistics, so a failure shouldn't cost the user their review.
+	if err := db.LogReview(database, n.ID, rating, think, time.Now()); err != nil {
+		log.Printf("Error logging review of %s: %v", n.Title, err)
+	}
+	if rating != study.RatingAgain || !study.IsLeech(n) {
+		return "", "", nil
+	}
+	if newLeech {
+		headline = fmt.Sprintf("🩸 You've forgotten this note %d times, so it is now tagged '%s'.", n.Lapses, study.LeechTag)
+	} else {
+		headline = fmt.Sprintf("🩸 This note is a leech (%d lapses).", n.Lapses)
+	}
+	return headline, "   Consider rewriting it: split it up, add an example, or link it to notes you know well.", nil
+}
+
+// lookupNote finds the note for a topic given on the command line. When several
+// notes match equally well, the user picks one (see chooseNote). When nothing is
+// found or picked, it says so and returns a nil note without an error, so commands
+// can simply stop.
+func lookupNote(database *sql.DB, topic string) (*note.Note, error) {
+	n, err := db.GetNoteByTitleOrFilename(database, topic)
+	var ambiguous *db.AmbiguousMatchError
+	switch {
+	case err == sql.ErrNoRows:
+		fmt.Printf("Sorry, I couldn't find a note matching '%s'.\n", topic)
+		return nil, nil
+	case errors.As(err, &ambiguous):
+		return chooseNote(topic, ambiguous.Matches)
+	case err != nil:
+		return nil, err
+	}
+	return n, nil
+}
+
+// topicIndex picks among equally good matches for a topic without prompting (1-based).
+var topicIndex int
+
+// addIndexFlag registers --index on a command that looks up a note by topic.
+func addIndexFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&topicIndex, "index", 0, "When the topic matches several notes equally well, use the Nth one instead of asking")
+}
+
+// chooseNote picks one of several equally good matches for topic: the one named
+// by --index, or else the one the user chooses from a numbered list, by number or
+// exact title. It returns nil if the user cancels.
+func chooseNote(topic string, matches []*note.Note) (*note.Note, error) {
+	if topicIndex > 0 {
+		if topicIndex > len(matches) {
+			return nil, fmt.Errorf("--index %d is out of range: '%s' matches %d notes", topicIndex, topic, len(matches))
+		}
+		return matches[topicIndex-1], nil
+	}
+	if jsonOutput {
+		return nil, fmt.Errorf("'%s' matches %d notes; pick one with --index", topic, len(matches))
+	}
+
+	fmt.Printf("'%s' matches several notes:\n", topic)
+	for i, match := range matches {
+		fmt.Printf("  %d. %s\n", i+1, match.Title)
+	}
+	for {
+		fmt.Printf("Which one? (1-%d, or Enter to cancel): ", len(matches))
+		// Read stdin unbuffered so the command's own reader still sees later input.
+		line, err := readUnbufferedLine(os.Stdin)
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil {
+				fmt.Println()
+			}
+			fmt.Println("Cancelled.")
+			return nil, nil
+		}
+		if choice, convErr := strconv.Atoi(line); convErr == nil && choice >= 1 && choice <= len(matches) {
+			return matches[choice-1], nil
+		}
+		for _, match := range matches {
+			if strings.EqualFold(match.Title, line) {
+				return match, nil
+			}
+		}
+		if err != nil {
+			fmt.Println("\nCancelled.")
+			return nil, nil
+		}
+		fmt.Printf("Please enter a number from 1 to %d.\n", len(matches))
+	}
+}
+
+// readUnbufferedLine reads one line a byte at a time, leaving the rest of r unread.
+func readUnbufferedLine(r io.Reader) (string, error) {
+	var line []byte
+	b := make([]byte, 1)
+	for {
+		n, err := r.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, b[0])
+		}
+		if err != nil {
+			return string(line), err
+		}
+	}
+}
+
+// untilDue describes how long until a note is due: minutes or hours for short
+// learning steps, otherwise whole days.
+func untilDue(due time.Time) string {
+	return formatWait(time.Until(due))
+}
+
+// formatWait renders a wait in minutes, hours, or days, rounded up.
+func formatWait(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d minute(s)", int(math.Ceil(d.Minutes())))
+	case d < 12*time.Hour:
+		return fmt.Sprintf("%d hour(s)", int(math.Ceil(d.Hours())))
+	}
+	return fmt.Sprintf("%d day(s)", int(math.Ceil(d.Hours()/24)))
+}
+
+// showComparison generates the AI answer to question, then shows it next to the
+// user's answer with feedback and, when the model provides one, a score.
+func showComparison(question, userInput string, n *note.Note) error {
+	fmt.Println("\n🤖 Generating AI answer for comparison...")
+	aiAnswer, err := study.GenerateAnswer(question, n, study.AnswerMedium)
+	if err != nil {
+		return fmt.Errorf("failed to generate AI answer: %w", err)
+	}
+
+	fmt.Println("\n🔍 Analyzing your answer...")
+	comparison, err := study.CompareAnswers(userInput, aiAnswer, question)
+	if err != nil {
+		return fmt.Errorf("failed to compare answers: %w", err)
+	}
+
+	// Scoring is a bonus; feedback is still shown if the model can't produce a score
+	score, scoreReason, scoreErr := study.ScoreAnswer(userInput, aiAnswer, question)
+
+	fmt.Println("\n" + strings.Repeat("=", 60))
+	fmt.Println("📊 COMPARISON RESULTS")
+	fmt.Println(strings.Repeat("=", 60))
+
+	userColor := color.New(color.FgYellow)
+	aiColor := color.New(color.FgMagenta)
+	feedbackColor := color.New(color.FgGreen)
+
+	fmt.Print("\n💭 Your Answer: ")
+	userColor.Println(userInput)
+
+	fmt.Print("\n🤖 AI Answer: ")
+	aiColor.Println(aiAnswer)
+
+	fmt.Print("\n📝 Feedback: ")
+	feedbackColor.Println(comparison)
+
+	if scoreErr == nil {
+		scoreColor := color.New(color.FgCyan, color.Bold)
+		fmt.Print("\n🎯 Score: ")
+		scoreColor.Printf("%d/100", score)
+		if scoreReason != "" {
+			fmt.Printf(" - %s", scoreReason)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println(strings.Repeat("=", 60))
+	return nil
+}
+
+// printSessionSummary shows how many cards were reviewed, the rating breakdown, and the time spent.
+func printSessionSummary(stats *study.SessionStats) {
+	if stats.Reviewed() == 0 {
+		return
+	}
+	color.New(color.FgCyan, color.Bold).Println("\n📊 Session Summary")
+	fmt.Printf("  %-10s %5d\n", "Reviewed", stats.Reviewed())
+	fmt.Printf("  %-10s %5d\n", "Again", stats.Again)
+	fmt.Printf("  %-10s %5d\n", "Good", stats.Good)
+	fmt.Printf("  %-10s %5d\n", "Easy", stats.Easy)
+	fmt.Printf("  %-10s %5s\n", "Time", stats.Elapsed())
+}
+
+// saveConversation writes a chat transcript to path when one was requested.
+// It runs when a session ends, so failures are logged rather than returned.
+func saveConversation(path string, messages *[]study.OllamaMessage) {
+	if path == "" {
+		return
+	}
+	if err := study.SaveTranscript(path, *messages); err != nil {
+		log.Printf("Error saving conversation to %s: %v", path, err)
+		return
+	}
+	fmt.Printf("💾 Conversation saved to %s\n", path)
+}
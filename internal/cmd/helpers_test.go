@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+func TestReadLine(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("first\r\nsecond\n\nlast"))
+	for _, want := range []string{"first", "second", "", "last"} {
+		line, err := readLine(reader)
+		if err != nil || line != want {
+			t.Fatalf("readLine() = %q, %v; want %q, nil", line, err, want)
+		}
+	}
+	// Once input is exhausted every read reports EOF.
+	for range 3 {
+		if line, err := readLine(reader); err != io.EOF || line != "" {
+			t.Fatalf("readLine() after the last line = %q, %v; want \"\", io.EOF", line, err)
+		}
+	}
+}
+
+func TestReadLineClosedReader(t *testing.T) {
+	r, w := io.Pipe()
+	w.Close()
+	if line, err := readLine(bufio.NewReader(r)); err != io.EOF || line != "" {
+		t.Errorf("readLine() on a closed reader = %q, %v; want \"\", io.EOF", line, err)
+	}
+}
+
+// readRatingFrom runs readRating on input, failing if it doesn't return promptly.
+func readRatingFrom(t *testing.T, input string, retire bool) (int, string, error) {
+	t.Helper()
+	var rating int
+	var err error
+	output := captureStdout(t, func() {
+		done := make(chan struct{})
+		go func() {
+			rating, err = readRating(bufio.NewReader(strings.NewReader(input)), retire)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("readRating(%q) is still prompting", input)
+		}
+	})
+	return rating, output, err
+}
+
+func TestReadRating(t *testing.T) {
+	tests := []struct {
+		input  string
+		retire bool
+		want   int
+	}{
+		{"1\n", false, study.RatingAgain},
+		{"a\n", false, study.RatingAgain},
+		{"Again\n", false, study.RatingAgain},
+		{" 2 \n", false, study.RatingGood},
+		{"G\n", false, study.RatingGood},
+		{"3\n", false, study.RatingEasy},
+		{"e", false, study.RatingEasy},
+		{"r\n", true, ratingRetire},
+		{"retire\n", true, ratingRetire},
+		{"r\n2\n", false, study.RatingGood},
+		{"5\n\nx\ne\n", false, study.RatingEasy},
+	}
+	for _, tt := range tests {
+		rating, _, err := readRatingFrom(t, tt.input, tt.retire)
+		if err != nil || rating != tt.want {
+			t.Errorf("readRating(%q, %v) = %d, %v; want %d", tt.input, tt.retire, rating, err, tt.want)
+		}
+	}
+}
+
+func TestReadRatingStopsAtEOF(t *testing.T) {
+	for _, input := range []string{"", "5\nnope\n"} {
+		_, output, err := readRatingFrom(t, input, false)
+		if err != io.EOF {
+			t.Errorf("readRating(%q) = %v, want io.EOF", input, err)
+		}
+		// One "Invalid input" per bad line, and none for the end of input.
+		if got, want := strings.Count(output, "Invalid input"), strings.Count(input, "\n"); got != want {
+			t.Errorf("readRating(%q) printed %d invalid-input messages, want %d:\n%s", input, got, want, output)
+		}
+	}
+}
+
+func TestInteractiveCommandsEndAtEOF(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmd   *cobra.Command
+		phase string
+		// Each prefix of the script leaves the command waiting for more input.
+		script []string
+	}{
+		{"reflect", reflectCmd, "", []string{"help", "note", "", "my explanation", "y", "my response", "y", "1", "refined", "x", "y", "2", "an aspect"}},
+		{"self-test", selfTestCmd, "", []string{"help", "note", "hint", "skip", "", "my answer", "x", "2", "y", "another answer"}},
+		{"deep-dive", deepDiveCmd, "", []string{"help", "tell me more", "and more"}},
+		{"teach", teachCmd, "", []string{"help", "my explanation", "more detail"}},
+		{"workflow foundational", workflowCmd, "foundational", []string{"6", "5", "9", "1", "", "2", "my answer", "y", "hint"}},
+		{"workflow verification", workflowCmd, "verification", []string{"7", "6", "4", "help", "my explanation", "y", "my response", "y", "2", "an aspect"}},
+		{"workflow extension", workflowCmd, "extension", []string{"7", "6", "1"}},
+	}
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/eof.md", "# EOF\n\n## Summary\nInput ends.\n")
+	useFakeProvider(t, "80 - a reply")
+	t.Cleanup(func() { resetFlags(workflowCmd) })
+
+	for _, tt := range tests {
+		if tt.phase != "" {
+			if err := tt.cmd.Flags().Set("phase", tt.phase); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i := range len(tt.script) + 1 {
+			input := strings.Join(tt.script[:i], "\n")
+			withStdin(t, input)
+			var err error
+			output := captureStdout(t, func() {
+				done := make(chan struct{})
+				go func() {
+					err = tt.cmd.RunE(tt.cmd, []string{n.Title})
+					close(done)
+				}()
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("%s is still running after input %q ran out", tt.name, input)
+				}
+			})
+			if err != nil {
+				t.Errorf("%s with input %q returned %v", tt.name, input, err)
+			}
+			if !strings.Contains(output, "Input closed") {
+				t.Errorf("%s with input %q didn't report the end of input:\n%s", tt.name, input, output)
+			}
+		}
+	}
+}
+
+// listedMatch returns the title chooseNote listed as choice number i.
+func listedMatch(t *testing.T, output string, i int) string {
+	t.Helper()
+	prefix := fmt.Sprintf("  %d. ", i)
+	for _, line := range strings.Split(output, "\n") {
+		if title, ok := strings.CutPrefix(line, prefix); ok {
+			return title
+		}
+	}
+	t.Fatalf("choice %d isn't listed:\n%s", i, output)
+	return ""
+}
+
+// addIndexNotes adds notes titled by their base names. Unlike addTestNote it
+// doesn't look them up again, which would fail once titles tie.
+func addIndexNotes(t *testing.T, database *sql.DB, filenames ...string) {
+	t.Helper()
+	for _, filename := range filenames {
+		n := &note.Note{Filename: filename, Title: filepath.Base(filename), Content: filename, DueDate: time.Now()}
+		if _, err := db.InsertNote(database, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLookupNoteAmbiguous(t *testing.T) {
+	database := testDB(t)
+	addIndexNotes(t, database, "/notes/a/Index.md", "/notes/b/index.md", "/notes/indexing.md")
+
+	tests := []struct {
+		name    string
+		input   string
+		want    int // the listed choice that is picked, or 0 for none
+		retries int
+	}{
+		{"by number", "2\n", 2, 0},
+		{"by exact title", "index.md\n", 1, 0},
+		{"after invalid choices", "x\n0\n3\n1\n", 1, 3},
+		{"cancelled with Enter", "\n", 0, 0},
+		{"cancelled at end of input", "", 0, 0},
+		{"invalid choice at end of input", "7", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withStdin(t, tt.input)
+			var n *note.Note
+			var err error
+			output := captureStdout(t, func() { n, err = lookupNote(database, "index.md") })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(output, "'index.md' matches several notes:") || strings.Contains(output, "indexing.md") {
+				t.Errorf("prompt doesn't list just the two equal matches:\n%s", output)
+			}
+			if got := strings.Count(output, "Please enter a number from 1 to 2."); got != tt.retries {
+				t.Errorf("asked again %d times, want %d:\n%s", got, tt.retries, output)
+			}
+			if tt.want == 0 {
+				if n != nil || !strings.Contains(output, "Cancelled.") {
+					t.Errorf("lookupNote() = %v, want a cancelled lookup:\n%s", n, output)
+				}
+				return
+			}
+			if want := listedMatch(t, output, tt.want); n == nil || n.Title != want {
+				t.Errorf("lookupNote() = %v, want %q", n, want)
+			}
+		})
+	}
+}
+
+func TestLookupNoteLeavesLaterInput(t *testing.T) {
+	database := testDB(t)
+	addIndexNotes(t, database, "/notes/a/Index.md", "/notes/b/index.md")
+
+	withStdin(t, "1\nmy answer\n")
+	captureStdout(t, func() {
+		if n, err := lookupNote(database, "index.md"); err != nil || n == nil {
+			t.Fatalf("lookupNote() = %v, %v; want the first match", n, err)
+		}
+	})
+	if line, err := readLine(bufio.NewReader(os.Stdin)); err != nil || line != "my answer" {
+		t.Errorf("the session's reader got %q, %v; want the input after the choice", line, err)
+	}
+}
+
+func TestChooseNoteIndex(t *testing.T) {
+	matches := []*note.Note{{Title: "Index"}, {Title: "index"}}
+	t.Cleanup(func() { topicIndex, jsonOutput = 0, false })
+
+	topicIndex = 2
+	if n, err := chooseNote("index", matches); err != nil || n != matches[1] {
+		t.Errorf("--index 2 chose %v, %v; want the second match", n, err)
+	}
+	topicIndex = 3
+	if _, err := chooseNote("index", matches); err == nil || !strings.Contains(err.Error(), "out of range") {
+		t.Errorf("--index 3 of 2 returned %v, want an out of range error", err)
+	}
+	// With --json there is nobody to ask.
+	topicIndex, jsonOutput = 0, true
+	if _, err := chooseNote("index", matches); err == nil || !strings.Contains(err.Error(), "--index") {
+		t.Errorf("--json without --index returned %v, want an error pointing at --index", err)
+	}
+}
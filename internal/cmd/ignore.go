@@ -0,0 +1,153 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreFileName is the gitignore-style file read from the root of an import.
+const ignoreFileName = ".neuronignore"
+
+// defaultIgnoreDirs are directory names never imported unless the config file sets its own list.
+var defaultIgnoreDirs = []string{".obsidian", ".trash", ".git", "node_modules"}
+
+// ignoreRule is one pattern from a .neuronignore file.
+type ignoreRule struct {
+	pattern *regexp.Regexp
+	negate  bool // "!pattern" re-includes what earlier rules excluded
+	dirOnly bool // "pattern/" only matches directories
+}
+
+// importIgnore decides which paths under an import root are skipped: dotfiles,
+// directories named in dirs, and paths matching the .neuronignore rules.
+type importIgnore struct {
+	dirs  map[string]bool
+	rules []ignoreRule
+}
+
+// loadImportIgnore reads root/.neuronignore, if present, and combines it with the ignored directory names.
+func loadImportIgnore(root string, dirs []string) (*importIgnore, error) {
+	ignore := &importIgnore{dirs: make(map[string]bool)}
+	for _, dir := range dirs {
+		ignore.dirs[dir] = true
+	}
+	file, err := os.Open(filepath.Join(root, ignoreFileName))
+	if os.IsNotExist(err) {
+		return ignore, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			ignore.rules = append(ignore.rules, rule)
+		}
+	}
+	return ignore, scanner.Err()
+}
+
+// parseIgnoreRule parses one gitignore-style line. Blank lines, # comments and
+// patterns that can't be compiled (such as the range "[z-a]") yield false.
+// Patterns without a slash match a name at any depth; patterns with one are anchored
+// to the import root. "*" and "?" stay within a path segment and "**" spans segments.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+	var rule ignoreRule
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignoreRule{}, false
+	}
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+	pattern, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.pattern = pattern
+	return rule, true
+}
+
+// globToRegexp translates a gitignore glob into a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(glob[i:], ']'); end > 1 {
+				class := glob[i+1 : i+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + class + "]")
+				i += end
+				continue
+			}
+			b.WriteString(regexp.QuoteMeta("["))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// skip reports whether the entry at rel (a slash-separated path relative to the
+// import root) is ignored by itself, without looking at its parent directories.
+func (ig *importIgnore) skip(rel string, isDir bool) bool {
+	name := rel[strings.LastIndex(rel, "/")+1:]
+	if strings.HasPrefix(name, ".") || (isDir && ig.dirs[name]) {
+		return true
+	}
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.pattern.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// covers reports whether a file at rel is ignored, either itself or through one of
+// its parent directories. The walk skips ignored directories wholesale, so this is
+// what tells cleanup that a file inside one was ignored rather than deleted.
+func (ig *importIgnore) covers(rel string) bool {
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if ig.skip(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return ig.skip(rel, false)
+}
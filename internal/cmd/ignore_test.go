@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newImportIgnore builds the ignore rules of a .neuronignore holding lines.
+func newImportIgnore(t *testing.T, dirs []string, lines ...string) *importIgnore {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ignoreFileName), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := loadImportIgnore(root, dirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ignore
+}
+
+func TestImportIgnoreSkip(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		rel   string
+		isDir bool
+		want  bool
+	}{
+		{"no rules", nil, "note.md", false, false},
+		{"dotfile", nil, "sub/.hidden.md", false, true},
+		{"ignored directory name", nil, "sub/node_modules", true, true},
+		{"ignored directory name is only for directories", nil, "node_modules", false, false},
+
+		{"name at any depth", []string{"*.tmp.md"}, "a/b/draft.tmp.md", false, true},
+		{"star stays in a segment", []string{"docs/*.md"}, "docs/a/b.md", false, false},
+		{"star in an anchored pattern", []string{"docs/*.md"}, "docs/b.md", false, true},
+		{"question mark is one character", []string{"?.md"}, "a.md", false, true},
+		{"question mark isn't two", []string{"?.md"}, "ab.md", false, false},
+		{"character class", []string{"[ab].md"}, "b.md", false, true},
+		{"negated character class", []string{"[!ab].md"}, "b.md", false, false},
+		{"regexp characters are literal", []string{"a+b.md"}, "a+b.md", false, true},
+		{"regexp characters don't match otherwise", []string{"a+b.md"}, "aab.md", false, false},
+
+		{"leading slash anchors", []string{"/top.md"}, "top.md", false, true},
+		{"leading slash doesn't match deeper", []string{"/top.md"}, "sub/top.md", false, false},
+		{"slash inside anchors", []string{"sub/top.md"}, "other/sub/top.md", false, false},
+
+		{"directory rule matches a directory", []string{"drafts/"}, "drafts", true, true},
+		{"directory rule at any depth", []string{"drafts/"}, "a/drafts", true, true},
+		{"directory rule skips files", []string{"drafts/"}, "drafts", false, false},
+
+		{"double star prefix at the root", []string{"**/archive"}, "archive", true, true},
+		{"double star prefix deep", []string{"**/archive"}, "a/b/archive", true, true},
+		{"double star in the middle", []string{"docs/**/*.md"}, "docs/a/b/c.md", false, true},
+		{"double star in the middle, no directories", []string{"docs/**/*.md"}, "docs/c.md", false, true},
+		{"double star suffix", []string{"logs/**"}, "logs/a/b.md", false, true},
+		{"double star suffix needs the prefix", []string{"logs/**"}, "other/logs.md", false, false},
+
+		{"negation re-includes", []string{"*.md", "!keep.md"}, "keep.md", false, false},
+		{"negation leaves the rest", []string{"*.md", "!keep.md"}, "other.md", false, true},
+		{"later rule wins", []string{"!keep.md", "*.md"}, "keep.md", false, true},
+
+		{"comments and blank lines", []string{"# *.md", "", "   "}, "note.md", false, false},
+		{"invalid pattern is dropped", []string{"[z-a].md"}, "z.md", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ignore := newImportIgnore(t, defaultIgnoreDirs, tt.rules...)
+			if got := ignore.skip(tt.rel, tt.isDir); got != tt.want {
+				t.Errorf("skip(%q, dir %v) with %q = %v, want %v", tt.rel, tt.isDir, tt.rules, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportIgnoreCovers(t *testing.T) {
+	ignore := newImportIgnore(t, defaultIgnoreDirs, "drafts/", "private/*", "!private/shared")
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"notes/a.md", false},
+		{"drafts/a.md", true},
+		{"notes/drafts/deep/a.md", true},
+		{"private/a.md", true},
+		{"private/shared/a.md", false},
+		{".obsidian/workspace.md", true},
+		{"sub/.trash/old.md", true},
+	}
+	for _, tt := range tests {
+		if got := ignore.covers(tt.rel); got != tt.want {
+			t.Errorf("covers(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestImportSkipsIgnoredFiles(t *testing.T) {
+	testDB(t)
+	dir := t.TempDir()
+	kept := writeNoteFile(t, dir, "kept.md", "# Kept\nbody")
+	writeNoteFile(t, dir, "drafts/draft.md", "# Draft\nbody")
+	writeNoteFile(t, dir, "scratch.md", "# Scratch\nbody")
+	writeNoteFile(t, dir, ignoreFileName, "drafts/\nscratch.md\n")
+	if err := runImport(t, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	canonical, err := canonicalPath(kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filenames := storedFilenames(t); len(filenames) != 1 || !filenames[canonical] {
+		t.Errorf("stored %v, want only %s", filenames, canonical)
+	}
+}
@@ -2,95 +2,525 @@
 package cmd
 
 import (
+	"bufio"
 	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
 	"github.com/soyomarvaldezg/neuron-cli/internal/db"
 	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
 	"github.com/spf13/cobra"
 )
 
+var importExtensions string
+var importWorkers int
+var importDryRun bool
+var importForce bool
+var importSince string
+var importAddTags []string
+var importFailOnDuplicates bool
+var importWatch bool
+
+// defaultWarnSizeKB is the note size above which import suggests splitting a note.
+const defaultWarnSizeKB = 20
+
 var importCmd = &cobra.Command{
 	Use:   "import [path]",
-	Short: "Import and sync notes from a directory",
+	Short: "Import and sync notes from a directory or a single file",
 	Long: `Imports notes from a specified directory of Markdown files.
 The command will intelligently sync your notes, adding new ones,
-updating modified ones, and removing deleted ones based on filename.`,
-	Args: cobra.ExactArgs(1),
+updating modified ones, and removing deleted ones based on filename. Files are
+matched by their absolute path with symlinks resolved, so the same folder
+imported through a symlink or a relative path updates the same notes.
+Before removing anything it asks for confirmation; pass --force (or --yes) to skip the prompt.
+Use --ext to choose which file extensions count as notes (default: md,markdown).
+Use --since to parse only files modified recently: a duration (24h, 7d), a date
+(2026-01-31), or "last" for the previous import. Older files are still counted
+as present, so they are never removed.
+
+Dotfiles, dot-directories, and directories named .obsidian, .trash, .git, or
+node_modules (configurable as import.ignore_dirs) are skipped. A .neuronignore
+file at the import root adds gitignore-style patterns. Notes that are ignored
+are left in the database rather than removed.
+
+Notes over 20 KB (configurable as import.warn_size_kb) are still imported, with
+a warning: the model sees too much of them at once for good questions.
+
+Notes that share a title (ignoring case) are reported, since looking them up by
+title is ambiguous. Pass --fail-on-duplicates to abort the sync instead.
+
+Use --add-tag (repeatable, or comma-separated) to tag every imported note, on top
+of the tags in its frontmatter. Notes whose content is unchanged are left as they are.
+
+Use --watch to keep syncing after the import: changed files are re-imported and
+deleted ones removed (without asking) as they happen, until Ctrl-C. A save is
+synced half a second after the last file event, so a burst of writes to one file
+is imported once.
+
+When the path is a file rather than a directory, only that note is added or
+updated. Nothing is removed, and --since, --watch and .neuronignore don't apply.
+
+Without a path, import syncs $NEURON_NOTES_DIR or the notes_dir set in the config file.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		notesPath := args[0]
-		fmt.Printf("Starting import from directory: %s\n", notesPath)
+		if importWatch && importDryRun {
+			return fmt.Errorf("--watch can't be combined with --dry-run")
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		notesPath, err := importNotesDir(args, cfg)
+		if err != nil {
+			return err
+		}
+		// Notes are stored under their canonical path, so importing the same
+		// folder through a symlink or a relative path finds the same rows.
+		if notesPath, err = canonicalPath(notesPath); err != nil {
+			return fmt.Errorf("cannot read the notes directory: %w", err)
+		}
+		info, err := os.Stat(notesPath)
+		if err != nil {
+			return fmt.Errorf("cannot read the notes directory: %w", err)
+		}
+		singleFile := !info.IsDir()
+		if singleFile && (importWatch || importSince != "") {
+			return fmt.Errorf("--watch and --since only apply when importing a directory")
+		}
+		if singleFile {
+			fmt.Printf("Importing file: %s\n", notesPath)
+		} else {
+			fmt.Printf("Starting import from directory: %s\n", notesPath)
+		}
 
 		// Get a database connection
 		database, err := db.GetDB()
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
+		// A dry run can't rewrite the stored paths, so it previews as if it had.
+		var renames map[string]string
+		if importDryRun {
+			if renames, err = db.FilenameRenames(database, canonicalFilename); err != nil {
+				return fmt.Errorf("failed to check stored paths: %w", err)
+			}
+			if len(renames) > 0 {
+				fmt.Printf("Would update the stored path of %d note(s) to its canonical form.\n", len(renames))
+			}
+		} else {
+			renamed, err := db.NormalizeFilenames(database, canonicalFilename)
+			if err != nil {
+				return fmt.Errorf("failed to normalize stored paths: %w", err)
+			}
+			if renamed > 0 {
+				fmt.Printf("Updated the stored path of %d note(s) to its canonical form.\n", renamed)
+			}
+		}
+
+		extensions := parseExtensions(importExtensions)
+		warnSizeKB := defaultWarnSizeKB
+		if cfg.Import.WarnSizeKB > 0 {
+			warnSizeKB = cfg.Import.WarnSizeKB
+		}
+		if singleFile {
+			return importFile(database, notesPath, extensions, warnSizeKB, renames)
+		}
+
+		ignoreDirs := defaultIgnoreDirs
+		if cfg.Import.IgnoreDirs != nil {
+			ignoreDirs = cfg.Import.IgnoreDirs
+		}
+		ignore, err := loadImportIgnore(notesPath, ignoreDirs)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", ignoreFileName, err)
+		}
+
+		started := time.Now()
+		var since time.Time
+		if importSince != "" {
+			since, err = resolveSince(database, importSince, started)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Track which files we found during this import
 		foundFiles := make(map[string]bool)
-		importedCount := 0
+		var paths []string
+		addedCount, updatedCount, unchangedCount, olderCount := 0, 0, 0, 0
+
+		// Walk the directory, collecting note paths first so they can be parsed in parallel
+		err = walkNoteFiles(notesPath, extensions, ignore, func(path string, info os.FileInfo) {
+			// Two symlinks to the same file are one note.
+			if foundFiles[path] {
+				return
+			}
+			// Mark this file as found
+			foundFiles[path] = true
+			if info.ModTime().Before(since) {
+				olderCount++
+				return
+			}
+			paths = append(paths, path)
+		})
+		if err != nil {
+			return fmt.Errorf("error walking the path %q: %w", notesPath, err)
+		}
+
+		// Results come back in path order, so output is deterministic no matter how many workers run.
+		sort.Strings(paths)
+		var parsedNotes []*note.Note
+		oversizedCount := 0
+		for _, result := range parseNotes(paths, importWorkers) {
+			if result.err != nil {
+				log.Printf("Error parsing %s: %v. Skipping.", result.path, result.err)
+				continue
+			}
+			result.note.AddTags(importAddTags...)
+			result.note.EaseFactor = study.InitialEase(result.note.Tags)
+			if warnOversized(result.note, warnSizeKB) {
+				oversizedCount++
+			}
+			parsedNotes = append(parsedNotes, result.note)
+		}
 
-		// Walk the directory
-		err = filepath.Walk(notesPath, func(path string, info os.FileInfo, err error) error {
+		if duplicates := duplicateTitles(parsedNotes); len(duplicates) > 0 {
+			printDuplicateTitles(duplicates)
+			if importFailOnDuplicates {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d title(s) are used by more than one note; nothing was imported", len(duplicates))
+			}
+		}
+
+		if importDryRun {
+			previews, err := db.PreviewSync(database, parsedNotes, renames)
 			if err != nil {
 				return err
 			}
-			// We only care about markdown files, not directories or other files
-			if !info.IsDir() && strings.HasSuffix(strings.ToLower(info.Name()), ".md") {
-				// Mark this file as found
-				foundFiles[path] = true
-
-				// Parse the file
-				parsedNote, err := note.ParseFile(path)
-				if err != nil {
-					log.Printf("Error parsing %s: %v. Skipping.", path, err)
-					return nil // Continue walking
-				}
-
-				// Insert into database
-				err = db.InsertNote(database, parsedNote)
-				if err != nil {
-					log.Printf("Error inserting %s into DB: %v. Skipping.", path, err)
-					return nil // Continue walking
-				}
-				fmt.Printf("✓ Synced: %s\n", parsedNote.Title)
-				importedCount++
+			toDelete, err := findDeletedNotes(database, foundFiles, notesPath, ignore, renames)
+			if err != nil {
+				return fmt.Errorf("error finding deleted notes: %w", err)
 			}
+			printDryRun(parsedNotes, previews, toDelete)
 			return nil
-		})
+		}
 
+		// Insert everything in one transaction so a failure leaves the database untouched
+		syncResults, err := db.InsertNotesTx(database, parsedNotes)
 		if err != nil {
-			return fmt.Errorf("error walking the path %q: %w", notesPath, err)
+			return fmt.Errorf("import rolled back: %w", err)
+		}
+		for i, syncResult := range syncResults {
+			switch syncResult {
+			case db.SyncAdded:
+				fmt.Printf("✓ Added: %s\n", parsedNotes[i].Title)
+				addedCount++
+			case db.SyncUpdated:
+				fmt.Printf("✓ Updated: %s\n", parsedNotes[i].Title)
+				updatedCount++
+			case db.SyncUnchanged:
+				unchangedCount++
+			}
 		}
 
 		// Now clean up deleted notes
-		deletedCount, err := cleanupDeletedNotes(database, foundFiles)
+		toDelete, err := findDeletedNotes(database, foundFiles, notesPath, ignore, nil)
 		if err != nil {
 			return fmt.Errorf("error cleaning up deleted notes: %w", err)
 		}
+		deletedCount := 0
+		if len(toDelete) > 0 {
+			if len(foundFiles) == 0 {
+				// An empty walk almost always means a wrong path, not that every note was deleted
+				return fmt.Errorf("no note files found in %s; refusing to remove all %d notes from the database. Check the path", notesPath, len(toDelete))
+			}
+			if importForce || confirmDeletion(bufio.NewReader(os.Stdin), toDelete, "are no longer in the import directory") {
+				deletedCount = deleteNotes(database, toDelete)
+			} else {
+				fmt.Println("Skipped removing notes. Their review history is kept.")
+			}
+		}
+
+		if err := db.SetLastImport(database, started); err != nil {
+			log.Printf("Error recording the import time: %v", err)
+		}
+		if err := db.SetLastImportPath(database, notesPath); err != nil {
+			log.Printf("Error recording the import directory: %v", err)
+		}
 
-		fmt.Printf("\nSync complete. Processed %d notes.", importedCount)
-		if deletedCount > 0 {
-			fmt.Printf(" Removed %d deleted notes.", deletedCount)
+		if olderCount > 0 {
+			fmt.Printf("\nSkipped %d file(s) not modified since %s.", olderCount, since.Format("2006-01-02 15:04"))
+		}
+		fmt.Printf("\nSync complete. Added: %d, Updated: %d, Unchanged: %d, Removed: %d.\n", addedCount, updatedCount, unchangedCount, deletedCount)
+		if oversizedCount > 0 {
+			fmt.Printf("%d note(s) are over %d KB; splitting them will make for better questions.\n", oversizedCount, warnSizeKB)
 		}
-		fmt.Println()
 
+		if importWatch {
+			return watchNotes(handleInterrupts(cmd), database, notesPath, extensions, ignore, warnSizeKB, watchDebounce)
+		}
 		return nil
 	},
 }
 
-// cleanupDeletedNotes removes database entries for files that no longer exist
-func cleanupDeletedNotes(database *sql.DB, foundFiles map[string]bool) (int, error) {
+// importFile adds or updates the one note at path. Unlike a directory import it
+// never removes notes: the rest of the collection simply isn't being looked at.
+func importFile(database *sql.DB, path string, extensions map[string]bool, warnSizeKB int, renames map[string]string) error {
+	if !extensions[strings.ToLower(filepath.Ext(path))] {
+		return fmt.Errorf("%s doesn't have a note extension (%s); use --ext to import it", filepath.Base(path), strings.Join(sortedKeys(extensions), ", "))
+	}
+	result := parseNotes([]string{path}, 1)[0]
+	if result.err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, result.err)
+	}
+	result.note.AddTags(importAddTags...)
+	result.note.EaseFactor = study.InitialEase(result.note.Tags)
+	warnOversized(result.note, warnSizeKB)
+	notes := []*note.Note{result.note}
+
+	if importDryRun {
+		previews, err := db.PreviewSync(database, notes, renames)
+		if err != nil {
+			return err
+		}
+		printDryRun(notes, previews, nil)
+		return nil
+	}
+	syncResults, err := db.InsertNotesTx(database, notes)
+	if err != nil {
+		return fmt.Errorf("import rolled back: %w", err)
+	}
+	switch syncResults[0] {
+	case db.SyncAdded:
+		fmt.Printf("✓ Added: %s\n", result.note.Title)
+	case db.SyncUpdated:
+		fmt.Printf("✓ Updated: %s\n", result.note.Title)
+	case db.SyncUnchanged:
+		fmt.Printf("Unchanged: %s\n", result.note.Title)
+	}
+	return nil
+}
+
+// importNotesDir returns the directory to import: the argument when given, otherwise
+// the configured notes directory.
+func importNotesDir(args []string, cfg *config.Config) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	if dir := cfg.NotesDirectory(); dir != "" {
+		return dir, nil
+	}
+	return "", fmt.Errorf("no notes directory given: pass a path, or set notes_dir in the config file or $%s", config.EnvNotesDir)
+}
+
+// canonicalPath returns path as an absolute path with symlinks resolved.
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// canonicalFilename is canonicalPath for db.NormalizeFilenames: files that no longer
+// exist can't be resolved, so they keep their stored name.
+func canonicalFilename(filename string) (string, bool) {
+	canonical, err := canonicalPath(filename)
+	return canonical, err == nil
+}
+
+// walkNoteFiles calls visit for every note file under root with one of the
+// extensions, skipping what ignore excludes. Paths are canonical (see
+// canonicalPath), the form notes are stored under, so a symlinked file matches
+// the row of its target.
+func walkNoteFiles(root string, extensions map[string]bool, ignore *importIgnore, visit func(path string, info os.FileInfo)) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if rel, relErr := filepath.Rel(root, path); relErr == nil && rel != "." && ignore.skip(filepath.ToSlash(rel), info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// We only care about markdown files, not directories or other files
+		if !info.IsDir() && extensions[strings.ToLower(filepath.Ext(info.Name()))] {
+			if canonical, err := canonicalPath(path); err == nil {
+				path = canonical
+			}
+			visit(path, info)
+		}
+		return nil
+	})
+}
+
+// warnOversized prints a warning and returns true when the note's content is over limitKB.
+func warnOversized(n *note.Note, limitKB int) bool {
+	size := len(n.Content)
+	if size <= limitKB*1024 {
+		return false
+	}
+	fmt.Printf("⚠️  %s is %d KB (over %d KB). The model only sees part of it; consider splitting it into smaller notes.\n", filepath.Base(n.Filename), (size+1023)/1024, limitKB)
+	return true
+}
+
+// titleGroup is a title shared by several notes.
+type titleGroup struct {
+	title     string
+	filenames []string
+}
+
+// duplicateTitles groups notes by case-insensitive title and returns the titles used
+// more than once, in the order they first appear.
+func duplicateTitles(notes []*note.Note) []titleGroup {
+	var groups []titleGroup
+	index := make(map[string]int)
+	for _, n := range notes {
+		key := strings.ToLower(strings.TrimSpace(n.Title))
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, titleGroup{title: n.Title})
+		}
+		groups[i].filenames = append(groups[i].filenames, n.Filename)
+	}
+	var duplicates []titleGroup
+	for _, g := range groups {
+		if len(g.filenames) > 1 {
+			duplicates = append(duplicates, g)
+		}
+	}
+	return duplicates
+}
+
+// printDuplicateTitles warns about each title shared by several notes.
+func printDuplicateTitles(duplicates []titleGroup) {
+	for _, g := range duplicates {
+		fmt.Printf("⚠️  %d notes are titled %q; looking one up by title will ask which you mean:\n", len(g.filenames), g.title)
+		for _, filename := range g.filenames {
+			fmt.Printf("  - %s\n", filename)
+		}
+	}
+}
+
+// resolveSince turns a --since value into a cutoff time. "last" means the start of the
+// previous import; with none recorded the zero time is returned so every file is parsed.
+func resolveSince(database *sql.DB, value string, now time.Time) (time.Time, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "last") {
+		last, ok, err := db.GetLastImport(database)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if !ok {
+			fmt.Println("No previous import recorded; importing every file.")
+		}
+		return last, nil
+	}
+	return parseSince(value, now)
+}
+
+// parseSince parses a duration (24h, 90m, or whole days like 7d), a date (2006-01-02,
+// local time), or an RFC 3339 timestamp into a cutoff time.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		return now.AddDate(0, 0, -days), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: use a duration like 24h or 7d, a date like 2026-01-31, or \"last\"", value)
+}
+
+// parseResult is the outcome of parsing one file.
+type parseResult struct {
+	path string
+	note *note.Note
+	err  error
+}
+
+// parseNotes parses files with a bounded pool of workers. Results are
+// returned in the same order as paths.
+func parseNotes(paths []string, workers int) []parseResult {
+	if workers < 1 {
+		workers = 1
+	}
+	results := make([]parseResult, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				parsed, err := note.ParseFile(paths[i])
+				results[i] = parseResult{path: paths[i], note: parsed, err: err}
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// parseExtensions turns a comma-separated list like "md, .Markdown" into a set of
+// lowercase extensions with a leading dot.
+func parseExtensions(list string) map[string]bool {
+	extensions := make(map[string]bool)
+	for _, ext := range strings.Split(list, ",") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		extensions[ext] = true
+	}
+	return extensions
+}
+
+// findDeletedNotes returns the filenames in the database that were not found during the walk.
+// Files under root that are ignored were skipped, not deleted, so they are kept.
+func findDeletedNotes(database *sql.DB, foundFiles map[string]bool, root string, ignore *importIgnore, renames map[string]string) ([]string, error) {
+	return filterStoredFilenames(database, func(filename string) bool {
+		// A dry run hasn't normalized the stored paths, so look for the new one.
+		if renamed, ok := renames[filename]; ok {
+			filename = renamed
+		}
+		// If this file wasn't found during our walk, it's been deleted
+		return !foundFiles[filename] && !isIgnoredNote(filename, root, ignore)
+	})
+}
+
+// filterStoredFilenames returns the filenames in the database for which gone reports true.
+func filterStoredFilenames(database *sql.DB, gone func(filename string) bool) ([]string, error) {
 	// Get all filenames currently in the database
 	query := `SELECT filename FROM notes;`
 	rows, err := database.Query(query)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -98,15 +528,39 @@ func cleanupDeletedNotes(database *sql.DB, foundFiles map[string]bool) (int, err
 	for rows.Next() {
 		var filename string
 		if err := rows.Scan(&filename); err != nil {
-			return 0, err
+			return nil, err
 		}
-
-		// If this file wasn't found during our walk, it's been deleted
-		if !foundFiles[filename] {
+		if gone(filename) {
 			toDelete = append(toDelete, filename)
 		}
 	}
+	return toDelete, rows.Err()
+}
 
+// isIgnoredNote reports whether a stored filename lies under root and is ignored there.
+func isIgnoredNote(filename, root string, ignore *importIgnore) bool {
+	rel, err := filepath.Rel(root, filename)
+	if err != nil || rel == "." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return ignore.covers(filepath.ToSlash(rel))
+}
+
+// confirmDeletion lists the notes that are about to be removed and asks the user to
+// confirm. why completes "N note(s) ...", saying why they are being removed.
+func confirmDeletion(reader *bufio.Reader, toDelete []string, why string) bool {
+	fmt.Printf("\n⚠️  %d note(s) %s and will be removed along with their review history:\n", len(toDelete), why)
+	for _, filename := range toDelete {
+		fmt.Printf("  - %s\n", filename)
+	}
+	fmt.Print("Remove them? (y/n): ")
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// deleteNotes removes database entries for files that no longer exist
+func deleteNotes(database *sql.DB, toDelete []string) int {
 	// Delete the orphaned entries
 	deletedCount := 0
 	for _, filename := range toDelete {
@@ -120,9 +574,65 @@ func cleanupDeletedNotes(database *sql.DB, foundFiles map[string]bool) (int, err
 		deletedCount++
 	}
 
-	return deletedCount, nil
+	// Drop links, cached questions and review history that belonged to removed
+	// notes. Foreign keys cascade these deletes, but can be turned off in the config.
+	if deletedCount > 0 {
+		if _, err := database.Exec(`DELETE FROM links WHERE source_id NOT IN (SELECT id FROM notes);`); err != nil {
+			log.Printf("Error removing links of deleted notes: %v", err)
+		}
+		if _, err := database.Exec(`DELETE FROM qa_cache WHERE note_id NOT IN (SELECT id FROM notes);`); err != nil {
+			log.Printf("Error removing cached questions of deleted notes: %v", err)
+		}
+		if _, err := database.Exec(`DELETE FROM review_log WHERE note_id NOT IN (SELECT id FROM notes);`); err != nil {
+			log.Printf("Error removing review history of deleted notes: %v", err)
+		}
+	}
+
+	return deletedCount
+}
+
+// printDryRun shows what an import would change without touching the database.
+func printDryRun(notes []*note.Note, results []db.SyncResult, toDelete []string) {
+	var added, updated []string
+	unchanged := 0
+	for i, result := range results {
+		switch result {
+		case db.SyncAdded:
+			added = append(added, notes[i].Title)
+		case db.SyncUpdated:
+			updated = append(updated, notes[i].Title)
+		case db.SyncUnchanged:
+			unchanged++
+		}
+	}
+
+	fmt.Println("\n--- Dry run: no changes were made ---")
+	for _, title := range added {
+		fmt.Printf("+ Would add:    %s\n", title)
+	}
+	for _, title := range updated {
+		fmt.Printf("~ Would update: %s\n", title)
+	}
+	for _, filename := range toDelete {
+		fmt.Printf("- Would remove: %s\n", filepath.Base(filename))
+	}
+
+	fmt.Println()
+	fmt.Printf("  %-10s %5d\n", "Add", len(added))
+	fmt.Printf("  %-10s %5d\n", "Update", len(updated))
+	fmt.Printf("  %-10s %5d\n", "Unchanged", unchanged)
+	fmt.Printf("  %-10s %5d\n", "Remove", len(toDelete))
 }
 
 func init() {
 	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().BoolVarP(&importForce, "force", "f", false, "Remove notes whose files are gone without asking")
+	importCmd.Flags().BoolVarP(&importForce, "yes", "y", false, "Alias for --force")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be added, updated, and removed without changing the database")
+	importCmd.Flags().IntVar(&importWorkers, "workers", runtime.NumCPU(), "Number of files to parse in parallel")
+	importCmd.Flags().StringVar(&importSince, "since", "", "Only parse files modified since a duration ago (24h, 7d), a date (2026-01-31), or the last import (\"last\")")
+	importCmd.Flags().BoolVar(&importFailOnDuplicates, "fail-on-duplicates", false, "Abort the sync when two notes share a title")
+	importCmd.Flags().StringSliceVar(&importAddTags, "add-tag", nil, "Tag to add to every imported note (repeatable)")
+	importCmd.Flags().BoolVar(&importWatch, "watch", false, "Keep running after the import and sync files as they change, until Ctrl-C")
+	importCmd.Flags().StringVar(&importExtensions, "ext", "md,markdown", "Comma-separated file extensions to import (e.g. md,markdown,mdx)")
 }
@@ -0,0 +1,680 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+// writeNoteFile creates a markdown note in dir and returns its path.
+func writeNoteFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// runImport runs the import command on path with --force set as given.
+func runImport(t *testing.T, path string, force bool) error {
+	t.Helper()
+	importForce = force
+	t.Cleanup(func() { importForce = false })
+	return importCmd.RunE(importCmd, []string{path})
+}
+
+// storedFilenames returns every filename in the notes table.
+func storedFilenames(t *testing.T) map[string]bool {
+	t.Helper()
+	database, err := db.GetDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes, err := db.AllNotes(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filenames := make(map[string]bool)
+	for _, n := range notes {
+		filenames[n.Filename] = true
+	}
+	return filenames
+}
+
+func TestImportRefusesToRemoveEverythingFromEmptyDirectory(t *testing.T) {
+	testDB(t)
+	notesDir := t.TempDir()
+	kept := writeNoteFile(t, notesDir, "kept.md", "# Kept\nbody")
+	if err := runImport(t, notesDir, true); err != nil {
+		t.Fatal(err)
+	}
+
+	empty := t.TempDir()
+	if err := runImport(t, empty, true); err == nil {
+		t.Fatal("importing an empty directory should refuse to remove every note")
+	}
+	if !storedFilenames(t)[kept] {
+		t.Errorf("%s was removed by an import that found no files", kept)
+	}
+}
+
+func TestImportForceRemovesWithoutAsking(t *testing.T) {
+	testDB(t)
+	notesDir := t.TempDir()
+	kept := writeNoteFile(t, notesDir, "kept.md", "# Kept\nbody")
+	gone := writeNoteFile(t, notesDir, "gone.md", "# Gone\nbody")
+	if err := runImport(t, notesDir, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without --force, answering no keeps the note.
+	withStdin(t, "n\n")
+	if err := runImport(t, notesDir, false); err != nil {
+		t.Fatal(err)
+	}
+	if !storedFilenames(t)[gone] {
+		t.Fatal("declining the prompt removed the note")
+	}
+
+	// With --force, nothing is read from stdin and the note goes.
+	withStdin(t, "")
+	if err := runImport(t, notesDir, true); err != nil {
+		t.Fatal(err)
+	}
+	filenames := storedFilenames(t)
+	if filenames[gone] || !filenames[kept] {
+		t.Errorf("after --force: stored %v, want only %s", filenames, kept)
+	}
+}
+
+func TestImportStoresSymlinkedFilesOnce(t *testing.T) {
+	testDB(t)
+	dir, err := canonicalPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	real := writeNoteFile(t, dir, "real.md", "# Real\nbody")
+	if err := os.Symlink(real, filepath.Join(dir, "link.md")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := runImport(t, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	filenames := storedFilenames(t)
+	if len(filenames) != 1 || !filenames[real] {
+		t.Errorf("stored %v, want only %s", filenames, real)
+	}
+}
+
+func TestImportDryRunPreviewsNormalizedPaths(t *testing.T) {
+	database := testDB(t)
+	root, err := canonicalPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(root, "notes")
+	path := writeNoteFile(t, dir, "a.md", "# A\nbody")
+	// The note was imported through a symlink, before paths were canonical.
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(dir, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	oldName := filepath.Join(link, "a.md")
+	addTestNote(t, database, oldName, "# A\nbody")
+
+	importDryRun = true
+	t.Cleanup(func() { importDryRun = false })
+	if err := runImport(t, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if filenames := storedFilenames(t); !filenames[oldName] {
+		t.Errorf("the dry run changed the stored paths: %v", filenames)
+	}
+
+	renames, err := db.FilenameRenames(database, canonicalFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renames[oldName] != path {
+		t.Fatalf("renames = %v, want %s -> %s", renames, oldName, path)
+	}
+	toDelete, err := findDeletedNotes(database, map[string]bool{path: true}, dir, &importIgnore{}, renames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(toDelete) != 0 {
+		t.Errorf("the dry run would remove %v, which is only stored under another path", toDelete)
+	}
+	stored, err := db.GetNoteByTitleOrFilename(database, "a.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored.Filename = path
+	previews, err := db.PreviewSync(database, []*note.Note{stored}, renames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previews[0] == db.SyncAdded {
+		t.Error("the dry run would add the note again under its canonical path")
+	}
+
+	importDryRun = false
+	if err := runImport(t, dir, false); err != nil {
+		t.Fatal(err)
+	}
+	if filenames := storedFilenames(t); len(filenames) != 1 || !filenames[path] {
+		t.Errorf("after a real import stored %v, want only %s", filenames, path)
+	}
+}
+
+func TestImportSingleFileKeepsOtherNotes(t *testing.T) {
+	testDB(t)
+	notesDir := t.TempDir()
+	first := writeNoteFile(t, notesDir, "first.md", "# First\nbody")
+	second := writeNoteFile(t, notesDir, "second.md", "# Second\nbody")
+	if err := runImport(t, first, false); err != nil {
+		t.Fatal(err)
+	}
+	if got := storedFilenames(t); len(got) != 1 || !got[first] {
+		t.Fatalf("after importing %s, stored = %v", first, got)
+	}
+
+	if err := runImport(t, second, false); err != nil {
+		t.Fatal(err)
+	}
+	got := storedFilenames(t)
+	if len(got) != 2 || !got[first] || !got[second] {
+		t.Errorf("importing %s should add it and keep %s, stored = %v", second, first, got)
+	}
+}
+
+func TestImportNotesDir(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		cfg     *config.Config
+		want    string
+		wantErr bool
+	}{
+		{"argument wins", []string{"/given"}, &config.Config{NotesDir: "/configured"}, "/given", false},
+		{"configured directory", nil, &config.Config{NotesDir: "/configured"}, "/configured", false},
+		{"home is expanded", nil, &config.Config{NotesDir: "~/notes"}, filepath.Join(home, "notes"), false},
+		{"nothing set", nil, &config.Config{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importNotesDir(tt.args, tt.cfg)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("importNotesDir = %q, %v; want %q (error %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImportNotesDirFromEnvironment(t *testing.T) {
+	useConfigFile(t, &config.Config{NotesDir: "/configured"})
+	t.Setenv(config.EnvNotesDir, "/from-env")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := importNotesDir(nil, cfg); err != nil || got != "/from-env" {
+		t.Errorf("importNotesDir = %q, %v; want $%s", got, err, config.EnvNotesDir)
+	}
+}
+
+func TestReimportingUnchangedNotesUpdatesNothing(t *testing.T) {
+	testDB(t)
+	dir := t.TempDir()
+	writeNoteFile(t, dir, "one.md", "# One\nbody")
+	writeNoteFile(t, dir, "sub/two.md", "---\ntags: [go]\n---\n# Two\nbody")
+
+	var err error
+	first := captureStdout(t, func() { err = runImport(t, dir, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(first, "Added: 2, Updated: 0, Unchanged: 0") {
+		t.Fatalf("first import didn't add both notes:\n%s", first)
+	}
+	second := captureStdout(t, func() { err = runImport(t, dir, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(second, "Added: 0, Updated: 0, Unchanged: 2, Removed: 0") {
+		t.Errorf("second import of the same files changed something:\n%s", second)
+	}
+	if strings.Contains(second, "✓ Updated") {
+		t.Errorf("second import reported updates:\n%s", second)
+	}
+}
+
+// tableContents returns every row of the tables an import writes, as text.
+func tableContents(t *testing.T, database *sql.DB) string {
+	t.Helper()
+	var b strings.Builder
+	for _, table := range []string{"notes", "links", "meta"} {
+		rows, err := database.Query(`SELECT * FROM ` + table + ` ORDER BY 1, 2;`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		columns, err := rows.Columns()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			values := make([]any, len(columns))
+			pointers := make([]any, len(columns))
+			for i := range values {
+				pointers[i] = &values[i]
+			}
+			if err := rows.Scan(pointers...); err != nil {
+				t.Fatal(err)
+			}
+			fmt.Fprintf(&b, "%s %v\n", table, values)
+		}
+		rows.Close()
+	}
+	return b.String()
+}
+
+func TestImportDryRunChangesNothing(t *testing.T) {
+	database := testDB(t)
+	dir := t.TempDir()
+	writeNoteFile(t, dir, "kept.md", "# Kept\nSee [[Edited]].")
+	edited := writeNoteFile(t, dir, "edited.md", "# Edited\nbody")
+	gone := writeNoteFile(t, dir, "gone.md", "# Gone\nbody")
+	if err := runImport(t, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	before := tableContents(t, database)
+
+	writeNoteFile(t, dir, "edited.md", "# Edited\nnew body with [[Kept]]")
+	writeNoteFile(t, dir, "added.md", "# Added\nbody")
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+	importDryRun = true
+	t.Cleanup(func() { importDryRun = false })
+	var err error
+	output := captureStdout(t, func() { err = runImport(t, dir, true) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if after := tableContents(t, database); after != before {
+		t.Errorf("the dry run changed the database:\nbefore:\n%s\nafter:\n%s", before, after)
+	}
+	for _, want := range []string{"+ Would add:    Added", "~ Would update: Edited", "- Would remove: gone.md"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("the dry run didn't report %q:\n%s", want, output)
+		}
+	}
+	if filenames := storedFilenames(t); !filenames[gone] || !filenames[edited] {
+		t.Errorf("stored notes after the dry run = %v", filenames)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, time.March, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"24h", now.Add(-24 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{" 1h30m ", now.Add(-90 * time.Minute)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"0d", now},
+		{"2026-01-31", time.Date(2026, time.January, 31, 0, 0, 0, 0, time.Local)},
+		{"2026-01-31T08:00:00Z", time.Date(2026, time.January, 31, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "yesterday", "7 days", "d", "31/01/2026"} {
+		if _, err := parseSince(value, now); err == nil {
+			t.Errorf("parseSince(%q) accepted an invalid value", value)
+		}
+	}
+}
+
+func TestResolveSinceLast(t *testing.T) {
+	database := testDB(t)
+	now := time.Now()
+	got, err := resolveSince(database, "last", now)
+	if err != nil || !got.IsZero() {
+		t.Errorf("without a recorded import, resolveSince = %v, %v; want the zero time", got, err)
+	}
+
+	last := now.Add(-3 * time.Hour).Truncate(time.Second)
+	if err := db.SetLastImport(database, last); err != nil {
+		t.Fatal(err)
+	}
+	got, err = resolveSince(database, " LAST ", now)
+	if err != nil || !got.Equal(last) {
+		t.Errorf("resolveSince(last) = %v, %v; want %v", got, err, last)
+	}
+	if got, err := resolveSince(database, "2h", now); err != nil || !got.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("resolveSince(2h) = %v, %v; want two hours ago", got, err)
+	}
+}
+
+func TestImportSinceSkipsOlderFiles(t *testing.T) {
+	testDB(t)
+	dir := t.TempDir()
+	fresh := writeNoteFile(t, dir, "fresh.md", "# Fresh\nbody")
+	stale := writeNoteFile(t, dir, "stale.md", "# Stale\nbody")
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	importSince = "24h"
+	t.Cleanup(func() { importSince = "" })
+	var err error
+	output := captureStdout(t, func() { err = runImport(t, dir, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filenames := storedFilenames(t); !filenames[fresh] || filenames[stale] {
+		t.Errorf("stored %v, want only %s", filenames, fresh)
+	}
+	if !strings.Contains(output, "Skipped 1 file(s) not modified since") {
+		t.Errorf("the skipped file wasn't reported:\n%s", output)
+	}
+}
+
+func TestWarnOversized(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		limitKB int
+		want    bool
+	}{
+		{"under the limit", 1024, 2, false},
+		{"exactly the limit", 2048, 2, false},
+		{"one byte over", 2049, 2, true},
+		{"far over", 50 * 1024, 20, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &note.Note{Filename: "/notes/big.md", Content: strings.Repeat("x", tt.size)}
+			var got bool
+			output := captureStdout(t, func() { got = warnOversized(n, tt.limitKB) })
+			if got != tt.want {
+				t.Errorf("warnOversized(%d bytes, %d KB) = %v, want %v", tt.size, tt.limitKB, got, tt.want)
+			}
+			if warned := strings.Contains(output, "big.md is"); warned != tt.want {
+				t.Errorf("printed %q; want a warning: %v", output, tt.want)
+			}
+		})
+	}
+	output := captureStdout(t, func() { warnOversized(&note.Note{Filename: "/notes/big.md", Content: strings.Repeat("x", 2049)}, 2) })
+	if !strings.Contains(output, "big.md is 3 KB (over 2 KB)") {
+		t.Errorf("the warning doesn't round the size up: %q", output)
+	}
+}
+
+func TestImportCountsOversizedNotes(t *testing.T) {
+	testDB(t)
+	useConfigFile(t, &config.Config{Import: config.ImportConfig{WarnSizeKB: 1}})
+	dir := t.TempDir()
+	writeNoteFile(t, dir, "small.md", "# Small\nbody")
+	writeNoteFile(t, dir, "large.md", "# Large\n"+strings.Repeat("word ", 400))
+	var err error
+	output := captureStdout(t, func() { err = runImport(t, dir, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "large.md is 2 KB (over 1 KB)") || strings.Contains(output, "small.md is") {
+		t.Errorf("expected a warning for large.md alone:\n%s", output)
+	}
+	if !strings.Contains(output, "1 note(s) are over 1 KB") {
+		t.Errorf("the summary doesn't count the oversized note:\n%s", output)
+	}
+	if len(storedFilenames(t)) != 2 {
+		t.Errorf("oversized notes should still be imported")
+	}
+}
+
+func TestDuplicateTitles(t *testing.T) {
+	notes := []*note.Note{
+		{Filename: "/a/go.md", Title: "Go"},
+		{Filename: "/a/tcp.md", Title: "TCP"},
+		{Filename: "/b/go.md", Title: " go "},
+		{Filename: "/a/udp.md", Title: "UDP"},
+		{Filename: "/c/tcp.md", Title: "tcp"},
+		{Filename: "/c/go.md", Title: "GO"},
+	}
+	got := duplicateTitles(notes)
+	want := []titleGroup{
+		{title: "Go", filenames: []string{"/a/go.md", "/b/go.md", "/c/go.md"}},
+		{title: "TCP", filenames: []string{"/a/tcp.md", "/c/tcp.md"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].title != want[i].title || !slices.Equal(got[i].filenames, want[i].filenames) {
+			t.Errorf("group %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got := duplicateTitles(notes[:2]); len(got) != 0 {
+		t.Errorf("unique titles grouped as %+v", got)
+	}
+}
+
+func TestImportFailOnDuplicates(t *testing.T) {
+	testDB(t)
+	dir := t.TempDir()
+	writeNoteFile(t, dir, "a/index.md", "# Index\nbody")
+	writeNoteFile(t, dir, "b/index.md", "# index\nbody")
+
+	importFailOnDuplicates = true
+	t.Cleanup(func() { importFailOnDuplicates = false })
+	var err error
+	output := captureStdout(t, func() { err = runImport(t, dir, false) })
+	if err == nil || !strings.Contains(err.Error(), "1 title(s) are used by more than one note") {
+		t.Errorf("err = %v, want the duplicate titles to abort the import", err)
+	}
+	if !strings.Contains(output, `2 notes are titled "Index"`) {
+		t.Errorf("the duplicates weren't listed:\n%s", output)
+	}
+	if filenames := storedFilenames(t); len(filenames) != 0 {
+		t.Errorf("the aborted import stored %v", filenames)
+	}
+
+	importFailOnDuplicates = false
+	if err := runImport(t, dir, false); err != nil {
+		t.Errorf("without --fail-on-duplicates the import failed: %v", err)
+	}
+	if len(storedFilenames(t)) != 2 {
+		t.Errorf("without --fail-on-duplicates both notes should be imported")
+	}
+}
+
+func TestImportStartsTaggedNotesAtTheirEase(t *testing.T) {
+	database := testDB(t)
+	scheduler := study.DefaultSchedulerConfig()
+	scheduler.TagEase = map[string]float64{"languages": 2.0}
+	previous := study.CurrentSchedulerConfig()
+	study.SetSchedulerConfig(scheduler)
+	t.Cleanup(func() { study.SetSchedulerConfig(previous) })
+
+	notesDir := t.TempDir()
+	tagged := writeNoteFile(t, notesDir, "spanish.md", "---\ntags: [languages]\n---\n# Spanish\nbody")
+	untagged := writeNoteFile(t, notesDir, "maps.md", "# Maps\nbody")
+	if err := runImport(t, notesDir, true); err != nil {
+		t.Fatal(err)
+	}
+	ease := func(path string) float64 {
+		t.Helper()
+		var ease float64
+		if err := database.QueryRow(`SELECT ease_factor FROM notes WHERE filename = ?;`, path).Scan(&ease); err != nil {
+			t.Fatal(err)
+		}
+		return ease
+	}
+	if got := ease(tagged); got != 2.0 {
+		t.Errorf("tagged note starts at ease %g, want its tag's 2.0", got)
+	}
+	if got := ease(untagged); got != note.DefaultEaseFactor {
+		t.Errorf("untagged note starts at ease %g, want %g", got, note.DefaultEaseFactor)
+	}
+
+	// Reimporting doesn't undo what reviews did to the ease.
+	if _, err := database.Exec(`UPDATE notes SET ease_factor = 2.9 WHERE filename = ?;`, tagged); err != nil {
+		t.Fatal(err)
+	}
+	writeNoteFile(t, notesDir, "spanish.md", "---\ntags: [languages]\n---\n# Spanish\nedited body")
+	if err := runImport(t, notesDir, true); err != nil {
+		t.Fatal(err)
+	}
+	if got := ease(tagged); got != 2.9 {
+		t.Errorf("reimported note's ease = %g, want the reviewed 2.9 kept", got)
+	}
+}
+
+func TestImportAddTagSkipsDuplicates(t *testing.T) {
+	database := testDB(t)
+	importAddTags = []string{"go", "Go", "course", " course "}
+	t.Cleanup(func() { importAddTags = nil })
+
+	notesDir := t.TempDir()
+	writeNoteFile(t, notesDir, "maps.md", "---\ntags: [GO, maps]\n---\n# Maps\nbody")
+	writeNoteFile(t, notesDir, "plain.md", "# Plain\nbody")
+	if err := runImport(t, notesDir, true); err != nil {
+		t.Fatal(err)
+	}
+	for title, want := range map[string][]string{
+		"Maps":  {"GO", "maps", "course"},
+		"Plain": {"go", "course"},
+	} {
+		n, err := db.GetNoteByTitleOrFilename(database, title)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(n.Tags, want) {
+			t.Errorf("%s imported with tags %q, want %q", title, n.Tags, want)
+		}
+	}
+}
+
+// writeManyNotes creates count small notes in dir and returns their paths, sorted.
+func writeManyNotes(tb testing.TB, dir string, count int) []string {
+	tb.Helper()
+	paths := make([]string, count)
+	for i := range paths {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("note-%04d.md", i))
+		content := fmt.Sprintf("---\ntags: [generated]\n---\n# Note %d\n\n## Summary\nFact number %d.\n", i, i)
+		if err := os.WriteFile(paths[i], []byte(content), 0644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return paths
+}
+
+func TestParseNotes(t *testing.T) {
+	dir := t.TempDir()
+	generated := writeManyNotes(t, dir, 200)
+	missing := filepath.Join(dir, "missing.md")
+	paths := slices.Concat(generated[:100], []string{missing}, generated[100:])
+
+	for _, workers := range []int{0, 1, 4, 64, 500} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			results := parseNotes(paths, workers)
+			if len(results) != len(paths) {
+				t.Fatalf("%d results for %d paths", len(results), len(paths))
+			}
+			for i, result := range results {
+				if result.path != paths[i] {
+					t.Fatalf("result %d is for %s, want %s: results aren't in path order", i, result.path, paths[i])
+				}
+				if result.path == missing {
+					if result.err == nil || result.note != nil {
+						t.Errorf("missing file parsed as %v, %v; want an error", result.note, result.err)
+					}
+					continue
+				}
+				want := fmt.Sprintf("Note %d", slices.Index(generated, result.path))
+				if result.err != nil || result.note == nil || result.note.Title != want {
+					t.Errorf("result %d = %v, %v; want %q", i, result.note, result.err, want)
+				}
+			}
+		})
+	}
+	if results := parseNotes(nil, 4); len(results) != 0 {
+		t.Errorf("parseNotes(nil) = %v, want no results", results)
+	}
+}
+
+func TestImportWorkersDontChangeTheResult(t *testing.T) {
+	notesDir := t.TempDir()
+	writeManyNotes(t, notesDir, 50)
+	t.Cleanup(func() { importWorkers = runtime.NumCPU() })
+
+	var outputs []string
+	var stored [][]string
+	for _, workers := range []int{1, 8} {
+		database := testDB(t)
+		importWorkers = workers
+		var err error
+		outputs = append(outputs, captureStdout(t, func() { err = runImport(t, notesDir, true) }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		notes, err := db.AllNotes(database)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for _, n := range notes {
+			titles = append(titles, n.Title+" "+strings.Join(n.Tags, ","))
+		}
+		slices.Sort(titles)
+		stored = append(stored, titles)
+	}
+	if len(stored[0]) != 50 || !slices.Equal(stored[0], stored[1]) {
+		t.Errorf("1 worker stored %d notes and 8 workers %d; want the same 50", len(stored[0]), len(stored[1]))
+	}
+	if outputs[0] != outputs[1] {
+		t.Errorf("output depends on the number of workers:\n1 worker:\n%s\n8 workers:\n%s", outputs[0], outputs[1])
+	}
+}
+
+func BenchmarkParseNotes(b *testing.B) {
+	paths := writeManyNotes(b, b.TempDir(), 2000)
+	for _, workers := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for b.Loop() {
+				parseNotes(paths, workers)
+			}
+		})
+	}
+}
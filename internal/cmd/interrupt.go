@@ -0,0 +1,52 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// errInterrupted is what a session's stdin returns once Ctrl-C was pressed.
+var errInterrupted = errors.New("interrupted")
+
+// interruptsHandled is set by commands that watch their context and end cleanly
+// on Ctrl-C. Any other command is stopped right away, as before.
+var interruptsHandled atomic.Bool
+
+// handleInterrupts marks the running command as ending itself on Ctrl-C and
+// returns the context that is cancelled when it is pressed.
+func handleInterrupts(cmd *cobra.Command) context.Context {
+	interruptsHandled.Store(true)
+	return cmd.Context()
+}
+
+// watchInterrupts waits for the first Ctrl-C. Commands that handle it get to
+// finish on their own; a second Ctrl-C then kills the process as usual, since
+// stop restores the default signal behavior.
+func watchInterrupts(ctx context.Context, stop context.CancelFunc) {
+	<-ctx.Done()
+	stop()
+	if !interruptsHandled.Load() {
+		resetColor()
+		os.Exit(130)
+	}
+}
+
+// resetColor clears any color left on by output that was cut short.
+func resetColor() {
+	if !color.NoColor {
+		fmt.Print("\x1b[0m")
+	}
+}
+
+// printInterrupted ends a session that Ctrl-C broke off at a card boundary.
+func printInterrupted(reviewed int) {
+	resetColor()
+	fmt.Printf("\n\n⏹  Session interrupted, %d card(s) reviewed. Ratings given so far are saved.\n", reviewed)
+}
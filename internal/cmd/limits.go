@@ -0,0 +1,62 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+// dailyLimits tracks Anki-style per-day caps for review and mix.
+// A max of 0 means unlimited.
+type dailyLimits struct {
+	maxReviews  int
+	maxNew      int
+	reviewsDone int
+	newDone     int
+}
+
+// loadDailyLimits reads today's counts from the database.
+func loadDailyLimits(database *sql.DB, maxReviews, maxNew int) (*dailyLimits, error) {
+	reviewsDone, newDone, err := db.GetDailyStats(database, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load daily stats: %w", err)
+	}
+	return &dailyLimits{maxReviews: maxReviews, maxNew: maxNew, reviewsDone: reviewsDone, newDone: newDone}, nil
+}
+
+// reviewsLeft returns how many more reviews are allowed today, or -1 if unlimited.
+func (l *dailyLimits) reviewsLeft() int {
+	if l.maxReviews <= 0 {
+		return -1
+	}
+	return max(l.maxReviews-l.reviewsDone, 0)
+}
+
+// newAllowed reports whether another never-reviewed card may be shown today.
+func (l *dailyLimits) newAllowed() bool {
+	return l.maxNew <= 0 || l.newDone < l.maxNew
+}
+
+// record counts a finished review toward today's totals.
+func (l *dailyLimits) record(database *sql.DB, wasNew bool) {
+	l.reviewsDone++
+	if wasNew {
+		l.newDone++
+	}
+	if err := db.RecordReview(database, time.Now(), wasNew); err != nil {
+		log.Printf("Error recording daily stats: %v", err)
+	}
+}
+
+// printLimitReached tells the user the daily cap is hit and how much is still waiting.
+func printLimitReached(database *sql.DB, l *dailyLimits) {
+	fmt.Printf("🛑 Daily review limit reached (%d/%d).", l.reviewsDone, l.maxReviews)
+	if remaining, err := db.CountDueNotes(database); err == nil {
+		fmt.Printf(" %d note(s) are still due and will wait for tomorrow.", remaining)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+// recordReviews counts reviews, all of them new cards, toward the day containing at.
+func recordReviews(t *testing.T, database *sql.DB, at time.Time, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		if err := db.RecordReview(database, at, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDailyLimitsStartOverEachDay(t *testing.T) {
+	database := testDB(t)
+	recordReviews(t, database, time.Now().AddDate(0, 0, -1), 5)
+
+	limits, err := loadDailyLimits(database, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if left := limits.reviewsLeft(); left != 5 {
+		t.Errorf("reviews left today = %d, want all 5; yesterday's reviews don't count", left)
+	}
+	if !limits.newAllowed() {
+		t.Error("new cards aren't allowed today, though only yesterday's hit the cap")
+	}
+
+	for i := 0; i < 3; i++ {
+		limits.record(database, true)
+	}
+	if limits, err = loadDailyLimits(database, 5, 3); err != nil {
+		t.Fatal(err)
+	}
+	if left := limits.reviewsLeft(); left != 2 {
+		t.Errorf("reviews left after 3 today = %d, want 2", left)
+	}
+	if limits.newAllowed() {
+		t.Error("new cards are still allowed after 3 of 3 today")
+	}
+}
+
+func TestReviewAfterYesterdaysCap(t *testing.T) {
+	database := testDB(t)
+	addCardNote(t, database, "due", -time.Hour)
+	recordReviews(t, database, time.Now().AddDate(0, 0, -1), 2)
+
+	output, err := runReview(t, rateGood, "--max-reviews", "2", "--brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(output, "Daily review limit reached") || len(reviewedNotes(t, database)) != 1 {
+		t.Errorf("yesterday's reviews kept today's from starting:\n%s", output)
+	}
+
+	recordReviews(t, database, time.Now(), 1)
+	addCardNote(t, database, "also due", -time.Hour)
+	output, err = runReview(t, rateGood, "--max-reviews", "2", "--brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Daily review limit reached (2/2)") {
+		t.Errorf("the cap didn't stop today's third review:\n%s", output)
+	}
+}
@@ -0,0 +1,103 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// linksResult is the links command's JSON output.
+type linksResult struct {
+	Title     string       `json:"title"`
+	Links     []linkTarget `json:"links"`
+	Backlinks []string     `json:"backlinks"`
+}
+
+// linkTarget is one outgoing wikilink. Title is empty when the target isn't imported.
+type linkTarget struct {
+	Target string `json:"target"`
+	Title  string `json:"title,omitempty"`
+}
+
+var linksCmd = &cobra.Command{
+	Use:   "links [topic]",
+	Short: "Show the notes a note links to and the notes that link back to it",
+	Long: `Shows the connections of a note based on Obsidian-style [[wikilinks]].
+Outgoing links are resolved against note titles, filenames, and aliases.
+Backlinks are the notes whose wikilinks point at this note.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		topic := args[0]
+
+		database, err := db.GetDB()
+		if err != nil {
+			return err
+		}
+
+		noteToShow, err := lookupNote(database, topic)
+		if err != nil || noteToShow == nil {
+			return err
+		}
+
+		targets, err := db.GetLinkTargets(database, noteToShow.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load links: %w", err)
+		}
+		backlinks, err := db.GetBacklinks(database, noteToShow)
+		if err != nil {
+			return fmt.Errorf("failed to load backlinks: %w", err)
+		}
+
+		result := linksResult{Title: noteToShow.Title, Links: []linkTarget{}, Backlinks: []string{}}
+		for _, target := range targets {
+			link := linkTarget{Target: target}
+			linked, err := db.ResolveLink(database, target)
+			if err == nil {
+				link.Title = linked.Title
+			} else if err != sql.ErrNoRows {
+				return fmt.Errorf("failed to resolve link %q: %w", target, err)
+			}
+			result.Links = append(result.Links, link)
+		}
+		for _, backlink := range backlinks {
+			result.Backlinks = append(result.Backlinks, backlink.Title)
+		}
+
+		return newOutputter().Print(result, func() {
+			headingColor := color.New(color.FgCyan, color.Bold)
+			missingColor := color.New(color.FgYellow)
+
+			fmt.Printf("--- Connections for: %s ---\n", result.Title)
+
+			headingColor.Printf("\n🔗 Links to (%d):\n", len(result.Links))
+			if len(result.Links) == 0 {
+				fmt.Println("  (none)")
+			}
+			for _, link := range result.Links {
+				if link.Title == "" {
+					missingColor.Printf("  • %s (not imported)\n", link.Target)
+					continue
+				}
+				fmt.Printf("  • %s\n", link.Title)
+			}
+
+			headingColor.Printf("\n↩️  Linked from (%d):\n", len(result.Backlinks))
+			if len(result.Backlinks) == 0 {
+				fmt.Println("  (none)")
+			}
+			for _, title := range result.Backlinks {
+				fmt.Printf("  • %s\n", title)
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linksCmd)
+	addIndexFlag(linksCmd)
+}
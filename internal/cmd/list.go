@@ -0,0 +1,105 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var listLeeches bool
+
+// listEntry is one note in list's JSON output.
+type listEntry struct {
+	Title     string    `json:"title"`
+	Filename  string    `json:"filename"`
+	Tags      []string  `json:"tags"`
+	DueDate   time.Time `json:"due_date"`
+	Lapses    int       `json:"lapses"`
+	Suspended bool      `json:"suspended"`
+	Retired   bool      `json:"retired"`
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the notes in your database",
+	Long: `Lists every imported note with its tags and next due date.
+Use --leeches to only show notes you keep forgetting (tagged "leech"),
+most-lapsed first. These are good candidates for rewriting.`,
+	Args:        cobra.NoArgs,
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		var notes []*note.Note
+		if listLeeches {
+			notes, err = db.GetNotesByTag(database, study.LeechTag, -1)
+			sort.Slice(notes, func(i, j int) bool {
+				return notes[i].Lapses > notes[j].Lapses
+			})
+		} else {
+			notes, err = db.AllNotes(database)
+			sort.Slice(notes, func(i, j int) bool {
+				return strings.ToLower(notes[i].Title) < strings.ToLower(notes[j].Title)
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load notes: %w", err)
+		}
+
+		entries := make([]listEntry, 0, len(notes))
+		for _, n := range notes {
+			tags := n.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			entries = append(entries, listEntry{Title: n.Title, Filename: n.Filename, Tags: tags, DueDate: n.DueDate, Lapses: n.Lapses, Suspended: n.Suspended, Retired: n.Retired})
+		}
+
+		return newOutputter().Print(entries, func() {
+			if len(notes) == 0 {
+				if listLeeches {
+					fmt.Println("🎉 No leeches. Every note is sticking!")
+				} else {
+					fmt.Println("You have no notes yet. Run 'neuron import <path>' first.")
+				}
+				return
+			}
+
+			tagColor := color.New(color.FgCyan)
+			for _, n := range notes {
+				fmt.Printf("• %s", n.Title)
+				if len(n.Tags) > 0 {
+					tagColor.Printf("  [%s]", strings.Join(n.Tags, ", "))
+				}
+				if n.Suspended {
+					fmt.Print("  (suspended)")
+				}
+				if n.Retired {
+					fmt.Print("  (retired)")
+				}
+				if listLeeches {
+					fmt.Printf("  (%d lapses)\n", n.Lapses)
+				} else {
+					fmt.Printf("  (due %s)\n", n.DueDate.Local().Format("2006-01-02"))
+				}
+			}
+			fmt.Printf("\n%d note(s)\n", len(notes))
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().BoolVar(&listLeeches, "leeches", false, "Only show notes tagged as leeches, most-lapsed first")
+}
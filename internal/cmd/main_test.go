@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"database/sql"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// TestMain points the config directory, and so the database, at a temporary
+// directory so tests never touch the real one.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "neuron-cmd-test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	log.SetOutput(io.Discard)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// fakeProvider answers every request with reply, or what respond returns for
+// the prompt when it is set, and counts the calls.
+type fakeProvider struct {
+	mu        sync.Mutex
+	reply     string
+	respond   func(prompt string) string
+	generates int
+	chats     int
+}
+
+func (p *fakeProvider) Generate(prompt string, opts *study.OllamaOptions) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.generates++
+	if p.respond != nil {
+		return p.respond(prompt), nil
+	}
+	return p.reply, nil
+}
+
+func (p *fakeProvider) Chat(messages []study.OllamaMessage, opts *study.OllamaOptions) (study.OllamaMessage, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.chats++
+	return study.OllamaMessage{Role: "assistant", Content: p.reply}, nil
+}
+
+// useFakeProvider makes the study helpers talk to a fake provider for the rest of the test.
+func useFakeProvider(t *testing.T, reply string) *fakeProvider {
+	t.Helper()
+	p := &fakeProvider{reply: reply}
+	study.SetProvider(p)
+	t.Cleanup(func() { study.SetProvider(study.NewOllamaProvider()) })
+	return p
+}
+
+// testDB returns the test database with every note and review removed.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := db.GetDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"notes", "links", "qa_cache", "review_log", "daily_stats", "meta"} {
+		if _, err := database.Exec(`DELETE FROM ` + table + `;`); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return database
+}
+
+// addTestNote stores a due note with the given file path, content and tags.
+func addTestNote(t *testing.T, database *sql.DB, filename, content string, tags ...string) *note.Note {
+	t.Helper()
+	n := &note.Note{
+		Filename:   filename,
+		Title:      filepath.Base(filename),
+		Tags:       tags,
+		Content:    content,
+		CreatedAt:  time.Now(),
+		DueDate:    time.Now().Add(-time.Hour),
+		Interval:   note.DefaultInterval,
+		EaseFactor: note.DefaultEaseFactor,
+	}
+	if _, err := db.InsertNote(database, n); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := db.GetNoteByTitleOrFilename(database, n.Title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+// withStdin feeds input to commands that read os.Stdin for the rest of the test.
+func withStdin(t *testing.T, input string) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		r.Close()
+	})
+}
+
+// executeRoot runs the command line args through rootCmd, as main does, and
+// resets the flags, scheduler and provider it set up once the test ends.
+func executeRoot(t *testing.T, args ...string) error {
+	t.Helper()
+	scheduler := study.CurrentSchedulerConfig()
+	t.Cleanup(func() {
+		study.SetSchedulerConfig(scheduler)
+		study.SetProvider(study.NewOllamaProvider())
+		resetFlags(rootCmd)
+	})
+	rootCmd.SetArgs(args)
+	return rootCmd.Execute()
+}
+
+// resetFlags restores every flag of cmd and its subcommands that was set.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if f.Changed {
+			f.Value.Set(f.DefValue)
+			f.Changed = false
+		}
+	}
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// captureStdout returns what fn prints to os.Stdout, including colored output,
+// which fatih/color writes to its own handle on stdout.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, colorOutput := os.Stdout, color.Output
+	os.Stdout, color.Output = w, w
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	restore := func() {
+		os.Stdout, color.Output = stdout, colorOutput
+		w.Close()
+	}
+	defer func() {
+		if os.Stdout == w {
+			restore()
+		}
+	}()
+	fn()
+	restore()
+	return <-output
+}
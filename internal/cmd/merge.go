@@ -0,0 +1,78 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/spf13/cobra"
+)
+
+var mergeYes bool
+
+// mergeSeparator goes between the target's content and the merged-in source.
+const mergeSeparator = "\n\n---\n\n"
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge [source] [target]",
+	Short: "Fold one note into another that covers the same concept",
+	Long: `Appends the source note's content to the target note, below a horizontal
+rule, and removes the source from the database. The target keeps its schedule
+but takes the earlier of the two due dates, and gains the source's tags, links,
+and review history.
+
+Only the database changes: both Markdown files are left as they are. Move the
+content in your notes folder too and delete the source file, or the next import
+adds the source back.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, source, err := findTopicNote(args[0])
+		if err != nil || source == nil {
+			return err
+		}
+		target, err := lookupNote(database, args[1])
+		if err != nil || target == nil {
+			return err
+		}
+		if source.ID == target.ID {
+			return fmt.Errorf("'%s' and '%s' are the same note", args[0], args[1])
+		}
+
+		if !mergeYes {
+			fmt.Printf("Merge '%s' into '%s'? '%s' will be removed from the database. (y/n): ", source.Title, target.Title, source.Title)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			answer = strings.TrimSpace(strings.ToLower(answer))
+			if answer != "y" && answer != "yes" {
+				fmt.Println("Merge cancelled.")
+				return nil
+			}
+		}
+
+		mergeNoteInto(source, target)
+		if err := db.MergeNotes(database, target, source.ID); err != nil {
+			return fmt.Errorf("failed to merge notes: %w", err)
+		}
+		fmt.Printf("✓ Merged '%s' into '%s'.\n", source.Title, target.Title)
+		fmt.Printf("⚠️  The files weren't changed. Move the content into %s and delete %s, or the next import adds the source back.\n", target.Filename, source.Filename)
+		return nil
+	},
+}
+
+// mergeNoteInto appends source's content to target's, adds source's tags, and
+// keeps whichever due date comes first.
+func mergeNoteInto(source, target *note.Note) {
+	target.Content = strings.TrimRight(target.Content, "\n") + mergeSeparator + strings.TrimSpace(source.Content) + "\n"
+	target.AddTags(source.Tags...)
+	if source.DueDate.Before(target.DueDate) {
+		target.DueDate = source.DueDate
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().BoolVarP(&mergeYes, "yes", "y", false, "Don't ask for confirmation")
+}
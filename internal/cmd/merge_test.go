@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+func TestMergeNoteInto(t *testing.T) {
+	now := time.Now()
+	source := &note.Note{Content: "\nSource body.\n\n", Tags: []string{"go", "Concurrency", ""}, DueDate: now.Add(-time.Hour)}
+	target := &note.Note{Content: "# Target\nTarget body.\n\n", Tags: []string{"go", "basics"}, DueDate: now.Add(time.Hour)}
+
+	mergeNoteInto(source, target)
+	if want := "# Target\nTarget body." + mergeSeparator + "Source body.\n"; target.Content != want {
+		t.Errorf("content = %q, want %q", target.Content, want)
+	}
+	if want := []string{"go", "basics", "Concurrency"}; len(target.Tags) != len(want) || target.Tags[0] != want[0] || target.Tags[1] != want[1] || target.Tags[2] != want[2] {
+		t.Errorf("tags = %q, want %q", target.Tags, want)
+	}
+	if !target.DueDate.Equal(source.DueDate) {
+		t.Errorf("due date = %v, want the earlier %v", target.DueDate, source.DueDate)
+	}
+
+	later := &note.Note{Content: "More.", DueDate: now.Add(24 * time.Hour)}
+	due := target.DueDate
+	mergeNoteInto(later, target)
+	if !target.DueDate.Equal(due) {
+		t.Errorf("merging a later note moved the due date to %v", target.DueDate)
+	}
+}
+
+func TestMergeCommand(t *testing.T) {
+	database := testDB(t)
+	source := addTestNote(t, database, "/notes/source.md", "Source body.")
+	target := addTestNote(t, database, "/notes/target.md", "Target body.")
+	if err := saveRating(database, source, 2, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := executeRoot(t, "merge", source.Title, target.Title, "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.GetNoteByTitleOrFilename(database, source.Title); err == nil {
+		t.Error("the source note is still in the database")
+	}
+	merged, err := db.GetNoteByTitleOrFilename(database, target.Title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Target body." + mergeSeparator + "Source body.\n"; merged.Content != want {
+		t.Errorf("content = %q, want %q", merged.Content, want)
+	}
+	timings, err := db.ReviewTimings(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timings) != 1 || timings[0].NoteID != target.ID {
+		t.Errorf("review history = %+v, want the source's review moved to the target", timings)
+	}
+}
@@ -5,13 +5,12 @@ import (
 	"bufio"
 	"database/sql"
 	"fmt"
-	"math"
 	"os"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
 	"github.com/soyomarvaldezg/neuron-cli/internal/study"
 	"github.com/spf13/cobra"
 )
@@ -20,24 +19,49 @@ const reviewLimit = 3 // Number of notes to review in one 'mix' session
 
 var mixBrief bool
 var mixQuestionType string
+var mixCache bool
+var mixNoCache bool
+var mixMaxReviews int
+var mixMaxNew int
 
 var mixCmd = &cobra.Command{
 	Use:   "mix",
 	Short: "Start an interleaved review session with random due notes",
 	Long: `Starts a review session with a small number of randomly selected notes
 that are currently due. This helps improve memory by forcing context switching.
+Notes are drawn from the most overdue ones, so a note that is weeks late comes
+up before one that only just became due.
 Use --question-type to specify the type of questions generated:
 - factual: Questions about definitions, facts, and specific details
 - conceptual: Questions about relationships, principles, and "why" things work
 - application: Questions about applying concepts to real scenarios
 - mixed: A mix of all question types (default)`,
+	Annotations: usesLLM,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := db.GetDB()
 		if err != nil {
 			return err
 		}
 
-		notes, err := db.GetDueNotes(database, reviewLimit)
+		limits, err := loadDailyLimits(database, mixMaxReviews, mixMaxNew)
+		if err != nil {
+			return err
+		}
+		limit := reviewLimit
+		if left := limits.reviewsLeft(); left >= 0 {
+			if left == 0 {
+				printLimitReached(database, limits)
+				return nil
+			}
+			limit = min(limit, left)
+		}
+
+		var notes []*note.Note
+		if limits.newAllowed() {
+			notes, err = db.GetDueNotesWeighted(database, limit)
+		} else {
+			notes, err = db.GetDueNotesWeightedExcludingNew(database, limit)
+		}
 		if err != nil {
 			if err == sql.ErrNoRows || len(notes) == 0 {
 				fmt.Println("🎉 No notes are due for review. Great job!")
@@ -45,6 +69,10 @@ Use --question-type to specify the type of questions generated:
 			}
 			return err
 		}
+		if len(notes) == 0 {
+			fmt.Println("🎉 No notes are due for review. Great job!")
+			return nil
+		}
 
 		// Convert string to QuestionType
 		qType := study.QuestionType(mixQuestionType)
@@ -52,38 +80,88 @@ Use --question-type to specify the type of questions generated:
 			qType = study.QuestionTypeMixed // Default to mixed
 		}
 
+		useCache := mixCache && !mixNoCache
+
 		fmt.Printf("--- Starting Interleaved Review Session (%d notes) ---\n", len(notes))
-		reader := bufio.NewReader(os.Stdin)
+		ctx := handleInterrupts(cmd)
+		reader := bufio.NewReader(newStdinFeed(ctx, os.Stdin))
+		stats := study.NewSessionStats()
 
 		// Loop through each randomly selected note
 		for i, dueNote := range notes {
+			if ctx.Err() != nil {
+				break
+			}
 			fmt.Printf("\n--- Card %d of %d ---\n", i+1, len(notes))
 
-			fmt.Printf("🧠 Generating %s question...\n", qType)
-			question, err := study.GenerateQuestion(dueNote, qType)
-			if err != nil {
-				fmt.Printf("Error generating question for %s: %v. Skipping.\n", dueNote.Title, err)
+			wasNew := study.IsNew(dueNote)
+			if wasNew && !limits.newAllowed() {
+				fmt.Printf("🛑 Daily new-card limit reached (%d/%d). Skipping %s.\n", limits.newDone, limits.maxNew, dueNote.Title)
 				continue
 			}
 
+			question, conciseAnswer, cacheHit := "", "", false
+			card, hasCard := pickCard(dueNote)
+			if hasCard {
+				question, conciseAnswer, cacheHit = card.Front, card.Back, true
+			} else if useCache {
+				question, conciseAnswer, cacheHit = cachedQuestionAnswer(database, dueNote, qType)
+			}
+
+			fullNote := false
+			switch {
+			case hasCard:
+				fmt.Printf("🃏 Using a card from the note (%d in total)...\n", len(dueNote.Cards))
+			case cacheHit:
+				fmt.Printf("⚡ Using cached %s question...\n", qType)
+			case offline:
+				fmt.Println("📴 Offline: recall what you can, then check it against the note.")
+				question, conciseAnswer, fullNote = offlineQuestionAnswer(dueNote)
+				cacheHit = true
+			default:
+				fmt.Printf("🧠 Generating %s question...\n", qType)
+				question, err = study.GenerateQuestion(dueNote, qType)
+				if err != nil {
+					fmt.Printf("Error generating question for %s: %v. Skipping.\n", dueNote.Title, err)
+					continue
+				}
+			}
+
+			shown := time.Now()
 			fmt.Printf("\n🤔 Question: %s\n", question)
 			fmt.Print("   (Press Enter to reveal concise answer)")
 			_, _ = reader.ReadString('\n')
-
-			fmt.Println("\n🤖 Generating concise answer...")
-			conciseAnswer, err := study.GenerateAnswer(question, dueNote)
-			if err != nil {
-				fmt.Printf("Error generating answer for %s: %v. Skipping.\n", dueNote.Title, err)
-				continue
+			if ctx.Err() != nil {
+				break
+			}
+			think := time.Since(shown)
+
+			if !cacheHit {
+				fmt.Println("\n🤖 Generating concise answer...")
+				conciseAnswer, err = study.GenerateAnswer(question, dueNote, study.AnswerMedium)
+				if err != nil {
+					fmt.Printf("Error generating answer for %s: %v. Skipping.\n", dueNote.Title, err)
+					continue
+				}
+				if useCache {
+					storeQuestionAnswer(database, dueNote, qType, question, conciseAnswer)
+				}
 			}
 
-			fmt.Println("\n💡 Concise Answer:")
-			fmt.Println("-----------------------------------------------------------")
-			fmt.Println(conciseAnswer)
-			fmt.Println("-----------------------------------------------------------")
+			if fullNote {
+				fmt.Println("\n📖 Full Note:")
+				fmt.Println("-----------------------------------------------------------")
+				printMarkdown(dueNote.Content)
+				fmt.Println("-----------------------------------------------------------")
+			} else {
+				fmt.Println("\n💡 Concise Answer:")
+				fmt.Println("-----------------------------------------------------------")
+				fmt.Println(conciseAnswer)
+				fmt.Println("-----------------------------------------------------------")
+			}
 
 			// Only ask about showing the full note if not in brief mode
-			if !mixBrief {
+			if !mixBrief && !fullNote {
 				fmt.Print("\n📖 See full note? (y/n): ")
 				showNote, _ := reader.ReadString('\n')
 				showNote = strings.TrimSpace(strings.ToLower(showNote))
@@ -92,38 +170,35 @@ Use --question-type to specify the type of questions generated:
 					fmt.Println("\n📖 Full Note Context:")
 					fmt.Println("-----------------------------------------------------------")
 
-					renderedContent, err := renderMarkdown(dueNote.Content)
-					if err != nil {
-						fmt.Println("Error rendering markdown, showing raw content:")
-						fmt.Println(dueNote.Content)
-					} else {
-						fmt.Println(renderedContent)
-					}
+					printMarkdown(dueNote.Content)
 
 					fmt.Println("-----------------------------------------------------------")
 				}
 			}
 
-			var rating int
-			for {
-				fmt.Print("\nHow well did you recall this? (1=Again, 2=Good, 3=Easy): ")
-				input, _ := reader.ReadString('\n')
-				rating, err = strconv.Atoi(strings.TrimSpace(input))
-				if err == nil && (rating >= 1 && rating <= 3) {
-					break
+			rating, err := readRating(reader, false)
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Println("Input closed; ending the session. This card was not rated.")
 				}
-				fmt.Println("Invalid input. Please enter 1, 2, or 3.")
+				break
 			}
 
-			study.UpdateSRSData(dueNote, rating)
-			if err := db.UpdateNoteSRS(database, dueNote); err != nil {
-				return fmt.Errorf("failed to update note schedule: %w", err)
+			if err := saveRating(database, dueNote, rating, think); err != nil {
+				return err
 			}
-			days := int(math.Ceil(time.Until(dueNote.DueDate).Hours() / 24))
-			fmt.Printf("✓ Scheduled for review in about %d day(s).\n", days)
+			stats.Record(rating)
+			limits.record(database, wasNew)
+			fmt.Printf("✓ Scheduled for review in about %s.\n", untilDue(dueNote.DueDate))
 		}
 
-		fmt.Println("\n--- Interleaved session complete! ---")
+		if ctx.Err() != nil {
+			printInterrupted(stats.Reviewed())
+		} else {
+			fmt.Println("\n--- Interleaved session complete! ---")
+		}
+		stats.Finish()
+		printSessionSummary(stats)
 		return nil
 	},
 }
@@ -132,4 +207,9 @@ func init() {
 	rootCmd.AddCommand(mixCmd)
 	mixCmd.Flags().BoolVar(&mixBrief, "brief", false, "Skip showing full note, only show Q&A")
 	mixCmd.Flags().StringVar(&mixQuestionType, "question-type", "mixed", "Type of question to generate: factual, conceptual, application, mixed")
+	mixCmd.Flags().IntVar(&mixMaxReviews, "max-reviews", 0, "Stop once this many reviews were done today (0 = unlimited)")
+	mixCmd.Flags().IntVar(&mixMaxNew, "max-new", 0, "Show at most this many never-reviewed notes per day (0 = unlimited)")
+	mixCmd.Flags().BoolVar(&mixCache, "cache", false, "Reuse previously generated questions/answers when available")
+	mixCmd.Flags().BoolVar(&mixNoCache, "no-cache", false, "Always ask the LLM, ignoring --cache")
+	mixCmd.Flags().BoolVar(&offline, "offline", false, offlineFlagUsage)
 }
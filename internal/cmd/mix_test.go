@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMixInterruptedBetweenCards(t *testing.T) {
+	for _, rated := range []int{0, 1} {
+		t.Run(fmt.Sprintf("after %d", rated), func(t *testing.T) {
+			database := testDB(t)
+			for _, name := range []string{"first", "second", "third"} {
+				addCardNote(t, database, name, -time.Hour)
+			}
+
+			output, err := runInterrupted(t, database, mixCmd, rated, "mix", "--no-preflight", "--brief")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(reviewedNotes(t, database)); got != rated {
+				t.Errorf("%d card(s) rated, want %d", got, rated)
+			}
+			if got := strings.Count(output, "Concise Answer"); got != rated {
+				t.Errorf("%d answer(s) revealed, want %d:\n%s", got, rated, output)
+			}
+			if want := fmt.Sprintf("Session interrupted, %d card(s) reviewed", rated); !strings.Contains(output, want) {
+				t.Errorf("output is missing %q:\n%s", want, output)
+			}
+			if strings.Contains(output, "Interleaved session complete") {
+				t.Errorf("an interrupted session was reported complete:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestMixSessionSummary(t *testing.T) {
+	database := testDB(t)
+	for _, name := range []string{"first", "second", "third"} {
+		addCardNote(t, database, name, -time.Hour)
+	}
+	// Reveal and rate each card: Again, Good, Easy.
+	withStdin(t, "\n1\n\n2\n\n3\n")
+	var err error
+	output := captureStdout(t, func() { err = executeRoot(t, "mix", "--no-preflight", "--brief") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, summary, found := strings.Cut(output, "Session Summary")
+	if !found {
+		t.Fatalf("no session summary:\n%s", output)
+	}
+	for _, want := range []string{"Reviewed       3", "Again          1", "Good           1", "Easy           1"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary is missing %q:\n%s", want, summary)
+		}
+	}
+}
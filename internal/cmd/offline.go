@@ -0,0 +1,28 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+// offline makes review and mix run without the LLM: notes are reviewed from
+// their cards, cached questions, or summaries, or else reread in full.
+var offline bool
+
+// offlineFlagUsage is the help text of --offline, shared by review and mix.
+const offlineFlagUsage = "Review without the LLM: use the note's cards or summary, or show the whole note to reread"
+
+// offlineQuestionAnswer returns a recall prompt for a note that has no cards. The
+// answer is its summary sections; when it has none, fullNote is true and the
+// whole note should be shown instead.
+func offlineQuestionAnswer(n *note.Note) (question, answer string, fullNote bool) {
+	question = fmt.Sprintf("What do you remember about %q?", n.Title)
+	if summary := study.ExtractSummary(n.Content); summary != n.Content {
+		return question, strings.TrimSpace(summary), false
+	}
+	return question, "", true
+}
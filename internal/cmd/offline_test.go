@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+)
+
+func TestOfflineQuestionAnswer(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantAnswer   string
+		wantFullNote bool
+	}{
+		{"summary", "# Maps\n\n## Summary\nHash tables.\n\n## Details\nBuckets.\n", "Hash tables.", false},
+		{"no summary", "# Maps\n\nBuckets and overflow chains.\n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := addTestNote(t, testDB(t), "/notes/maps.md", tt.content)
+			question, answer, fullNote := offlineQuestionAnswer(n)
+			if question != `What do you remember about "maps.md"?` || answer != tt.wantAnswer || fullNote != tt.wantFullNote {
+				t.Errorf("offlineQuestionAnswer() = %q, %q, %v; want the recall question, %q, %v", question, answer, fullNote, tt.wantAnswer, tt.wantFullNote)
+			}
+		})
+	}
+}
+
+// countRequests points the Ollama provider at a server that counts, and fails, every request.
+func countRequests(t *testing.T) *atomic.Int32 {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "offline sessions must not call the model", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	useConfigFile(t, &config.Config{OllamaHost: server.URL})
+	return &requests
+}
+
+func TestOfflineSessionsMakeNoRequests(t *testing.T) {
+	// Each note is reviewed a different way: from its card, its summary, or reread in full.
+	// Preflight is left on, since --offline has to skip it too.
+	for _, tt := range []struct {
+		name  string
+		args  []string
+		input string
+	}{
+		{"review", []string{"review", "--offline", "--count", "3"}, strings.Repeat("\nn\n2\n", 3)},
+		{"mix", []string{"mix", "--offline", "--brief"}, strings.Repeat("\nn\n2\n", 3)},
+		{"review --batch", []string{"review", "--offline", "--batch"}, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			database := testDB(t)
+			addCardNote(t, database, "card", -3*time.Hour)
+			addTestNote(t, database, "/notes/summary.md", "# Summary\n\n## Summary\nRemember this.\n\n## Details\nMore.\n")
+			addTestNote(t, database, "/notes/plain.md", "# Plain\n\nJust text.\n")
+			requests := countRequests(t)
+
+			withStdin(t, tt.input)
+			var err error
+			output := captureStdout(t, func() { err = executeRoot(t, tt.args...) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := requests.Load(); n != 0 {
+				t.Errorf("%v made %d request(s) to the model", tt.args, n)
+			}
+			for _, want := range []string{"What is card?", "Remember this.", "Just text."} {
+				if !strings.Contains(output, want) {
+					t.Errorf("%v output is missing %q:\n%s", tt.args, want, output)
+				}
+			}
+			if tt.input != "" {
+				if got := len(reviewedNotes(t, database)); got != 3 {
+					t.Errorf("%v rated %d notes, want 3:\n%s", tt.args, got, output)
+				}
+			}
+		})
+	}
+}
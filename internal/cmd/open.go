@@ -0,0 +1,90 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var openEdit bool
+
+var openCmd = &cobra.Command{
+	Use:   "open [topic]",
+	Short: "Open a note's file in your viewer or editor",
+	Long: `Finds the note by title, filename, or alias and opens its file with the
+system's default application (open on macOS, start on Windows, xdg-open
+elsewhere). With --edit, opens it in $EDITOR instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		topic := args[0]
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		n, err := lookupNote(database, topic)
+		if err != nil || n == nil {
+			return err
+		}
+
+		if _, err := os.Stat(n.Filename); os.IsNotExist(err) {
+			return fmt.Errorf("%s no longer exists; it may have been moved or deleted. Run 'neuron import' to sync your notes", n.Filename)
+		}
+		return openFile(n.Filename, openEdit)
+	},
+}
+
+// openFile opens path with the platform opener, or in $EDITOR when edit is set.
+// It is a variable so the command can be exercised without launching anything.
+var openFile = func(path string, edit bool) error {
+	command, err := openerCommand(path, edit)
+	if err != nil {
+		return err
+	}
+	opener := exec.Command(command[0], command[1:]...)
+	opener.Stdin = os.Stdin
+	opener.Stdout = os.Stdout
+	opener.Stderr = os.Stderr
+	if edit {
+		// Wait for the editor, which takes over the terminal.
+		err = opener.Run()
+	} else {
+		err = opener.Start()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s with %s: %w", path, command[0], err)
+	}
+	return nil
+}
+
+// openerCommand returns the command line that opens path.
+func openerCommand(path string, edit bool) ([]string, error) {
+	if edit {
+		editor := strings.Fields(os.Getenv("EDITOR"))
+		if len(editor) == 0 {
+			return nil, fmt.Errorf("--edit needs $EDITOR to be set, e.g. export EDITOR=vim")
+		}
+		return append(editor, path), nil
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open", path}, nil
+	case "windows":
+		// start is a cmd builtin; its first quoted argument is the window title.
+		return []string{"cmd", "/c", "start", "", path}, nil
+	default:
+		return []string{"xdg-open", path}, nil
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	addIndexFlag(openCmd)
+	openCmd.Flags().BoolVarP(&openEdit, "edit", "e", false, "Open the note in $EDITOR instead of the default application")
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOpenerCommand(t *testing.T) {
+	var opener []string
+	switch runtime.GOOS {
+	case "darwin":
+		opener = []string{"open", "note.md"}
+	case "windows":
+		opener = []string{"cmd", "/c", "start", "", "note.md"}
+	default:
+		opener = []string{"xdg-open", "note.md"}
+	}
+
+	tests := []struct {
+		name    string
+		editor  string
+		edit    bool
+		want    []string
+		wantErr bool
+	}{
+		{name: "default application", editor: "vim", want: opener},
+		{name: "editor", editor: "vim", edit: true, want: []string{"vim", "note.md"}},
+		{name: "editor with arguments", editor: "code --wait", edit: true, want: []string{"code", "--wait", "note.md"}},
+		{name: "no editor", editor: "", edit: true, wantErr: true},
+		{name: "blank editor", editor: "  ", edit: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("EDITOR", tt.editor)
+			got, err := openerCommand("note.md", tt.edit)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("openerCommand() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("openerCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// stubOpenFile replaces openFile for the rest of the test and records each call.
+func stubOpenFile(t *testing.T) *[]string {
+	t.Helper()
+	var calls []string
+	original := openFile
+	openFile = func(path string, edit bool) error {
+		mode := "open"
+		if edit {
+			mode = "edit"
+		}
+		calls = append(calls, mode+" "+path)
+		return nil
+	}
+	t.Cleanup(func() { openFile = original })
+	return &calls
+}
+
+func TestOpenCommand(t *testing.T) {
+	database := testDB(t)
+	path := filepath.Join(t.TempDir(), "lambda.md")
+	if err := os.WriteFile(path, []byte("# Lambda\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	addTestNote(t, database, path, "# Lambda\n")
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"open", "--no-preflight", "lambda.md"}, want: "open " + path},
+		{args: []string{"open", "--no-preflight", "--edit", "lambda.md"}, want: "edit " + path},
+	} {
+		calls := stubOpenFile(t)
+		if err := executeRoot(t, tt.args...); err != nil {
+			t.Fatalf("%v: %v", tt.args, err)
+		}
+		if len(*calls) != 1 || (*calls)[0] != tt.want {
+			t.Errorf("%v opened %q, want [%s]", tt.args, *calls, tt.want)
+		}
+		resetFlags(rootCmd)
+	}
+}
+
+func TestOpenCommandMissingFile(t *testing.T) {
+	database := testDB(t)
+	path := filepath.Join(t.TempDir(), "gone.md")
+	addTestNote(t, database, path, "# Gone\n")
+	calls := stubOpenFile(t)
+
+	err := executeRoot(t, "open", "--no-preflight", "gone.md")
+	if err == nil || !strings.Contains(err.Error(), "no longer exists") {
+		t.Errorf("open on a deleted file returned %v, want a 'no longer exists' error", err)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("open on a deleted file still opened %q", *calls)
+	}
+}
+
+func TestOpenFileRunsEditor(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub editor is a shell script")
+	}
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "opened")
+	editor := filepath.Join(dir, "editor")
+	script := "#!/bin/sh\necho \"$1\" > " + marker + "\n"
+	if err := os.WriteFile(editor, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", editor)
+
+	if err := openFile("note.md", true); err != nil {
+		t.Fatal(err)
+	}
+	// The editor is waited for, so the marker is there as soon as openFile returns.
+	got, err := os.ReadFile(marker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(got)) != "note.md" {
+		t.Errorf("editor was given %q, want note.md", got)
+	}
+}
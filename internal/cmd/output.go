@@ -0,0 +1,62 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonOutput makes read-only commands print JSON instead of formatted text.
+var jsonOutput bool
+
+// jsonAnnotation marks, in a command's Annotations, that it supports --json.
+const jsonAnnotation = "supports-json"
+
+// supportsJSON is the Annotations value for commands that can print JSON.
+var supportsJSON = map[string]string{jsonAnnotation: "true"}
+
+// Outputter writes a command's result, either formatted for people or as JSON for scripts.
+type Outputter interface {
+	// Print writes data. The text outputter calls text, which prints the
+	// formatted view; the JSON outputter encodes data and ignores text.
+	Print(data any, text func()) error
+}
+
+// textOutputter prints the formatted view, colors and all.
+type textOutputter struct{}
+
+func (textOutputter) Print(_ any, text func()) error {
+	text()
+	return nil
+}
+
+// jsonOutputter encodes results as indented JSON.
+type jsonOutputter struct {
+	w io.Writer
+}
+
+func (o jsonOutputter) Print(data any, _ func()) error {
+	encoder := json.NewEncoder(o.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// newOutputter returns the outputter selected by --json.
+func newOutputter() Outputter {
+	if jsonOutput {
+		return jsonOutputter{w: os.Stdout}
+	}
+	return textOutputter{}
+}
+
+// checkJSONSupport rejects --json for commands that can't produce JSON, such as interactive sessions.
+func checkJSONSupport(cmd *cobra.Command) error {
+	if jsonOutput && cmd.Annotations[jsonAnnotation] == "" {
+		return fmt.Errorf("--json is not supported by '%s'; it works with list, due, links, search, stats, and review --batch", cmd.CommandPath())
+	}
+	return nil
+}
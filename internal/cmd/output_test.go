@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+// decodeJSON decodes output, which must be exactly one JSON value with no
+// fields that v doesn't know about.
+func decodeJSON(t *testing.T, output string, v any) {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(output))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("output isn't the expected JSON: %v\n%s", err, output)
+	}
+	if decoder.More() {
+		t.Fatalf("output has more than one JSON value:\n%s", output)
+	}
+}
+
+// jsonOf runs the command with --json and returns what it printed.
+func jsonOf(t *testing.T, args ...string) string {
+	t.Helper()
+	var err error
+	output := captureStdout(t, func() { err = executeRoot(t, append(args, "--json", "--no-preflight")...) })
+	if err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+	resetFlags(rootCmd)
+	return output
+}
+
+func TestJSONOutputUnmarshals(t *testing.T) {
+	database := testDB(t)
+	alpha := addCardNote(t, database, "alpha", -time.Hour, "go")
+	addCardNote(t, database, "beta", 48*time.Hour)
+	alpha.Content += "\nSee [[beta]] and [[missing]].\n"
+	alpha.Links = []string{"beta", "missing"}
+	if _, err := db.InsertNote(database, alpha); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.LogReview(database, alpha.ID, study.RatingGood, 3*time.Second, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("list", func(t *testing.T) {
+		var entries []listEntry
+		decodeJSON(t, jsonOf(t, "list"), &entries)
+		if len(entries) != 2 || entries[0].Title != "alpha.md" || len(entries[0].Tags) != 1 || entries[1].Tags == nil {
+			t.Errorf("list = %+v, want alpha tagged go and beta with no tags", entries)
+		}
+	})
+	t.Run("due", func(t *testing.T) {
+		var summary dueSummary
+		decodeJSON(t, jsonOf(t, "due", "--list"), &summary)
+		if summary.Today != 1 || len(summary.DueNow) != 1 || summary.DueNow[0].Title != "alpha.md" {
+			t.Errorf("due = %+v, want alpha due now", summary)
+		}
+	})
+	t.Run("links", func(t *testing.T) {
+		var result linksResult
+		decodeJSON(t, jsonOf(t, "links", "alpha.md"), &result)
+		want := []linkTarget{{Target: "beta", Title: "beta.md"}, {Target: "missing"}}
+		if result.Title != "alpha.md" || len(result.Links) != 2 || result.Links[0] != want[0] || result.Links[1] != want[1] {
+			t.Errorf("links = %+v, want links %+v", result, want)
+		}
+		decodeJSON(t, jsonOf(t, "links", "beta.md"), &result)
+		if len(result.Backlinks) != 1 || result.Backlinks[0] != "alpha.md" || result.Links == nil {
+			t.Errorf("links of beta = %+v, want a backlink from alpha", result)
+		}
+	})
+	t.Run("search", func(t *testing.T) {
+		var page searchPage
+		decodeJSON(t, jsonOf(t, "search", "What is beta"), &page)
+		if page.Total != 1 || len(page.Results) != 1 || page.Results[0].Title != "beta.md" {
+			t.Errorf("search = %+v, want beta", page)
+		}
+		decodeJSON(t, jsonOf(t, "search", "nothing-matches-this"), &page)
+		if page.Total != 0 || page.Results == nil {
+			t.Errorf("search with no matches = %+v, want an empty result list", page)
+		}
+	})
+	t.Run("stats", func(t *testing.T) {
+		var summary statsSummary
+		decodeJSON(t, jsonOf(t, "stats"), &summary)
+		if summary.TotalNotes != 2 || summary.TotalReviews != 1 || summary.ThinkTime.Reviews != 1 {
+			t.Errorf("stats = %+v, want 2 notes and 1 timed review", summary)
+		}
+		var weeks []weekRetention
+		decodeJSON(t, jsonOf(t, "stats", "--retention"), &weeks)
+		if len(weeks) == 0 || weeks[len(weeks)-1].Reviews != 1 {
+			t.Errorf("stats --retention = %+v, want this week's review", weeks)
+		}
+	})
+	t.Run("review --batch", func(t *testing.T) {
+		var cards []batchCard
+		decodeJSON(t, jsonOf(t, "review", "--batch"), &cards)
+		if len(cards) != 1 || cards[0].Question != "What is alpha?" || cards[0].Answer != "The answer." {
+			t.Errorf("review --batch = %+v, want alpha's card", cards)
+		}
+	})
+}
+
+func TestJSONOutputIsRejectedByInteractiveCommands(t *testing.T) {
+	testDB(t)
+	for _, args := range [][]string{{"quiz"}, {"review"}} {
+		err := executeRoot(t, append(args, "--json", "--no-preflight")...)
+		if err == nil || !strings.Contains(err.Error(), "--json") {
+			t.Errorf("%v --json returned %v, want a --json error", args, err)
+		}
+		resetFlags(rootCmd)
+	}
+}
@@ -0,0 +1,54 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+// noPreflight skips checking that the model is available before an LLM session.
+var noPreflight bool
+
+// llmAnnotation marks, in a command's Annotations, that it talks to the LLM
+// and should check the model is available before starting.
+const llmAnnotation = "uses-llm"
+
+// usesLLM is the Annotations value for commands that talk to the LLM.
+var usesLLM = map[string]string{llmAnnotation: "true"}
+
+// runPreflight checks the model before commands annotated as using the LLM.
+func runPreflight(cmd *cobra.Command) error {
+	if noPreflight || offline || cmd.Annotations[llmAnnotation] == "" {
+		return nil
+	}
+	if err := preflightModel(); err != nil {
+		cmd.SilenceUsage = true // the command line was fine; the model isn't
+		return err
+	}
+	return nil
+}
+
+// preflightModel makes sure the LLM server is reachable and offers the model, so a
+// session fails up front with a helpful message instead of after the first prompt.
+func preflightModel() error {
+	if noPreflight {
+		return nil
+	}
+	model := study.ActiveModel()
+	if model == "" {
+		return nil // the provider can't tell us its model, so there is nothing to check
+	}
+	models, err := study.ListModels()
+	if err != nil {
+		return fmt.Errorf("%w\n(skip this check with --no-preflight)", err)
+	}
+	if study.HasModel(models, model) {
+		return nil
+	}
+	if providerName == study.ProviderOllama {
+		return fmt.Errorf("the model %s is not pulled yet. Run 'ollama pull %s' and try again (or pass --no-preflight to skip this check)", model, model)
+	}
+	return fmt.Errorf("the model %s is not offered by the %s server (pass --no-preflight to skip this check)", model, providerName)
+}
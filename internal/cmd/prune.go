@@ -0,0 +1,73 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var pruneDryRun bool
+var pruneForce bool
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove notes whose files no longer exist",
+	Long: `Checks every note's file and removes the notes whose file is gone, along with
+their review history, without re-importing anything. It asks for confirmation
+first; pass --yes to skip the prompt, or --dry-run to only list them.
+
+If none of the files can be found, nothing is removed: that usually means the
+notes folder was moved or a drive isn't mounted.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		total := 0
+		missing, err := filterStoredFilenames(database, func(filename string) bool {
+			total++
+			_, err := os.Stat(filename)
+			return os.IsNotExist(err)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to check note files: %w", err)
+		}
+		if len(missing) == 0 {
+			fmt.Printf("✓ All %d note files exist. Nothing to prune.\n", total)
+			return nil
+		}
+		if len(missing) == total {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("none of the %d note files exist; refusing to remove them all. Check that your notes folder is where it was imported from", total)
+		}
+
+		if pruneDryRun {
+			fmt.Println("--- Dry run: no changes were made ---")
+			for _, filename := range missing {
+				fmt.Printf("- Would remove: %s\n", filepath.Base(filename))
+			}
+			fmt.Printf("\n%d of %d note(s) point to files that no longer exist.\n", len(missing), total)
+			return nil
+		}
+		if !pruneForce && !confirmDeletion(bufio.NewReader(os.Stdin), missing, "point to files that no longer exist") {
+			fmt.Println("Nothing was removed.")
+			return nil
+		}
+		removed := deleteNotes(database, missing)
+		fmt.Printf("\nPruned %d note(s).\n", removed)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "List the notes that would be removed without removing them")
+	pruneCmd.Flags().BoolVarP(&pruneForce, "yes", "y", false, "Remove without asking")
+}
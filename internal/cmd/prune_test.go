@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+// loggedReviews returns how many reviews of noteID are logged.
+func loggedReviews(t *testing.T, database *sql.DB, noteID int) int {
+	t.Helper()
+	var count int
+	if err := database.QueryRow(`SELECT count(*) FROM review_log WHERE note_id = ?;`, noteID).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestPruneRemovesMissingNotesAndTheirHistory(t *testing.T) {
+	database := testDB(t)
+	dir := t.TempDir()
+	kept := addTestNote(t, database, writeNoteFile(t, dir, "kept.md", "# Kept"), "# Kept")
+	gone := addTestNote(t, database, filepath.Join(dir, "gone.md"), "# Gone")
+	for _, n := range []int{kept.ID, gone.ID} {
+		if err := db.LogReview(database, n, 2, time.Second, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := executeRoot(t, "prune", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	filenames := storedFilenames(t)
+	if filenames[gone.Filename] || !filenames[kept.Filename] {
+		t.Errorf("stored %v, want only %s", filenames, kept.Filename)
+	}
+	if got := loggedReviews(t, database, gone.ID); got != 0 {
+		t.Errorf("%d reviews of the pruned note are left", got)
+	}
+	if got := loggedReviews(t, database, kept.ID); got != 1 {
+		t.Errorf("the kept note has %d reviews, want 1", got)
+	}
+}
+
+func TestDeleteNotesCleansUpWithoutForeignKeys(t *testing.T) {
+	// A copy of the schema, opened with foreign keys off as database.foreign_keys: false does.
+	path := filepath.Join(t.TempDir(), "neuron.db")
+	if err := db.Backup(testDB(t), path); err != nil {
+		t.Fatal(err)
+	}
+	database, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=off")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	n := addTestNote(t, database, "/notes/gone.md", "# Gone")
+	if err := db.LogReview(database, n.ID, 2, time.Second, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveCachedQA(database, n.ID, "factual", "hash", "Q?", "A."); err != nil {
+		t.Fatal(err)
+	}
+	if removed := deleteNotes(database, []string{n.Filename}); removed != 1 {
+		t.Fatalf("removed %d notes, want 1", removed)
+	}
+	if got := loggedReviews(t, database, n.ID); got != 0 {
+		t.Errorf("%d reviews of the removed note are left", got)
+	}
+	if _, _, err := db.GetCachedQA(database, n.ID, "factual", "hash"); err != sql.ErrNoRows {
+		t.Errorf("cached question of the removed note is left: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var quizCount int
+var quizTag string
+var quizQuestionType string
+var quizSchedule bool
+
+// quizResult is one graded quiz question.
+type quizResult struct {
+	note   *note.Note
+	score  int  // 0-100
+	scored bool // false when the model couldn't grade the answer
+}
+
+// quizRating maps a quiz score to the recall rating --schedule saves.
+func quizRating(score int) int {
+	switch {
+	case score >= 85:
+		return study.RatingEasy
+	case score >= 60:
+		return study.RatingGood
+	}
+	return study.RatingAgain
+}
+
+// quizPercentage averages the graded scores. It reports false when nothing was graded.
+func quizPercentage(results []quizResult) (int, bool) {
+	total, graded := 0, 0
+	for _, r := range results {
+		if r.scored {
+			total += r.score
+			graded++
+		}
+	}
+	if graded == 0 {
+		return 0, false
+	}
+	return (total + graded/2) / graded, true
+}
+
+var quizCmd = &cobra.Command{
+	Use:   "quiz",
+	Short: "Take a graded quiz of a fixed number of questions",
+	Long: `Asks --count questions drawn from the whole deck, or from the notes with a
+tag with --tag, whether they are due or not. Type an answer to each; the model
+grades it from 0 to 100 against its own answer, and a report card with your
+overall percentage is printed at the end. Press Enter to skip a question, which
+scores 0.
+
+A quiz doesn't change your review schedule unless you pass --schedule: each
+quizzed note is then rated by its lowest score (85+ Easy, 60+ Good, below that
+Again).`,
+	Args:        cobra.NoArgs,
+	Annotations: usesLLM,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if quizCount < 1 {
+			return fmt.Errorf("--count must be at least 1, got %d", quizCount)
+		}
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		var notes []*note.Note
+		if quizTag != "" {
+			notes, err = db.GetNotesByTag(database, quizTag, quizCount)
+		} else {
+			notes, err = db.GetRandomNotes(database, quizCount)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes: %w", err)
+		}
+		if len(notes) == 0 {
+			if quizTag != "" {
+				fmt.Printf("No notes are tagged '%s'.\n", quizTag)
+			} else {
+				fmt.Println("You have no notes to quiz on. Run 'neuron import' first.")
+			}
+			return nil
+		}
+
+		qType := study.QuestionType(quizQuestionType)
+		if qType == "" {
+			qType = study.QuestionTypeMixed
+		}
+
+		ctx := handleInterrupts(cmd)
+		reader := bufio.NewReader(newStdinFeed(ctx, os.Stdin))
+		fmt.Printf("--- Quiz: %d questions ---\n", quizCount)
+		var results []quizResult
+		for i := 0; i < quizCount && ctx.Err() == nil; i++ {
+			// With fewer notes than questions, notes come round again with a
+			// different question each time.
+			n := notes[i%len(notes)]
+			attempt := i/len(notes) + 1
+			fmt.Printf("\n--- Question %d of %d ---\n", i+1, quizCount)
+			fmt.Printf("🧠 Generating %s question...\n", qType)
+			question, err := study.GenerateQuestionWithVariation(n, qType, attempt)
+			if err != nil {
+				return fmt.Errorf("failed to generate question for %s: %w", n.Title, err)
+			}
+			color.New(color.FgCyan).Printf("\n🤔 %s\n", question)
+			fmt.Print("\nYour answer (Enter to skip): ")
+			answer, err := readLine(reader)
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Println("\nInput closed; ending the quiz.")
+				}
+				break
+			}
+			result := gradeQuizAnswer(n, question, strings.TrimSpace(answer))
+			results = append(results, result)
+		}
+
+		if ctx.Err() != nil {
+			printInterrupted(len(results))
+		}
+		printReportCard(results, quizCount)
+		if quizSchedule {
+			return scheduleQuizNotes(results)
+		}
+		return nil
+	},
+}
+
+// gradeQuizAnswer scores answer against the model's own answer and shows both.
+func gradeQuizAnswer(n *note.Note, question, answer string) quizResult {
+	result := quizResult{note: n}
+	if answer == "" {
+		result.scored = true
+		fmt.Println("Skipped: 0/100.")
+		return result
+	}
+	fmt.Println("\n🔍 Grading...")
+	reference, err := study.GenerateAnswer(question, n, study.AnswerMedium)
+	if err != nil {
+		fmt.Printf("Could not generate a reference answer: %v. This question isn't graded.\n", err)
+		return result
+	}
+	score, reason, err := study.ScoreAnswer(answer, reference, question)
+	if err != nil {
+		fmt.Printf("Could not grade the answer: %v. This question isn't graded.\n", err)
+	} else {
+		result.score, result.scored = score, true
+		color.New(color.FgCyan, color.Bold).Printf("\n🎯 %d/100", score)
+		if reason != "" {
+			fmt.Printf(" - %s", reason)
+		}
+		fmt.Println()
+	}
+	fmt.Print("💡 Answer: ")
+	color.New(color.FgMagenta).Println(reference)
+	return result
+}
+
+// printReportCard lists each question's score and the overall percentage.
+func printReportCard(results []quizResult, asked int) {
+	if len(results) == 0 {
+		return
+	}
+	color.New(color.FgCyan, color.Bold).Println("\n📋 Report Card")
+	for i, r := range results {
+		score := "  n/a"
+		if r.scored {
+			score = fmt.Sprintf("%5d", r.score)
+		}
+		fmt.Printf("  %2d. %s  %s\n", i+1, score, r.note.Title)
+	}
+	if percentage, ok := quizPercentage(results); ok {
+		fmt.Printf("\n  Overall: %d%% over %d of %d questions\n", percentage, len(results), asked)
+	}
+}
+
+// scheduleQuizNotes rates every quizzed note by its lowest graded score.
+func scheduleQuizNotes(results []quizResult) error {
+	database, err := db.GetDB()
+	if err != nil {
+		return err
+	}
+	lowest := make(map[int]int)
+	var order []*note.Note
+	for _, r := range results {
+		if !r.scored {
+			continue
+		}
+		score, seen := lowest[r.note.ID]
+		if !seen {
+			order = append(order, r.note)
+		}
+		if !seen || r.score < score {
+			lowest[r.note.ID] = r.score
+		}
+	}
+	for _, n := range order {
+		if err := saveRating(database, n, quizRating(lowest[n.ID]), 0); err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s is scheduled for review in about %s.\n", n.Title, untilDue(n.DueDate))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(quizCmd)
+	quizCmd.Flags().IntVar(&quizCount, "count", 10, "Number of questions")
+	quizCmd.Flags().StringVar(&quizTag, "tag", "", "Only ask about notes with this tag")
+	quizCmd.Flags().StringVar(&quizQuestionType, "question-type", "mixed", "Type of question to generate: factual, conceptual, application, mixed")
+	quizCmd.Flags().BoolVar(&quizSchedule, "schedule", false, "Update each quizzed note's review schedule from its lowest score")
+}
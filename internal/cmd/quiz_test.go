@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+func TestQuizRating(t *testing.T) {
+	tests := []struct {
+		score int
+		want  int
+	}{
+		{100, study.RatingEasy},
+		{85, study.RatingEasy},
+		{84, study.RatingGood},
+		{60, study.RatingGood},
+		{59, study.RatingAgain},
+		{0, study.RatingAgain},
+	}
+	for _, tt := range tests {
+		if got := quizRating(tt.score); got != tt.want {
+			t.Errorf("quizRating(%d) = %d, want %d", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestQuizPercentage(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []quizResult
+		want    int
+		wantOK  bool
+	}{
+		{name: "none", results: nil, wantOK: false},
+		{name: "nothing graded", results: []quizResult{{score: 0}, {score: 0}}, wantOK: false},
+		{name: "rounds to nearest", results: []quizResult{{score: 90, scored: true}, {score: 70, scored: true}, {score: 0, scored: true}}, want: 53, wantOK: true},
+		{name: "rounds half up", results: []quizResult{{score: 50, scored: true}, {score: 51, scored: true}}, want: 51, wantOK: true},
+		{name: "ungraded left out", results: []quizResult{{score: 80, scored: true}, {}}, want: 80, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := quizPercentage(tt.results)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("quizPercentage() = %d, %v; want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// studentAnswer finds the answer being graded in a ScoreAnswer prompt.
+var studentAnswer = regexp.MustCompile(`STUDENT'S ANSWER: (\w+)`)
+
+func TestQuizGradesAndSchedules(t *testing.T) {
+	database := testDB(t)
+	for _, name := range []string{"alpha", "beta", "gamma"} {
+		addTestNote(t, database, "/notes/"+name+".md", "# "+name+"\n\nbody")
+	}
+	// The mock scorer grades each typed answer by the number it spells out.
+	scores := map[string]string{"ninety": "90", "seventy": "70"}
+	provider := useFakeProvider(t, "")
+	provider.respond = func(prompt string) string {
+		if m := studentAnswer.FindStringSubmatch(prompt); m != nil {
+			return "SCORE: " + scores[m[1]] + "\nREASON: Graded."
+		}
+		return "Generated."
+	}
+	quizCount, quizSchedule = 3, true
+	quizCmd.SetContext(context.Background())
+	t.Cleanup(func() { quizCount, quizSchedule = 10, false })
+
+	// The third question is skipped, which scores 0 without asking the model.
+	withStdin(t, "ninety\nseventy\n\n")
+	var err error
+	output := captureStdout(t, func() { err = quizCmd.RunE(quizCmd, nil) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"🎯 90/100 - Graded.", "🎯 70/100 - Graded.", "Skipped: 0/100.", "Overall: 53% over 3 of 3 questions"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output is missing %q:\n%s", want, output)
+		}
+	}
+	_, card, found := strings.Cut(output, "Report Card")
+	if !found {
+		t.Fatalf("no report card:\n%s", output)
+	}
+	if got := regexp.MustCompile(`(?m)^\s+\d+\.\s+(\d+)`).FindAllStringSubmatch(card, -1); len(got) != 3 ||
+		got[0][1] != "90" || got[1][1] != "70" || got[2][1] != "0" {
+		t.Errorf("report card scores = %q, want 90, 70, 0:\n%s", got, card)
+	}
+
+	// --schedule rates each note by its score: 90 Easy, 70 Good, 0 Again.
+	rows, err := database.Query(`SELECT rating FROM review_log;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var ratings []int
+	for rows.Next() {
+		var rating int
+		if err := rows.Scan(&rating); err != nil {
+			t.Fatal(err)
+		}
+		ratings = append(ratings, rating)
+	}
+	slices.Sort(ratings)
+	if want := []int{study.RatingAgain, study.RatingGood, study.RatingEasy}; !slices.Equal(ratings, want) {
+		t.Errorf("scheduled ratings %v, want %v", ratings, want)
+	}
+}
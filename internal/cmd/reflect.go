@@ -3,13 +3,15 @@ package cmd
 
 import (
 	"bufio"
-	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
 	"github.com/soyomarvaldezg/neuron-cli/internal/study"
 	"github.com/spf13/cobra"
 )
@@ -25,7 +27,8 @@ This implements your "Red Team Pattern" by:
 1. Having you explain a concept in your own words
 2. Challenging your assumptions and exploring edge cases
 3. Encouraging critical thinking about limitations and alternatives`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		topic := args[0]
 
@@ -34,12 +37,8 @@ This implements your "Red Team Pattern" by:
 			return err
 		}
 
-		noteToReflect, err := db.GetNoteByTitleOrFilename(database, topic)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				fmt.Printf("Sorry, I couldn't find a note matching '%s'.\n", topic)
-				return nil
-			}
+		noteToReflect, err := lookupNote(database, topic)
+		if err != nil || noteToReflect == nil {
 			return err
 		}
 
@@ -52,131 +51,152 @@ This implements your "Red Team Pattern" by:
 
 		// Show available commands at start
 		helpColor := color.New(color.FgGreen)
-		helpColor.Println("\n💡 Tip: Type 'help' anytime to see available commands\n")
+		helpColor.Print("\n💡 Tip: Type 'help' anytime to see available commands\n\n")
 
-		// First round: Get initial explanation
+		err = runReflection(reader, noteToReflect, "End the session", "Reflection session ended. Great work on critical thinking!")
+		if errors.Is(err, io.EOF) {
+			fmt.Println("\nInput closed; ending the reflection session.")
+			return nil
+		}
+		return err
+	},
+}
+
+// runReflection runs the Red Team reflection loop on a note: it asks for an
+// explanation, then generates challenges round after round until the user stops.
+// quitHelp describes the quit command in the help text and endMessage is printed
+// when the user ends the session. It returns io.EOF if input runs out.
+func runReflection(reader *bufio.Reader, n *note.Note, quitHelp, endMessage string) error {
+	// First round: Get initial explanation, re-prompting after commands
+	var userExplanation string
+	for userExplanation == "" {
 		fmt.Print("\n📝 Explain the concept in your own words: ")
-		userExplanation, _ := reader.ReadString('\n')
-		userExplanation = strings.TrimSpace(userExplanation)
+		input, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		input = strings.TrimSpace(input)
 
 		// Check for special commands
-		if strings.ToLower(userExplanation) == "help" || strings.ToLower(userExplanation) == "?" {
-			helpColor := color.New(color.FgGreen)
-			helpColor.Println("\n🛠️  Available Commands:")
+		switch strings.ToLower(input) {
+		case "help", "?":
+			color.New(color.FgGreen).Println("\n🛠️  Available Commands:")
 			fmt.Println("  • 'help' or '?' - Show this help message")
 			fmt.Println("  • 'note' or 'show note' - Display the full note content")
-			fmt.Println("  • 'quit' or 'exit' - End the session")
+			fmt.Printf("  • 'quit' or 'exit' - %s\n", quitHelp)
 			fmt.Println("  • Type your explanation to begin reflection")
 			fmt.Println()
-			// Recursively call the function to get actual explanation
-			return cmd.RunE(cmd, args)
-		}
-
-		if strings.ToLower(userExplanation) == "quit" || strings.ToLower(userExplanation) == "exit" {
-			fmt.Println("Reflection session ended. Good work on critical thinking!")
+		case "quit", "exit":
+			fmt.Println(endMessage)
 			return nil
-		}
-
-		if strings.ToLower(userExplanation) == "note" || strings.ToLower(userExplanation) == "show note" {
+		case "note", "show note":
 			fmt.Println("\n📖 Full Note Content:")
 			fmt.Println("-----------------------------------------------------------")
-			rendered, err := renderMarkdown(noteToReflect.Content)
-			if err != nil {
-				fmt.Println(noteToReflect.Content)
-			} else {
-				fmt.Println(rendered)
-			}
+			printMarkdown(n.Content)
 			fmt.Println("-----------------------------------------------------------")
-			// Recursively call to get actual explanation
-			return cmd.RunE(cmd, args)
+		case "":
+			fmt.Println("Please provide an explanation or type a command.")
+		default:
+			userExplanation = input
 		}
+	}
 
-		if userExplanation == "" {
-			fmt.Println("Please provide an explanation or type a command.")
-			// Recursively call to get actual explanation
-			return cmd.RunE(cmd, args)
+	// Now we have the initial explanation, start the reflection loop
+	for round := 1; ; round++ {
+		// Generate reflection challenges based on current explanation
+		fmt.Println("\n🔍 Generating reflection challenges...")
+		challenges, err := study.GenerateReflectionChallenges(userExplanation, n.Content, round)
+		if err != nil {
+			return fmt.Errorf("failed to generate reflection challenges: %w", err)
 		}
 
-		// Now we have the initial explanation, start the reflection loop
-		for {
-			// Generate reflection challenges based on current explanation
-			fmt.Println("\n🔍 Generating reflection challenges...")
-			challenges, err := study.GenerateReflectionChallenges(userExplanation, noteToReflect.Content)
+		// Display challenges
+		fmt.Println("\n" + strings.Repeat("=", 60))
+		fmt.Println("🎯 REFLECTION CHALLENGES")
+		fmt.Println(strings.Repeat("=", 60))
+
+		challengeColor := color.New(color.FgCyan)
+		fmt.Print("\n")
+		challengeColor.Println(challenges)
+
+		fmt.Println(strings.Repeat("=", 60))
+
+		// Ask if user wants to respond to challenges
+		fmt.Print("\n💭 Would you like to respond to these challenges? (y/n): ")
+		responseInput, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		responseInput = strings.TrimSpace(strings.ToLower(responseInput))
+
+		if responseInput == "y" || responseInput == "yes" {
+			fmt.Println("\n💬 Share your thoughts on these challenges:")
+			userResponse, err := readLine(reader)
 			if err != nil {
-				return fmt.Errorf("failed to generate reflection challenges: %w", err)
+				return err
 			}
+			userResponse = strings.TrimSpace(userResponse)
 
-			// Display challenges
-			fmt.Println("\n" + strings.Repeat("=", 60))
-			fmt.Println("🎯 REFLECTION CHALLENGES")
-			fmt.Println(strings.Repeat("=", 60))
-
-			challengeColor := color.New(color.FgCyan)
-			fmt.Print("\n")
-			challengeColor.Println(challenges)
+			if userResponse != "" {
+				fmt.Println("\n🤝 Your response has been noted. This deeper reflection strengthens your understanding!")
+				// Update the explanation with the user's response for the next round
+				userExplanation = userExplanation + "\n\nReflection: " + userResponse
+			}
+		}
 
-			fmt.Println(strings.Repeat("=", 60))
+		// Ask if user wants to continue with another reflection round
+		fmt.Print("\n🔄 Continue with another reflection round? (y/n): ")
+		continueInput, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		continueInput = strings.TrimSpace(strings.ToLower(continueInput))
 
-			// Ask if user wants to respond to challenges
-			fmt.Print("\n💭 Would you like to respond to these challenges? (y/n): ")
-			responseInput, _ := reader.ReadString('\n')
-			responseInput = strings.TrimSpace(strings.ToLower(responseInput))
+		if continueInput == "n" || continueInput == "no" {
+			fmt.Println(endMessage)
+			return nil
+		}
 
-			if responseInput == "y" || responseInput == "yes" {
-				fmt.Println("\n💬 Share your thoughts on these challenges:")
-				userResponse, _ := reader.ReadString('\n')
-				userResponse = strings.TrimSpace(userResponse)
+		// For subsequent rounds, ask if they want to refine their explanation or explore new aspects
+		fmt.Print("\n📝 Would you like to (1) refine your explanation or (2) explore new aspects? [1/2]: ")
+		choiceInput, err := readLine(reader)
+		if err != nil {
+			return err
+		}
+		choiceInput = strings.TrimSpace(choiceInput)
 
-				if userResponse != "" {
-					fmt.Println("\n🤝 Your response has been noted. This deeper reflection strengthens your understanding!")
-					// Update the explanation with the user's response for next round
-					userExplanation = userExplanation + "\n\nReflection: " + userResponse
-				}
+		if choiceInput == "1" {
+			fmt.Print("\n✏️ Refine your explanation based on the challenges: ")
+			refinedExplanation, err := readLine(reader)
+			if err != nil {
+				return err
 			}
+			refinedExplanation = strings.TrimSpace(refinedExplanation)
 
-			// Ask if user wants to continue with another reflection round
-			fmt.Print("\n🔄 Continue with another reflection round? (y/n): ")
-			continueInput, _ := reader.ReadString('\n')
-			continueInput = strings.TrimSpace(strings.ToLower(continueInput))
-
-			if continueInput == "n" || continueInput == "no" {
-				fmt.Println("Reflection session ended. Great work on critical thinking!")
-				break
+			if refinedExplanation != "" {
+				userExplanation = refinedExplanation
+				fmt.Println("✅ Your explanation has been updated for the next reflection round.")
+			}
+		} else if choiceInput == "2" {
+			fmt.Print("\n🔍 What aspect of the concept would you like to explore next? ")
+			newAspect, err := readLine(reader)
+			if err != nil {
+				return err
 			}
+			newAspect = strings.TrimSpace(newAspect)
 
-			// For subsequent rounds, ask if they want to refine their explanation or explore new aspects
-			fmt.Print("\n📝 Would you like to (1) refine your explanation or (2) explore new aspects? [1/2]: ")
-			choiceInput, _ := reader.ReadString('\n')
-			choiceInput = strings.TrimSpace(choiceInput)
-
-			if choiceInput == "1" {
-				fmt.Print("\n✏️ Refine your explanation based on the challenges: ")
-				refinedExplanation, _ := reader.ReadString('\n')
-				refinedExplanation = strings.TrimSpace(refinedExplanation)
-
-				if refinedExplanation != "" {
-					userExplanation = refinedExplanation
-					fmt.Println("✅ Your explanation has been updated for the next reflection round.")
-				}
-			} else if choiceInput == "2" {
-				fmt.Print("\n🔍 What aspect of the concept would you like to explore next? ")
-				newAspect, _ := reader.ReadString('\n')
-				newAspect = strings.TrimSpace(newAspect)
-
-				if newAspect != "" {
-					userExplanation = userExplanation + "\n\nNew aspect to explore: " + newAspect
-					fmt.Println("✅ New aspect added for the next reflection round.")
-				}
-			} else {
-				// Default to continuing with current explanation
-				fmt.Println("✅ Continuing with your current explanation for the next reflection round.")
+			if newAspect != "" {
+				userExplanation = userExplanation + "\n\nNew aspect to explore: " + newAspect
+				fmt.Println("✅ New aspect added for the next reflection round.")
 			}
+		} else {
+			// Default to continuing with the current explanation
+			fmt.Println("✅ Continuing with your current explanation for the next reflection round.")
 		}
-
-		return nil
-	},
+	}
 }
 
 func init() {
 	rootCmd.AddCommand(reflectCmd)
+	addIndexFlag(reflectCmd)
 }
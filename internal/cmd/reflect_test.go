@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+func TestReflectCommandsRepromptWithoutRestarting(t *testing.T) {
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/reflect.md", "# Reflect\n\n## Summary\nThink hard.\n")
+	provider := useFakeProvider(t, "What breaks first?")
+
+	// help, an empty line and note each re-prompt; then explain, skip responding and stop.
+	withStdin(t, "help\n\nnote\nmy explanation\nx\nn\n")
+	var err error
+	output := captureStdout(t, func() { err = reflectCmd.RunE(reflectCmd, []string{n.Title}) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for text, want := range map[string]int{
+		"Starting Reflection Session":           1,
+		"Explain the concept in your own words": 4,
+		"Available Commands":                    1,
+		"Please provide an explanation":         1,
+		"Full Note Content":                     1,
+		"REFLECTION CHALLENGES":                 1,
+		"Reflection session ended.":             1,
+	} {
+		if got := strings.Count(output, text); got != want {
+			t.Errorf("%q printed %d times, want %d:\n%s", text, got, want, output)
+		}
+	}
+	if provider.generates != 1 {
+		t.Errorf("made %d LLM calls, want 1", provider.generates)
+	}
+}
+
+// reflectionExplanation returns the explanation a reflection prompt challenges.
+func reflectionExplanation(t *testing.T, prompt string) string {
+	t.Helper()
+	_, rest, found := strings.Cut(prompt, "USER'S EXPLANATION: ")
+	explanation, _, found2 := strings.Cut(rest, "\n\nSOURCE MATERIAL:")
+	if !found || !found2 {
+		t.Fatalf("not a reflection prompt:\n%s", prompt)
+	}
+	return explanation
+}
+
+func TestRunReflection(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// The explanation challenged in each round.
+		want    []string
+		wantErr error
+	}{
+		{
+			name:  "quit before explaining",
+			input: "quit\n",
+		},
+		{
+			name:  "one round",
+			input: "first\nx\nn\n",
+			want:  []string{"first"},
+		},
+		{
+			name:  "a response is added to the explanation",
+			input: "first\ny\nmy response\ny\n3\nx\nno\n",
+			want:  []string{"first", "first\n\nReflection: my response"},
+		},
+		{
+			name:  "refining replaces the explanation",
+			input: "first\ny\nmy response\ny\n1\nrefined\nx\nn\n",
+			want:  []string{"first", "refined"},
+		},
+		{
+			name:  "a new aspect is added to the explanation",
+			input: "first\nx\ny\n2\nedge cases\nx\ny\n1\n\nx\nn\n",
+			want:  []string{"first", "first\n\nNew aspect to explore: edge cases", "first\n\nNew aspect to explore: edge cases"},
+		},
+		{
+			name:    "input runs out",
+			input:   "first\ny\n",
+			want:    []string{"first"},
+			wantErr: io.EOF,
+		},
+	}
+	n := &note.Note{Title: "Reflect", Content: "Think hard."}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := useFakeProvider(t, "")
+			var prompts []string
+			provider.respond = func(prompt string) string {
+				prompts = append(prompts, prompt)
+				return "What breaks first?"
+			}
+
+			var err error
+			output := captureStdout(t, func() {
+				err = runReflection(bufio.NewReader(strings.NewReader(tt.input)), n, "Leave now", "All done.")
+			})
+			if err != tt.wantErr {
+				t.Fatalf("runReflection() = %v, want %v", err, tt.wantErr)
+			}
+			if ended := strings.Contains(output, "All done."); ended != (tt.wantErr == nil) {
+				t.Errorf("end message printed = %v:\n%s", ended, output)
+			}
+			if len(prompts) != len(tt.want) {
+				t.Fatalf("%d reflection rounds, want %d", len(prompts), len(tt.want))
+			}
+			for i, prompt := range prompts {
+				if got := reflectionExplanation(t, prompt); got != tt.want[i] {
+					t.Errorf("round %d challenged %q, want %q", i+1, got, tt.want[i])
+				}
+				// Later rounds ask for new angles.
+				if later := strings.Contains(prompt, "reflection round #"); later != (i > 0) {
+					t.Errorf("round %d asks for new angles = %v", i+1, later)
+				}
+			}
+		})
+	}
+}
+
+func TestRunReflectionHelpUsesCallerText(t *testing.T) {
+	useFakeProvider(t, "What breaks first?")
+	n := &note.Note{Title: "Reflect", Content: "Think hard."}
+	output := captureStdout(t, func() {
+		if err := runReflection(bufio.NewReader(strings.NewReader("?\nexit\n")), n, "Back to the menu", "Bye."); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(output, "'quit' or 'exit' - Back to the menu") || !strings.HasSuffix(strings.TrimSpace(output), "Bye.") {
+		t.Errorf("help and end message don't use the caller's text:\n%s", output)
+	}
+}
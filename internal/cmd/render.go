@@ -4,17 +4,83 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
 	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/fatih/color"
+	"golang.org/x/term"
 )
 
+// These variables hold the global rendering flags.
+var renderWidth int
+var renderPager bool
+var renderStyle string
+var noColor bool
+
+// defaultRenderWidth is used when the terminal size can't be detected (e.g. output is piped).
+const defaultRenderWidth = 80
+
+// renderConfig collects the options callers can pass to renderMarkdown.
+type renderConfig struct {
+	wordWrap int
+	style    string
+}
+
+// renderOption customizes a single renderMarkdown call.
+type renderOption func(*renderConfig)
+
+// withWordWrap wraps rendered output at the given column. Zero keeps glamour's default.
+func withWordWrap(width int) renderOption {
+	return func(c *renderConfig) {
+		c.wordWrap = width
+	}
+}
+
+// withStyle selects a glamour style: "auto", a built-in name such as "dark",
+// "light" or "notty", or a path to a custom JSON style file.
+func withStyle(style string) renderOption {
+	return func(c *renderConfig) {
+		c.style = style
+	}
+}
+
+// styleOption maps a style name or path to the matching glamour option.
+func styleOption(style string) (glamour.TermRendererOption, error) {
+	switch {
+	case style == "" || style == styles.AutoStyle:
+		// glamour.WithAutoStyle() will automatically detect if the terminal
+		// has a light or dark background and choose colors accordingly.
+		return glamour.WithAutoStyle(), nil
+	case styles.DefaultStyles[style] != nil:
+		return glamour.WithStandardStyle(style), nil
+	}
+	if info, err := os.Stat(style); err == nil && !info.IsDir() {
+		return glamour.WithStylePath(style), nil
+	}
+	return nil, fmt.Errorf("unknown style %q: use auto, dark, light, notty, ascii, dracula, pink, tokyo-night, or a path to a JSON style file", style)
+}
+
 // renderMarkdown takes a string of markdown and returns a string
 // of beautifully rendered terminal-ready output.
-func renderMarkdown(content string) (string, error) {
-	// glamour.WithAutoStyle() will automatically detect if the terminal
-	// has a light or dark background and choose colors accordingly.
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-	)
+func renderMarkdown(content string, opts ...renderOption) (string, error) {
+	var config renderConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	style, err := styleOption(config.style)
+	if err != nil {
+		return "", err
+	}
+	glamourOpts := []glamour.TermRendererOption{style}
+	if config.wordWrap > 0 {
+		glamourOpts = append(glamourOpts, glamour.WithWordWrap(config.wordWrap))
+	}
+	renderer, err := glamour.NewTermRenderer(glamourOpts...)
 	if err != nil {
 		return "", err
 	}
@@ -27,3 +93,61 @@ func renderMarkdown(content string) (string, error) {
 
 	return out, nil
 }
+
+// displayWidth returns the --width override, or the current terminal width.
+func displayWidth() int {
+	if renderWidth > 0 {
+		return renderWidth
+	}
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	return defaultRenderWidth
+}
+
+// markdownStyle returns the --style theme. When color is off (--no-color, $NO_COLOR,
+// or output that isn't a terminal) the auto style falls back to plain notty output.
+func markdownStyle() string {
+	if color.NoColor && (renderStyle == "" || renderStyle == styles.AutoStyle) {
+		return styles.NoTTYStyle
+	}
+	return renderStyle
+}
+
+// printMarkdown renders note content at the display width and in the --style theme,
+// then prints it, paging when --pager is set. If rendering fails the raw content is shown.
+func printMarkdown(content string) {
+	rendered, err := renderMarkdown(content, withWordWrap(displayWidth()), withStyle(markdownStyle()))
+	if err != nil {
+		fmt.Println("Error rendering markdown, showing raw content:")
+		rendered = content
+	}
+	if renderPager {
+		if err := pageOutput(rendered); err == nil {
+			return
+		}
+	}
+	fmt.Println(rendered)
+}
+
+// pageOutput pipes text through $PAGER (or "less -R"). Text that fits on the
+// screen, or output that isn't a terminal, is left for the caller to print.
+func pageOutput(text string) error {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("stdout is not a terminal")
+	}
+	if _, height, err := term.GetSize(fd); err == nil && strings.Count(text, "\n") < height {
+		return fmt.Errorf("output fits on screen")
+	}
+
+	pagerCmd := strings.Fields(os.Getenv("PAGER"))
+	if len(pagerCmd) == 0 {
+		pagerCmd = []string{"less", "-R"}
+	}
+	pager := exec.Command(pagerCmd[0], pagerCmd[1:]...)
+	pager.Stdin = strings.NewReader(text)
+	pager.Stdout = os.Stdout
+	pager.Stderr = os.Stderr
+	return pager.Run()
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStyleOption(t *testing.T) {
+	dir := t.TempDir()
+	stylePath := filepath.Join(dir, "style.json")
+	if err := os.WriteFile(stylePath, []byte(`{"document":{}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		style   string
+		wantErr bool
+	}{
+		{"", false},
+		{"auto", false},
+		{"dark", false},
+		{"light", false},
+		{"notty", false},
+		{"dracula", false},
+		{stylePath, false},
+		{"neon", true},
+		{"Dark", true},
+		{dir, true},
+		{filepath.Join(dir, "missing.json"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			opt, err := styleOption(tt.style)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "unknown style") {
+					t.Errorf("styleOption(%q) error = %v, want an unknown style error", tt.style, err)
+				}
+				return
+			}
+			if err != nil || opt == nil {
+				t.Errorf("styleOption(%q) = %v, %v; want an option", tt.style, opt, err)
+			}
+		})
+	}
+}
+
+func TestInvalidStyleFlag(t *testing.T) {
+	err := executeRoot(t, "tune", "--style", "neon")
+	if err == nil || !strings.Contains(err.Error(), `unknown style "neon"`) {
+		t.Errorf("--style neon returned %v, want an unknown style error", err)
+	}
+	if _, err := renderMarkdown("# Title", withStyle("neon")); err == nil {
+		t.Error("renderMarkdown with an unknown style succeeded")
+	}
+}
@@ -0,0 +1,93 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var resetAll bool
+var resetTag string
+var resetYes bool
+
+var resetScheduleCmd = &cobra.Command{
+	Use:   "reset-schedule [topic]",
+	Short: "Restart the spaced repetition schedule of a note",
+	Long: `Resets a note's interval to 1 day and its ease factor to 2.5, and makes it
+due now, as if it had just been imported. Useful after rewriting a note.
+
+Use --tag to reset every note with a tag, or --all to reset the whole deck.
+Both ask for confirmation unless --yes is given.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		selectors := 0
+		if len(args) == 1 {
+			selectors++
+		}
+		if resetAll {
+			selectors++
+		}
+		if resetTag != "" {
+			selectors++
+		}
+		if selectors != 1 {
+			return fmt.Errorf("specify exactly one of a topic, --tag, or --all")
+		}
+
+		if len(args) == 1 {
+			database, n, err := findTopicNote(args[0])
+			if err != nil || n == nil {
+				return err
+			}
+			if err := db.ResetSRS(database, n.ID); err != nil {
+				return fmt.Errorf("failed to reset schedule: %w", err)
+			}
+			fmt.Printf("✓ Reset the schedule of '%s'. It is due now.\n", n.Title)
+			return nil
+		}
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		scope := "every note"
+		if resetTag != "" {
+			scope = fmt.Sprintf("every note tagged '%s'", resetTag)
+		}
+		if !resetYes {
+			fmt.Printf("⚠️  This resets the review schedule of %s. Progress can't be recovered.\n", scope)
+			fmt.Print("Continue? (y/n): ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			answer = strings.TrimSpace(strings.ToLower(answer))
+			if answer != "y" && answer != "yes" {
+				fmt.Println("Reset cancelled.")
+				return nil
+			}
+		}
+
+		var count int64
+		if resetTag != "" {
+			count, err = db.ResetSRSByTag(database, resetTag)
+		} else {
+			count, err = db.ResetAllSRS(database)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to reset schedules: %w", err)
+		}
+		fmt.Printf("✓ Reset the schedule of %d note(s).\n", count)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resetScheduleCmd)
+	resetScheduleCmd.Flags().BoolVar(&resetAll, "all", false, "Reset every note in the database")
+	resetScheduleCmd.Flags().StringVar(&resetTag, "tag", "", "Reset every note with this tag")
+	resetScheduleCmd.Flags().BoolVarP(&resetYes, "yes", "y", false, "Don't ask for confirmation")
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// scheduledNote adds a note that has been reviewed for a while.
+func scheduledNote(t *testing.T, database *sql.DB, name string, tags ...string) {
+	t.Helper()
+	n := addTestNote(t, database, "/notes/"+name+".md", "# "+name+"\n", tags...)
+	n.Interval, n.EaseFactor, n.DueDate, n.State = 30, 2.9, time.Now().AddDate(0, 0, 30), note.StateReview
+	if err := db.UpdateNoteSRS(database, n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// isReset reports whether a note is back at a freshly imported schedule.
+func isReset(t *testing.T, database *sql.DB, name string) bool {
+	t.Helper()
+	n, err := db.GetNoteByTitleOrFilename(database, name+".md")
+	if err != nil || n == nil {
+		t.Fatalf("looking up %s: %v", name, err)
+	}
+	return n.Interval == note.DefaultInterval && n.EaseFactor == note.DefaultEaseFactor &&
+		n.State == note.StateNew && !n.DueDate.After(time.Now())
+}
+
+func TestResetScheduleAll(t *testing.T) {
+	database := testDB(t)
+	names := []string{"alpha", "beta", "gamma"}
+	for _, name := range names {
+		scheduledNote(t, database, name)
+	}
+
+	// Declining the confirmation leaves every schedule alone.
+	withStdin(t, "n\n")
+	output := captureStdout(t, func() {
+		if err := executeRoot(t, "reset-schedule", "--all"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(output, "Reset cancelled.") {
+		t.Errorf("declined reset didn't report the cancel:\n%s", output)
+	}
+	for _, name := range names {
+		if isReset(t, database, name) {
+			t.Errorf("%s was reset after the confirmation was declined", name)
+		}
+	}
+	resetFlags(rootCmd)
+
+	output = captureStdout(t, func() {
+		if err := executeRoot(t, "reset-schedule", "--all", "--yes"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(output, "Reset the schedule of 3 note(s).") {
+		t.Errorf("output doesn't report the count:\n%s", output)
+	}
+	for _, name := range names {
+		if !isReset(t, database, name) {
+			t.Errorf("%s wasn't reset by --all", name)
+		}
+	}
+}
+
+func TestResetScheduleTag(t *testing.T) {
+	database := testDB(t)
+	scheduledNote(t, database, "tagged", "go")
+	scheduledNote(t, database, "untagged")
+
+	if err := executeRoot(t, "reset-schedule", "--tag", "go", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if !isReset(t, database, "tagged") {
+		t.Error("the tagged note wasn't reset")
+	}
+	if isReset(t, database, "untagged") {
+		t.Error("the untagged note was reset by --tag go")
+	}
+}
+
+func TestResetScheduleNeedsOneSelector(t *testing.T) {
+	testDB(t)
+	for _, args := range [][]string{
+		{"reset-schedule"},
+		{"reset-schedule", "--all", "--tag", "go"},
+		{"reset-schedule", "alpha", "--all"},
+	} {
+		err := executeRoot(t, args...)
+		if err == nil || !strings.Contains(err.Error(), "exactly one") {
+			t.Errorf("%v returned %v, want an 'exactly one' error", args, err)
+		}
+		resetFlags(rootCmd)
+	}
+}
@@ -0,0 +1,57 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var retireCmd = &cobra.Command{
+	Use:   "retire [topic]",
+	Short: "Stop reviewing a note you have mastered",
+	Long: `Retires a note you know thoroughly, so it never comes up in review, mix, or
+any other due-note query again. Unlike 'neuron suspend', which is for setting a
+note aside for a while, retiring is meant to be permanent; 'neuron unretire'
+undoes it. You can also retire the note on screen during review by answering
+'r' at the rating prompt.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setNoteRetired(args[0], true)
+	},
+}
+
+var unretireCmd = &cobra.Command{
+	Use:   "unretire [topic]",
+	Short: "Return a retired note to reviews",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setNoteRetired(args[0], false)
+	},
+}
+
+// setNoteRetired retires or unretires the note matching topic.
+func setNoteRetired(topic string, retired bool) error {
+	database, n, err := findTopicNote(topic)
+	if err != nil || n == nil {
+		return err
+	}
+	if err := db.SetRetired(database, n.ID, retired); err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	if retired {
+		fmt.Printf("✓ Retired '%s'. It won't come up for review again unless you unretire it.\n", n.Title)
+	} else {
+		fmt.Printf("✓ '%s' is back in your reviews.\n", n.Title)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(retireCmd)
+	rootCmd.AddCommand(unretireCmd)
+	for _, c := range []*cobra.Command{retireCmd, unretireCmd} {
+		addIndexFlag(c)
+	}
+}
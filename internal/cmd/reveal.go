@@ -0,0 +1,116 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// stdinFeed reads its source on a goroutine, so a prompt can stop waiting for
+// Enter when a timer fires or Ctrl-C is pressed, without losing what is typed
+// later. Read it through a bufio.Reader like os.Stdin.
+type stdinFeed struct {
+	done    <-chan struct{} // closed on Ctrl-C; reads then fail with errInterrupted
+	chunks  chan []byte
+	err     error // the source's final error, set before chunks is closed
+	pending []byte
+}
+
+// newStdinFeed starts reading r in the background until ctx is cancelled.
+func newStdinFeed(ctx context.Context, r io.Reader) *stdinFeed {
+	f := &stdinFeed{done: ctx.Done(), chunks: make(chan []byte)}
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				f.chunks <- append([]byte(nil), buf[:n]...)
+			}
+			if err != nil {
+				f.err = err
+				close(f.chunks)
+				return
+			}
+		}
+	}()
+	return f
+}
+
+func (f *stdinFeed) Read(p []byte) (int, error) {
+	if len(f.pending) == 0 {
+		select {
+		case <-f.done:
+			return 0, errInterrupted
+		case chunk, ok := <-f.chunks:
+			if !ok {
+				return 0, f.err
+			}
+			f.pending = chunk
+		}
+	}
+	n := copy(p, f.pending)
+	f.pending = f.pending[n:]
+	return n, nil
+}
+
+// waitForLine waits up to d for a line, which it consumes. It reports whether the
+// line (or the end of input) came first; anything typed afterwards stays unread.
+func (f *stdinFeed) waitForLine(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		if i := bytes.IndexByte(f.pending, '\n'); i >= 0 {
+			f.pending = f.pending[i+1:]
+			return true
+		}
+		select {
+		case <-f.done:
+			return true
+		case chunk, ok := <-f.chunks:
+			if !ok {
+				return true
+			}
+			f.pending = append(f.pending, chunk...)
+		case <-timer.C:
+			return false
+		}
+	}
+}
+
+// revealPrompter waits before an answer is revealed: for Enter, or with a delay,
+// for Enter or the delay, whichever comes first.
+type revealPrompter struct {
+	reader *bufio.Reader
+	feed   *stdinFeed
+	delay  time.Duration // zero waits for Enter alone
+}
+
+// newRevealPrompter returns the prompter and the reader the rest of the session
+// must use, since stdin is read through a stdinFeed that stops on Ctrl-C.
+func newRevealPrompter(ctx context.Context, stdin io.Reader, delay time.Duration) (*revealPrompter, *bufio.Reader) {
+	feed := newStdinFeed(ctx, stdin)
+	reader := bufio.NewReader(feed)
+	return &revealPrompter{reader: reader, feed: feed, delay: delay}, reader
+}
+
+// wait prints the reveal prompt and blocks until the answer should be shown.
+func (p *revealPrompter) wait() {
+	if p.delay <= 0 {
+		fmt.Print("   (Press Enter to reveal concise answer)")
+		_, _ = p.reader.ReadString('\n')
+		return
+	}
+	fmt.Printf("   (Press Enter to reveal concise answer, or wait %s)", p.delay)
+	// Input the bufio.Reader already holds never reaches the feed, so read it directly.
+	if p.reader.Buffered() > 0 {
+		_, _ = p.reader.ReadString('\n')
+		return
+	}
+	if !p.feed.waitForLine(p.delay) {
+		fmt.Println()
+	}
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// waitReveal runs p.wait and returns how long it blocked, failing if it takes
+// longer than limit.
+func waitReveal(t *testing.T, p *revealPrompter, limit time.Duration) time.Duration {
+	t.Helper()
+	var took time.Duration
+	captureStdout(t, func() {
+		start := time.Now()
+		done := make(chan struct{})
+		go func() {
+			p.wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			took = time.Since(start)
+		case <-time.After(limit):
+			t.Fatalf("wait() still blocked after %s", limit)
+		}
+	})
+	return took
+}
+
+func TestRevealPrompterAutoReveals(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	prompter, reader := newRevealPrompter(context.Background(), r, 50*time.Millisecond)
+
+	if took := waitReveal(t, prompter, 2*time.Second); took < 50*time.Millisecond {
+		t.Errorf("wait() revealed after %s, before the 50ms delay", took)
+	}
+
+	// A rating typed after the answer was revealed is still read by the session.
+	go w.Write([]byte("3\n"))
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "3\n" {
+		t.Errorf("ReadString() after auto-reveal = %q, %v; want \"3\\n\"", line, err)
+	}
+}
+
+func TestRevealPrompterEnterRevealsEarly(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	prompter, reader := newRevealPrompter(context.Background(), r, time.Minute)
+
+	go w.Write([]byte("\n2\n"))
+	waitReveal(t, prompter, 2*time.Second)
+
+	// Only the Enter that revealed the answer is consumed.
+	line, err := reader.ReadString('\n')
+	if err != nil || line != "2\n" {
+		t.Errorf("ReadString() after Enter = %q, %v; want \"2\\n\"", line, err)
+	}
+}
+
+func TestRevealPrompterWithoutDelayWaitsForEnter(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	prompter, _ := newRevealPrompter(context.Background(), r, 0)
+
+	// Enter arrives well after a delay would have revealed the answer.
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("\n"))
+	}()
+	if took := waitReveal(t, prompter, 2*time.Second); took < 100*time.Millisecond {
+		t.Errorf("wait() returned after %s, before Enter was pressed", took)
+	}
+}
+
+func TestRevealPrompterEndOfInput(t *testing.T) {
+	r, w := io.Pipe()
+	prompter, reader := newRevealPrompter(context.Background(), r, time.Minute)
+	w.Close()
+
+	waitReveal(t, prompter, 2*time.Second)
+	if _, err := reader.ReadString('\n'); err != io.EOF {
+		t.Errorf("ReadString() after the input closed = %v, want io.EOF", err)
+	}
+}
+
+func TestRevealPrompterInterrupted(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	prompter, reader := newRevealPrompter(ctx, r, time.Minute)
+
+	cancel()
+	waitReveal(t, prompter, 2*time.Second)
+	if _, err := reader.ReadString('\n'); !errors.Is(err, errInterrupted) {
+		t.Errorf("ReadString() after Ctrl-C = %v, want errInterrupted", err)
+	}
+}
@@ -3,14 +3,18 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"math"
+	"log"
+	"math/rand"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/soyomarvaldezg/neuron-cli/internal/db"
 	"github.com/soyomarvaldezg/neuron-cli/internal/note"
 	"github.com/soyomarvaldezg/neuron-cli/internal/study"
@@ -21,6 +25,21 @@ import (
 var reviewAny bool
 var reviewBrief bool
 var questionType string
+var reviewCache bool
+var reviewNoCache bool
+var reviewHint bool
+var reviewMaxReviews int
+var reviewMaxNew int
+var reviewBatch bool
+var reviewBatchLimit int
+var reviewOrder string
+var reviewRevealAfter int
+var reviewTUI bool
+var reviewCount int
+var reviewExplainFirst bool
+var reviewTag string
+var reviewAnswerLength string
+var reviewPrefetchAnswer bool
 
 var reviewCmd = &cobra.Command{
 	Use:   "review",
@@ -30,106 +49,446 @@ Use --question-type to specify the type of questions generated:
 - factual: Questions about definitions, facts, and specific details
 - conceptual: Questions about relationships, principles, and "why" things work
 - application: Questions about applying concepts to real scenarios
-- mixed: A mix of all question types (default)`,
+- mixed: A mix of all question types (default)
+
+Use --batch to print questions and answers for due notes without prompting
+(plain text, or JSON with --json). Batch mode never changes the schedule.
+
+Use --count to review several notes in a row; the next due note is fetched after
+each rating.
+
+Use --tag to review only notes with that tag. Due notes come first; if fewer
+than --count are due, notes with the tag that are due soonest fill the rest.
+
+Use --answer-length short|medium|long to get a one-sentence answer, the default
+3-5 sentences, or a thorough explanation. Cached answers are reused as they are.
+
+Use --prefetch-answer to generate the question and its answer in a single
+request, so the answer shows up the moment you press Enter.
+
+Use --explain-first to explain the answer in your own words before it is
+revealed; the model gives feedback on your explanation, then you rate yourself.
+
+Use --tui for a full-screen session that keeps going through due notes: space
+reveals the answer and 1/2/3 rate it as Again/Good/Easy.
+
+At the rating prompt, r retires a note you have mastered: it won't come up
+again until 'neuron unretire'.`,
+	Annotations: map[string]string{jsonAnnotation: "true", llmAnnotation: "true"},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		database, err := db.GetDB()
 		if err != nil {
 			return fmt.Errorf("failed to connect to database: %w", err)
 		}
 
-		var dueNote *note.Note
+		// Convert string to QuestionType
+		qType := study.QuestionType(questionType)
+		if qType == "" {
+			qType = study.QuestionTypeMixed // Default to mixed
+		}
 
-		if reviewAny {
-			fmt.Println("Fetching a random note to review...")
-			dueNote, err = db.GetAnyNote(database)
-		} else {
-			dueNote, err = db.GetDueNote(database)
+		length, err := study.ParseAnswerLength(reviewAnswerLength)
+		if err != nil {
+			return err
+		}
+		if reviewBatch {
+			return runBatchReview(database, qType, length, reviewCache && !reviewNoCache)
+		}
+		if jsonOutput {
+			return fmt.Errorf("--json requires --batch; interactive reviews have no JSON output")
 		}
 
+		limits, err := loadDailyLimits(database, reviewMaxReviews, reviewMaxNew)
 		if err != nil {
-			if err == sql.ErrNoRows {
-				if reviewAny {
-					fmt.Println("You have no notes in your database to review!")
-				} else {
-					fmt.Println("🎉 No notes are due for review. Great job!")
-				}
-				return nil
+			return err
+		}
+		order, err := db.ParseReviewOrder(reviewOrder)
+		if err != nil {
+			return err
+		}
+		if reviewExplainFirst && offline {
+			return fmt.Errorf("--explain-first needs the model to give feedback, so it can't be combined with --offline")
+		}
+		if reviewTUI {
+			return runTUIReview(database, qType, length, order, limits, reviewCache && !reviewNoCache)
+		}
+
+		useCache := reviewCache && !reviewNoCache
+		ctx := handleInterrupts(cmd)
+		// One prompter for the whole run: it owns the buffered stdin reader.
+		reveal, reader := newRevealPrompter(ctx, os.Stdin, time.Duration(reviewRevealAfter)*time.Second)
+		session := &reviewSession{ctx: ctx, database: database, qType: qType, length: length, order: order, limits: limits, useCache: useCache, reveal: reveal, reader: reader}
+		count := max(reviewCount, 1)
+		if reviewTag != "" {
+			return session.finish(session.reviewTagged(reviewTag, count))
+		}
+		if count > 1 && !reviewAny {
+			if dueNow, err := db.CountDueNotes(database); err == nil {
+				count = min(count, max(dueNow, 1))
+			}
+			if left := limits.reviewsLeft(); left > 0 {
+				count = min(count, left)
+			}
+		}
+		for i := 0; i < count && ctx.Err() == nil; i++ {
+			if count > 1 {
+				fmt.Printf("\n--- Card %d of %d ---\n", i+1, count)
+			}
+			// The next note is fetched after each rating, so the one just
+			// rescheduled doesn't come straight back.
+			more, err := session.next()
+			if err != nil || !more {
+				return session.finish(err)
 			}
-			return fmt.Errorf("failed to fetch note: %w", err)
 		}
+		return session.finish(nil)
+	},
+}
 
-		// Convert string to QuestionType
-		qType := study.QuestionType(questionType)
-		if qType == "" {
-			qType = study.QuestionTypeMixed // Default to mixed
+// reviewSession holds what every card of a review run shares.
+type reviewSession struct {
+	ctx      context.Context // cancelled by Ctrl-C
+	database *sql.DB
+	qType    study.QuestionType
+	length   study.AnswerLength
+	order    db.ReviewOrder
+	limits   *dailyLimits
+	useCache bool
+	reveal   *revealPrompter
+	reader   *bufio.Reader // owned by reveal; every prompt reads through it
+	reviewed int           // cards rated so far
+}
+
+// finish ends the run, noting when Ctrl-C cut it short.
+func (s *reviewSession) finish(err error) error {
+	if err == nil && s.ctx.Err() != nil {
+		printInterrupted(s.reviewed)
+	}
+	return err
+}
+
+// reviewTagged reviews up to count notes tagged tag: the due ones first, then,
+// with a warning, the ones that are due soonest.
+func (s *reviewSession) reviewTagged(tag string, count int) error {
+	notes, err := db.GetDueNotesByTag(s.database, tag, count)
+	if err != nil {
+		return fmt.Errorf("failed to fetch notes tagged '%s': %w", tag, err)
+	}
+	if len(notes) < count {
+		upcoming, err := db.GetUpcomingNotesByTag(s.database, tag, count-len(notes))
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes tagged '%s': %w", tag, err)
+		}
+		if len(notes) == 0 && len(upcoming) == 0 {
+			fmt.Printf("No notes are tagged '%s'.\n", tag)
+			return nil
+		}
+		if len(upcoming) > 0 {
+			color.Yellow("⚠️  Only %d note(s) tagged '%s' are due; adding %d that aren't due yet.", len(notes), tag, len(upcoming))
 		}
+		notes = append(notes, upcoming...)
+	}
+	for i, n := range notes {
+		if s.ctx.Err() != nil {
+			return nil
+		}
+		if s.limits.reviewsLeft() == 0 {
+			printLimitReached(s.database, s.limits)
+			return nil
+		}
+		if len(notes) > 1 {
+			fmt.Printf("\n--- Card %d of %d ---\n", i+1, len(notes))
+		}
+		more, err := s.review(n)
+		if err != nil || !more {
+			return err
+		}
+	}
+	return nil
+}
 
-		fmt.Printf("🧠 Generating %s question...\n", qType)
-		question, err := study.GenerateQuestion(dueNote, qType)
+// next fetches the next due note (any note with --any) and reviews it. It reports
+// false when the session should stop: nothing is due, a daily limit is hit, or
+// input ran out.
+func (s *reviewSession) next() (bool, error) {
+	var dueNote *note.Note
+	var err error
+	if reviewAny {
+		fmt.Println("Fetching a random note to review...")
+		dueNote, err = db.GetAnyNote(s.database)
+	} else {
+		if s.limits.reviewsLeft() == 0 {
+			printLimitReached(s.database, s.limits)
+			return false, nil
+		}
+		dueNote, err = db.GetDueNoteOrdered(s.database, s.order, !s.limits.newAllowed())
+	}
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if reviewAny {
+				fmt.Println("You have no notes in your database to review!")
+			} else if !s.limits.newAllowed() {
+				fmt.Printf("🛑 Daily new-card limit reached (%d/%d). No previously reviewed notes are due.\n", s.limits.newDone, s.limits.maxNew)
+			} else {
+				fmt.Println("🎉 No notes are due for review. Great job!")
+			}
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to fetch note: %w", err)
+	}
+	return s.review(dueNote)
+}
+
+// review quizzes the user on dueNote and saves the rating. It reports false when
+// input ran out before a rating was given.
+func (s *reviewSession) review(dueNote *note.Note) (bool, error) {
+	var err error
+	question, conciseAnswer, cacheHit := "", "", false
+	card, hasCard := pickCard(dueNote)
+	if hasCard {
+		// Cards written in the note are reviewed as-is, without the model.
+		question, conciseAnswer, cacheHit = card.Front, card.Back, true
+	} else if s.useCache {
+		question, conciseAnswer, cacheHit = cachedQuestionAnswer(s.database, dueNote, s.qType)
+	}
+
+	fullNote := false
+	switch {
+	case hasCard:
+		fmt.Printf("🃏 Using a card from the note (%d in total)...\n", len(dueNote.Cards))
+	case cacheHit:
+		fmt.Printf("⚡ Using cached %s question...\n", s.qType)
+	case offline:
+		fmt.Println("📴 Offline: recall what you can, then check it against the note.")
+		question, conciseAnswer, fullNote = offlineQuestionAnswer(dueNote)
+		cacheHit = true
+	case reviewPrefetchAnswer:
+		fmt.Printf("🧠 Generating %s question and answer...\n", s.qType)
+		question, conciseAnswer, err = study.GenerateQnA(dueNote, s.qType, s.length)
+		if errors.Is(err, study.ErrNoQnA) {
+			// The model didn't follow the format; ask for the question on its own.
+			question, err = study.GenerateQuestion(dueNote, s.qType)
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to generate question: %w", err)
+		}
+	default:
+		fmt.Printf("🧠 Generating %s question...\n", s.qType)
+		question, err = study.GenerateQuestion(dueNote, s.qType)
 		if err != nil {
-			return fmt.Errorf("failed to generate question: %w", err)
+			return false, fmt.Errorf("failed to generate question: %w", err)
 		}
+	}
 
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Printf("\n🤔 Question: %s\n", question)
-		fmt.Print("   (Press Enter to reveal concise answer)")
-		_, _ = reader.ReadString('\n')
+	started := time.Now()
+	fmt.Printf("\n🤔 Question: %s\n", question)
+	explanation := ""
+	if reviewExplainFirst {
+		fmt.Print("\n✍️  Explain it in your own words before seeing the answer (Enter to skip): ")
+		explanation, _ = readLine(s.reader)
+		explanation = strings.TrimSpace(explanation)
+	} else if reviewHint && !hasCard && !offline {
+		fmt.Print("   (Type 'h' for a hint, or press Enter to reveal concise answer)")
+		input, _ := s.reader.ReadString('\n')
+		if strings.TrimSpace(strings.ToLower(input)) == "h" {
+			fmt.Println("\n🔎 Generating hint...")
+			hint, err := study.GenerateHint(question, dueNote)
+			if err != nil {
+				fmt.Printf("Could not generate a hint: %v\n", err)
+			} else {
+				fmt.Printf("\n💭 Hint: %s\n", hint)
+			}
+			s.reveal.wait()
+		}
+	} else {
+		s.reveal.wait()
+	}
+	if s.ctx.Err() != nil {
+		return false, nil
+	}
+	// Think time runs until the answer is revealed, not until the card is rated.
+	think := time.Since(started)
 
-		fmt.Println("\n🤖 Generating concise answer...")
-		conciseAnswer, err := study.GenerateAnswer(question, dueNote)
+	if !cacheHit {
+		if conciseAnswer == "" {
+			fmt.Println("\n🤖 Generating concise answer...")
+			conciseAnswer, err = study.GenerateAnswer(question, dueNote, s.length)
+			if err != nil {
+				return false, fmt.Errorf("failed to generate answer: %w", err)
+			}
+		}
+		if s.useCache {
+			storeQuestionAnswer(s.database, dueNote, s.qType, question, conciseAnswer)
+		}
+	}
+
+	if explanation != "" {
+		fmt.Println("\n🔍 Comparing your explanation with the answer...")
+		feedback, err := study.CompareAnswers(explanation, conciseAnswer, question)
 		if err != nil {
-			return fmt.Errorf("failed to generate answer: %w", err)
+			fmt.Printf("Could not compare your explanation: %v\n", err)
+		} else {
+			fmt.Print("\n📝 Feedback: ")
+			color.New(color.FgGreen).Println(feedback)
 		}
+	}
 
+	if fullNote {
+		fmt.Println("\n📖 Full Note:")
+		fmt.Println("-----------------------------------------------------------")
+		printMarkdown(dueNote.Content)
+		fmt.Println("-----------------------------------------------------------")
+	} else {
 		fmt.Println("\n💡 Concise Answer:")
 		fmt.Println("-----------------------------------------------------------")
 		fmt.Println(conciseAnswer)
 		fmt.Println("-----------------------------------------------------------")
+	}
 
-		// Only ask about showing the full note if not in brief mode
-		if !reviewBrief {
-			fmt.Print("\n📖 Would you like to see the full note for additional context? (y/n): ")
-			showNote, _ := reader.ReadString('\n')
-			showNote = strings.TrimSpace(strings.ToLower(showNote))
-
-			if showNote == "y" || showNote == "yes" {
-				fmt.Println("\n📖 Full Note Context:")
-				fmt.Println("-----------------------------------------------------------")
-
-				renderedContent, err := renderMarkdown(dueNote.Content)
-				if err != nil {
-					fmt.Println("Error rendering markdown, showing raw content:")
-					fmt.Println(dueNote.Content)
-				} else {
-					fmt.Println(renderedContent)
-				}
-
-				fmt.Println("-----------------------------------------------------------")
-			}
+	// Only ask about showing the full note if not in brief mode
+	if !reviewBrief && !fullNote {
+		fmt.Print("\n📖 Would you like to see the full note for additional context? (y/n): ")
+		showNote, _ := s.reader.ReadString('\n')
+		showNote = strings.TrimSpace(strings.ToLower(showNote))
+
+		if showNote == "y" || showNote == "yes" {
+			fmt.Println("\n📖 Full Note Context:")
+			fmt.Println("-----------------------------------------------------------")
+
+			printMarkdown(dueNote.Content)
+
+			fmt.Println("-----------------------------------------------------------")
 		}
+	}
 
-		var rating int
-		for {
-			fmt.Print("\nHow well did you recall this? (1=Again, 2=Good, 3=Easy): ")
-			input, _ := reader.ReadString('\n')
-			input = strings.TrimSpace(input)
-			rating, err = strconv.Atoi(input)
-			if err == nil && (rating >= 1 && rating <= 3) {
-				break
-			}
-			fmt.Println("Invalid input. Please enter 1, 2, or 3.")
+	showRelatedNotes(s.database, dueNote)
+
+	rating, err := readRating(s.reader, true)
+	if err != nil {
+		if s.ctx.Err() == nil {
+			fmt.Println("Input closed; this card was not rated.")
+		}
+		return false, nil
+	}
+	if rating == ratingRetire {
+		if err := db.SetRetired(s.database, dueNote.ID, true); err != nil {
+			return false, fmt.Errorf("failed to retire note: %w", err)
 		}
+		fmt.Printf("🎓 Retired '%s'. It won't come up again; 'neuron unretire' brings it back.\n", dueNote.Title)
+		return true, nil
+	}
+	wasNew := study.IsNew(dueNote)
+	if err := saveRating(s.database, dueNote, rating, think); err != nil {
+		return false, err
+	}
+	s.limits.record(s.database, wasNew)
+	s.reviewed++
+	fmt.Printf("✓ Good work! This note is scheduled for review in about %s. Time: %s\n", untilDue(dueNote.DueDate), think.Round(time.Second))
+
+	return true, nil
+}
+
+// pickCard returns one of the note's explicit cards at random, if it has any.
+func pickCard(n *note.Note) (note.Card, bool) {
+	if len(n.Cards) == 0 {
+		return note.Card{}, false
+	}
+	return n.Cards[rand.Intn(len(n.Cards))], true
+}
+
+// relatedNotesShown is how many related notes are suggested after an answer.
+const relatedNotesShown = 3
+
+// showRelatedNotes lists the notes that share the most tags or links with n,
+// so the answer can be connected to what else you know.
+func showRelatedNotes(database *sql.DB, n *note.Note) {
+	related, err := db.FindRelatedNotes(database, n, relatedNotesShown)
+	if err != nil {
+		log.Printf("Error finding notes related to %s: %v", n.Title, err)
+		return
+	}
+	if len(related) == 0 {
+		return
+	}
+	fmt.Println("\n🔗 Related notes:")
+	for _, r := range related {
+		fmt.Printf("  • %s\n", r.Title)
+	}
+}
+
+// batchCard is one question/answer pair printed by review --batch.
+type batchCard struct {
+	Title    string    `json:"title"`
+	Filename string    `json:"filename"`
+	DueDate  time.Time `json:"due_date"`
+	Question string    `json:"question"`
+	Answer   string    `json:"answer"`
+}
+
+// runBatchReview generates a question and answer for each due note and prints them
+// without prompting. Progress goes to stderr so stdout can be piped or mailed as-is.
+func runBatchReview(database *sql.DB, qType study.QuestionType, length study.AnswerLength, useCache bool) error {
+	notes, err := db.GetDueNotes(database, reviewBatchLimit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch due notes: %w", err)
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].DueDate.Before(notes[j].DueDate)
+	})
 
-		study.UpdateSRSData(dueNote, rating)
-		if err := db.UpdateNoteSRS(database, dueNote); err != nil {
-			return fmt.Errorf("failed to update note schedule: %w", err)
+	cards := []batchCard{}
+	for i, dueNote := range notes {
+		fmt.Fprintf(os.Stderr, "🧠 Generating %s question %d of %d: %s\n", qType, i+1, len(notes), dueNote.Title)
+		question, answer, cacheHit := "", "", false
+		if card, ok := pickCard(dueNote); ok {
+			question, answer, cacheHit = card.Front, card.Back, true
+		} else if useCache {
+			question, answer, cacheHit = cachedQuestionAnswer(database, dueNote, qType)
+		}
+		if !cacheHit && offline {
+			var fullNote bool
+			question, answer, fullNote = offlineQuestionAnswer(dueNote)
+			if fullNote {
+				answer = dueNote.Content
+			}
+			cacheHit = true
 		}
-		nextReview := time.Until(dueNote.DueDate)
-		days := int(math.Ceil(nextReview.Hours() / 24))
-		fmt.Printf("✓ Good work! This note is scheduled for review in about %d day(s).\n", days)
+		if !cacheHit {
+			question, err = study.GenerateQuestion(dueNote, qType)
+			if err != nil {
+				log.Printf("Error generating question for %s: %v. Skipping.", dueNote.Title, err)
+				continue
+			}
+			answer, err = study.GenerateAnswer(question, dueNote, length)
+			if err != nil {
+				log.Printf("Error generating answer for %s: %v. Skipping.", dueNote.Title, err)
+				continue
+			}
+			if useCache {
+				storeQuestionAnswer(database, dueNote, qType, question, answer)
+			}
+		}
+		cards = append(cards, batchCard{
+			Title:    dueNote.Title,
+			Filename: dueNote.Filename,
+			DueDate:  dueNote.DueDate,
+			Question: question,
+			Answer:   answer,
+		})
+	}
 
-		return nil
-	},
+	return newOutputter().Print(cards, func() {
+		if len(cards) == 0 {
+			fmt.Println("No notes are due for review.")
+			return
+		}
+		for i, card := range cards {
+			fmt.Printf("%d. %s\n", i+1, card.Title)
+			fmt.Printf("Q: %s\n", card.Question)
+			fmt.Printf("A: %s\n\n", card.Answer)
+		}
+	})
 }
 
 func init() {
@@ -137,4 +496,20 @@ func init() {
 	reviewCmd.Flags().BoolVar(&reviewAny, "any", false, "Review any card, even if it's not due")
 	reviewCmd.Flags().BoolVar(&reviewBrief, "brief", false, "Skip showing full note, only show Q&A")
 	reviewCmd.Flags().StringVar(&questionType, "question-type", "mixed", "Type of question to generate: factual, conceptual, application, mixed")
+	reviewCmd.Flags().BoolVar(&reviewHint, "hint", false, "Offer a short hint before revealing the answer")
+	reviewCmd.Flags().IntVar(&reviewMaxReviews, "max-reviews", 0, "Stop once this many reviews were done today (0 = unlimited)")
+	reviewCmd.Flags().IntVar(&reviewMaxNew, "max-new", 0, "Show at most this many never-reviewed notes per day (0 = unlimited)")
+	reviewCmd.Flags().BoolVar(&reviewBatch, "batch", false, "Print questions and answers for due notes without prompting or rescheduling")
+	reviewCmd.Flags().IntVar(&reviewBatchLimit, "limit", 10, "With --batch, the maximum number of due notes to include")
+	reviewCmd.Flags().BoolVar(&reviewCache, "cache", false, "Reuse a previously generated question/answer for this note when available")
+	reviewCmd.Flags().BoolVar(&reviewNoCache, "no-cache", false, "Always ask the LLM, ignoring --cache")
+	reviewCmd.Flags().BoolVar(&offline, "offline", false, offlineFlagUsage)
+	reviewCmd.Flags().IntVar(&reviewRevealAfter, "reveal-after", 0, "Reveal the answer after this many seconds if Enter wasn't pressed (0 = wait for Enter)")
+	reviewCmd.Flags().BoolVar(&reviewExplainFirst, "explain-first", false, "Type your own explanation before the answer is revealed and get feedback on it")
+	reviewCmd.Flags().IntVarP(&reviewCount, "count", "n", 1, "Review up to this many notes in a row")
+	reviewCmd.Flags().StringVar(&reviewAnswerLength, "answer-length", string(study.AnswerMedium), "How long generated answers are: short (one sentence), medium (3-5 sentences), long (a thorough explanation)")
+	reviewCmd.Flags().BoolVar(&reviewPrefetchAnswer, "prefetch-answer", false, "Generate the question and its answer in one request, so revealing the answer doesn't wait on the model")
+	reviewCmd.Flags().StringVar(&reviewTag, "tag", "", "Review only notes with this tag, topping up with not-yet-due ones if too few are due")
+	reviewCmd.Flags().BoolVar(&reviewTUI, "tui", false, "Review due notes one after another in a full-screen view, rating with single key presses")
+	reviewCmd.Flags().StringVar(&reviewOrder, "order", string(db.OrderDue), "Which due note comes first: new (newest created), old (oldest created), due (most overdue), random")
 }
@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+// addCardNote stores a note with one Q:/A: card, so reviewing it needs no model,
+// due in dueIn (negative for overdue).
+func addCardNote(t *testing.T, database *sql.DB, name string, dueIn time.Duration, tags ...string) *note.Note {
+	t.Helper()
+	n := addTestNote(t, database, "/notes/"+name+".md", "# "+name+"\n\nQ: What is "+name+"?\nA: The answer.\n", tags...)
+	n.DueDate = time.Now().Add(dueIn)
+	if err := db.UpdateNoteSRS(database, n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// runReview runs "neuron review" with args, answering its prompts from input,
+// and returns what it printed.
+func runReview(t *testing.T, input string, args ...string) (string, error) {
+	t.Helper()
+	withStdin(t, input)
+	var err error
+	output := captureStdout(t, func() {
+		err = executeRoot(t, append([]string{"review", "--no-preflight"}, args...)...)
+	})
+	return output, err
+}
+
+// rateGood is the input that reveals a card's answer and rates it Good.
+const rateGood = "\n2\n"
+
+// reviewedNotes returns the ids of the notes in the review log, in review order.
+func reviewedNotes(t *testing.T, database *sql.DB) []int {
+	t.Helper()
+	rows, err := database.Query(`SELECT note_id FROM review_log ORDER BY id;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestReviewCountReviewsDistinctNotes(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		rating string // the key each card is rated with
+	}{
+		{"rated Good", "2"},
+		// Again brings a card back after a learning step, not within the same run.
+		{"rated Again", "1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			database := testDB(t)
+			for i, name := range []string{"first", "second", "third", "fourth", "fifth"} {
+				addCardNote(t, database, name, -time.Duration(5-i)*time.Hour)
+			}
+
+			output, err := runReview(t, strings.Repeat("\n"+tt.rating+"\n", 3), "--count", "3", "--brief")
+			if err != nil {
+				t.Fatal(err)
+			}
+			reviewed := reviewedNotes(t, database)
+			if distinct := slices.Compact(slices.Sorted(slices.Values(reviewed))); len(reviewed) != 3 || len(distinct) != 3 {
+				t.Errorf("reviewed notes %v, want 3 different ones", reviewed)
+			}
+			for i := 1; i <= 3; i++ {
+				if want := fmt.Sprintf("--- Card %d of 3 ---", i); !strings.Contains(output, want) {
+					t.Errorf("output is missing %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
+
+func TestReviewCountStopsWhenNothingIsDue(t *testing.T) {
+	database := testDB(t)
+	addCardNote(t, database, "first", -2*time.Hour)
+	addCardNote(t, database, "second", -time.Hour)
+	addCardNote(t, database, "upcoming", time.Hour)
+
+	output, err := runReview(t, strings.Repeat(rateGood, 5), "--count", "5", "--brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reviewed := reviewedNotes(t, database); len(reviewed) != 2 || reviewed[0] == reviewed[1] {
+		t.Errorf("reviewed notes %v, want the 2 due ones", reviewed)
+	}
+	if !strings.Contains(output, "--- Card 2 of 2 ---") || strings.Contains(output, "Card 3") {
+		t.Errorf("the count isn't capped at the due notes:\n%s", output)
+	}
+}
+
+func TestReviewTagFallsBackToUpcomingNotes(t *testing.T) {
+	database := testDB(t)
+	due := addCardNote(t, database, "due", -time.Hour, "go")
+	soon := addCardNote(t, database, "soon", 24*time.Hour, "go")
+	later := addCardNote(t, database, "later", 5*24*time.Hour, "go")
+	addCardNote(t, database, "latest", 30*24*time.Hour, "go")
+	addCardNote(t, database, "untagged", -time.Hour)
+
+	output, err := runReview(t, strings.Repeat(rateGood, 3), "--tag", "go", "--count", "3", "--brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{due.ID, soon.ID, later.ID}; !slices.Equal(reviewedNotes(t, database), want) {
+		t.Errorf("reviewed %v, want the due note and then the two due soonest %v", reviewedNotes(t, database), want)
+	}
+	if !strings.Contains(output, "Only 1 note(s) tagged 'go' are due; adding 2") {
+		t.Errorf("no warning about the notes that aren't due:\n%s", output)
+	}
+}
+
+func TestReviewTagWithEnoughDueNotes(t *testing.T) {
+	database := testDB(t)
+	first := addCardNote(t, database, "first", -2*time.Hour, "go")
+	second := addCardNote(t, database, "second", -time.Hour, "go")
+	addCardNote(t, database, "upcoming", time.Hour, "go")
+
+	output, err := runReview(t, strings.Repeat(rateGood, 2), "--tag", "go", "--count", "2", "--brief")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{first.ID, second.ID}; !slices.Equal(reviewedNotes(t, database), want) {
+		t.Errorf("reviewed %v, want only the due notes %v", reviewedNotes(t, database), want)
+	}
+	if strings.Contains(output, "aren't due yet") {
+		t.Errorf("warned about a fallback that wasn't needed:\n%s", output)
+	}
+}
+
+func TestReviewTagWithNoNotes(t *testing.T) {
+	database := testDB(t)
+	addCardNote(t, database, "untagged", -time.Hour)
+
+	output, err := runReview(t, "", "--tag", "go", "--count", "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reviewedNotes(t, database)) != 0 || !strings.Contains(output, "No notes are tagged 'go'") {
+		t.Errorf("reviewed %v, output:\n%s", reviewedNotes(t, database), output)
+	}
+}
+
+// runInterrupted runs the command line args with cmd's context cancelled, as
+// Ctrl-C does, once rated cards have been saved; zero cancels it up front. Its
+// stdin rates every card Good until then and stays open afterwards, so only the
+// cancellation can end the session early.
+func runInterrupted(t *testing.T, database *sql.DB, cmd *cobra.Command, rated int, args ...string) (string, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = r
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd.SetContext(ctx)
+	t.Cleanup(func() {
+		cancel()
+		cmd.SetContext(context.Background())
+		os.Stdin = stdin
+		w.Close()
+		r.Close()
+	})
+
+	if rated == 0 {
+		cancel()
+	} else {
+		if _, err := w.WriteString(strings.Repeat(rateGood, rated)); err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			for ctx.Err() == nil {
+				var n int
+				if database.QueryRow(`SELECT COUNT(*) FROM review_log;`).Scan(&n) == nil && n >= rated {
+					cancel()
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+	}
+
+	var runErr error
+	output := captureStdout(t, func() { runErr = executeRoot(t, args...) })
+	return output, runErr
+}
+
+func TestReviewInterruptedBetweenCards(t *testing.T) {
+	for _, rated := range []int{0, 1} {
+		t.Run(fmt.Sprintf("after %d", rated), func(t *testing.T) {
+			database := testDB(t)
+			for _, name := range []string{"first", "second", "third"} {
+				addCardNote(t, database, name, -time.Hour)
+			}
+
+			output, err := runInterrupted(t, database, reviewCmd, rated, "review", "--no-preflight", "--count", "3", "--brief")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(reviewedNotes(t, database)); got != rated {
+				t.Errorf("%d card(s) rated, want %d", got, rated)
+			}
+			if got := strings.Count(output, "Concise Answer"); got != rated {
+				t.Errorf("%d answer(s) revealed, want %d:\n%s", got, rated, output)
+			}
+			if want := fmt.Sprintf("Session interrupted, %d card(s) reviewed", rated); !strings.Contains(output, want) {
+				t.Errorf("output is missing %q:\n%s", want, output)
+			}
+		})
+	}
+}
+
+func TestBatchReviewPairsEveryDueNote(t *testing.T) {
+	database := testDB(t)
+	for i, name := range []string{"first", "second", "third"} {
+		n := addTestNote(t, database, "/notes/"+name+".md", "# "+name+"\n\nbody")
+		n.DueDate = time.Now().Add(-time.Duration(3-i) * time.Hour)
+		if err := db.UpdateNoteSRS(database, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addCardNote(t, database, "card", -30*time.Minute)
+	addCardNote(t, database, "not due", 24*time.Hour)
+	provider := useFakeProvider(t, "Generated.")
+	jsonOutput = true
+	t.Cleanup(func() { jsonOutput = false })
+
+	var err error
+	output := captureStdout(t, func() { err = runBatchReview(database, study.QuestionTypeMixed, study.AnswerMedium, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cards []batchCard
+	if err := json.Unmarshal([]byte(output), &cards); err != nil {
+		t.Fatalf("review --batch --json printed invalid JSON: %v\n%s", err, output)
+	}
+	var titles []string
+	for _, card := range cards {
+		titles = append(titles, card.Title)
+		if card.Question == "" || card.Answer == "" {
+			t.Errorf("%s has question %q and answer %q, want both", card.Title, card.Question, card.Answer)
+		}
+	}
+	if want := []string{"first.md", "second.md", "third.md", "card.md"}; !slices.Equal(titles, want) {
+		t.Errorf("batch covered %q, want every due note, most overdue first: %q", titles, want)
+	}
+	// One question and one answer per note without a card; the card needs neither.
+	if provider.generates != 6 {
+		t.Errorf("made %d requests, want 6", provider.generates)
+	}
+	if reviews := reviewedNotes(t, database); len(reviews) != 0 {
+		t.Errorf("--batch rated notes %v; it shouldn't reschedule anything", reviews)
+	}
+}
+
+func TestBatchReviewLimit(t *testing.T) {
+	database := testDB(t)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		addCardNote(t, database, name, -time.Hour)
+	}
+	reviewBatchLimit = 2
+	t.Cleanup(func() { reviewBatchLimit = 10 })
+
+	var err error
+	output := captureStdout(t, func() { err = runBatchReview(database, study.QuestionTypeMixed, study.AnswerMedium, false) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(output, "\nQ: "); got != 2 {
+		t.Errorf("printed %d pairs with --limit 2, want 2:\n%s", got, output)
+	}
+}
+
+// ollamaPrompts points the Ollama provider at a server that answers every
+// generate request with reply and records its prompt.
+func ollamaPrompts(t *testing.T, reply string) *[]string {
+	t.Helper()
+	var mu sync.Mutex
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request study.OllamaRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		prompts = append(prompts, request.Prompt)
+		mu.Unlock()
+		json.NewEncoder(w).Encode(study.OllamaResponse{Response: reply, Done: true})
+	}))
+	t.Cleanup(server.Close)
+	useConfigFile(t, &config.Config{OllamaHost: server.URL})
+	return &prompts
+}
+
+func TestReviewExplainFirst(t *testing.T) {
+	database := testDB(t)
+	n := addCardNote(t, database, "maps", -time.Hour)
+	prompts := ollamaPrompts(t, "You got the gist.")
+
+	// Explain, skip the full note, rate Good.
+	output, err := runReview(t, "they are hash tables\nn\n2\n", "--explain-first")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Explain, then compare, then see the answer, then rate.
+	last := -1
+	for _, step := range []string{
+		"Question: What is maps?",
+		"Explain it in your own words",
+		"Comparing your explanation",
+		"Feedback: You got the gist.",
+		"Concise Answer",
+		"How well did you recall this?",
+	} {
+		at := strings.Index(output, step)
+		if at <= last {
+			t.Fatalf("%q isn't shown after the previous step:\n%s", step, output)
+		}
+		last = at
+	}
+	if len(*prompts) != 1 {
+		t.Fatalf("made %d model requests, want just the comparison", len(*prompts))
+	}
+	for _, want := range []string{"QUESTION: What is maps?", "STUDENT'S ANSWER: they are hash tables", "CORRECT ANSWER: The answer."} {
+		if !strings.Contains((*prompts)[0], want) {
+			t.Errorf("comparison prompt is missing %q:\n%s", want, (*prompts)[0])
+		}
+	}
+	if reviewed := reviewedNotes(t, database); !slices.Equal(reviewed, []int{n.ID}) {
+		t.Errorf("reviewed notes %v, want the explained note rated", reviewed)
+	}
+}
+
+func TestReviewExplainFirstSkipped(t *testing.T) {
+	database := testDB(t)
+	addCardNote(t, database, "maps", -time.Hour)
+	prompts := ollamaPrompts(t, "You got the gist.")
+
+	// Enter skips the explanation and goes straight to the answer.
+	output, err := runReview(t, "\nn\n2\n", "--explain-first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*prompts) != 0 || strings.Contains(output, "Feedback") {
+		t.Errorf("a skipped explanation was compared (%d requests):\n%s", len(*prompts), output)
+	}
+	if !strings.Contains(output, "Concise Answer") || len(reviewedNotes(t, database)) != 1 {
+		t.Errorf("the card wasn't answered and rated:\n%s", output)
+	}
+}
+
+func TestReviewExplainFirstNeedsTheModel(t *testing.T) {
+	testDB(t)
+	_, err := runReview(t, "", "--explain-first", "--offline")
+	if err == nil || !strings.Contains(err.Error(), "--offline") {
+		t.Errorf("--explain-first --offline returned %v, want an error", err)
+	}
+}
@@ -2,12 +2,38 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
 
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
 	"github.com/spf13/cobra"
 )
 
+// providerName selects the LLM backend used by every study command.
+var providerName string
+
+// modelName, temperature and seed override the LLM model and sampling options for this run.
+var (
+	modelName   string
+	temperature float64
+	seed        int
+)
+
+// promptLanguage is the --lang value: the language generated content is written in.
+var promptLanguage string
+
+// scheduleFuzz spreads out due dates of notes that would otherwise come due together.
+var scheduleFuzz bool
+
+// targetRetention tunes review intervals for one run; "config set target_retention" keeps it.
+var targetRetention float64
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "neuron",
@@ -15,22 +41,166 @@ var rootCmd = &cobra.Command{
 	Long: `A powerful, evidence-based learning tool for the command line.
 Neuron CLI helps you learn and retain knowledge from your notes
 by using spaced repetition, active recall, and AI-powered questioning.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkJSONSupport(cmd); err != nil {
+			return err
+		}
+		// fatih/color already turns itself off for $NO_COLOR and output that isn't a terminal.
+		if noColor {
+			color.NoColor = true
+		}
+		if _, err := styleOption(renderStyle); err != nil {
+			return err
+		}
+		// The config commands don't study anything, and must keep working when
+		// config.yaml holds an invalid setting so "config set" can fix it.
+		if isConfigCommand(cmd) {
+			return nil
+		}
+		// doctor reports an invalid config as a failed check instead of refusing to run.
+		if cmd == doctorCmd {
+			doctorConfigErr = loadDoctorConfig(cmd)
+			return nil
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if err := applyConfig(cmd, cfg); err != nil {
+			return err
+		}
+		return runPreflight(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		cmd.Help()
 	},
 }
 
+// applyConfig sets up the provider, database, scheduler and prompts for cmd from
+// cfg and the command line flags, which override it. It stops at the first invalid
+// setting, leaving the ones before it applied; the provider and database come
+// first, so they are set up whatever else is wrong.
+func applyConfig(cmd *cobra.Command, cfg *config.Config) error {
+	model := cfg.ModelFor(cmd.Name())
+	if cmd.Flags().Changed("model") {
+		model = modelName
+	}
+	provider, err := study.NewProvider(providerName, model)
+	if err != nil {
+		return err
+	}
+	if ollama, ok := provider.(*study.OllamaProvider); ok && cfg.OllamaHost != "" {
+		ollama.BaseURL = strings.TrimRight(cfg.OllamaHost, "/")
+	}
+	study.SetProvider(provider)
+	dbOptions := db.DefaultOptions()
+	if cfg.Database.WAL != nil {
+		dbOptions.WAL = *cfg.Database.WAL
+	}
+	if cfg.Database.ForeignKeys != nil {
+		dbOptions.ForeignKeys = *cfg.Database.ForeignKeys
+	}
+	if cfg.Database.BusyTimeoutMS > 0 {
+		dbOptions.BusyTimeout = cfg.Database.BusyTimeoutMS
+	}
+	db.SetOptions(dbOptions)
+	sampling := study.OllamaOptions{Temperature: cfg.Temperature, Seed: cfg.Seed}
+	if cmd.Flags().Changed("seed") {
+		sampling.Seed = &seed
+	}
+	study.SetDefaultOptions(sampling)
+	study.SetMaxConcurrency(cfg.MaxConcurrency)
+	if cmd.Flags().Changed("temperature") {
+		if temperature < 0 {
+			return fmt.Errorf("--temperature must not be negative, got %g", temperature)
+		}
+		study.SetTemperatureOverride(&temperature)
+	}
+	if cmd.Flags().Changed("target-retention") {
+		if err := study.ValidateTargetRetention(targetRetention); err != nil {
+			return err
+		}
+		cfg.TargetRetention = targetRetention
+	}
+
+	scheduler := study.DefaultSchedulerConfig()
+	scheduler.Fuzz = cfg.Fuzz != nil && *cfg.Fuzz
+	if cmd.Flags().Changed("fuzz") {
+		scheduler.Fuzz = scheduleFuzz
+	}
+	if cfg.TargetRetention != 0 {
+		if err := study.ValidateTargetRetention(cfg.TargetRetention); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		scheduler.TargetRetention = cfg.TargetRetention
+	}
+	if cfg.LeechThreshold != nil {
+		scheduler.LeechThreshold = *cfg.LeechThreshold
+	}
+	if scheduler.LearningSteps, err = study.ParseLearningSteps(cfg.LearningSteps); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if cfg.LapseInterval != "" {
+		if scheduler.LapseInterval, err = study.ParseDelay(cfg.LapseInterval); err != nil {
+			return fmt.Errorf("invalid config: lapse_interval %q: %w", cfg.LapseInterval, err)
+		}
+	}
+	if cfg.AgainPenalty != nil {
+		if err := study.ValidateEaseAdjustment("again_penalty", *cfg.AgainPenalty); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		scheduler.AgainPenalty = *cfg.AgainPenalty
+	}
+	if cfg.EasyBonus != nil {
+		if err := study.ValidateEaseAdjustment("easy_bonus", *cfg.EasyBonus); err != nil {
+			return fmt.Errorf("invalid config: %w", err)
+		}
+		scheduler.EasyBonus = *cfg.EasyBonus
+	}
+	if scheduler.TagEase, err = scheduler.ParseTagEase(cfg.TagEase); err != nil {
+		return fmt.Errorf("invalid config: tag_ease: %w", err)
+	}
+	study.SetSchedulerConfig(scheduler)
+	language := cfg.Language
+	if cmd.Flags().Changed("lang") {
+		language = promptLanguage
+	}
+	study.SetLanguage(language)
+	study.SetReflectionPersona(cfg.ReflectionPersona)
+	study.SetMaxPromptChars(cfg.MaxPromptChars)
+	study.SetSummarySections(cfg.SummarySections)
+	streamReplies = !noStream && (cfg.Stream == nil || *cfg.Stream)
+
+	return nil
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Ctrl-C cancels the context; interactive sessions stop at the next card and
+	// print a summary instead of dying mid-output.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	go watchInterrupts(ctx, stop)
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
-// The init function in root.go should be empty.
+// The init function in root.go only registers global flags.
 // Each command file (e.g., review.go, import.go) is responsible
 // for adding itself to the rootCmd in its own init() function.
 func init() {
-	// Intentionally empty.
+	rootCmd.PersistentFlags().IntVar(&renderWidth, "width", 0, "Wrap rendered notes at this many columns (default: terminal width)")
+	rootCmd.PersistentFlags().StringVar(&renderStyle, "style", "auto", "Markdown style: auto, dark, light, notty, or a path to a JSON style file")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (also set by $NO_COLOR or when output isn't a terminal)")
+	rootCmd.PersistentFlags().BoolVar(&renderPager, "pager", false, "Show long rendered notes through $PAGER (or less -R)")
+	rootCmd.PersistentFlags().BoolVar(&scheduleFuzz, "fuzz", false, "Add up to ±5% jitter to review intervals longer than 3 days to avoid pile-ups")
+	rootCmd.PersistentFlags().Float64Var(&targetRetention, "target-retention", study.DefaultTargetRetention, "Share of reviews you aim to recall (0.70-0.99); higher means shorter intervals")
+	rootCmd.PersistentFlags().BoolVar(&noPreflight, "no-preflight", false, "Don't check that the LLM model is available before starting a session")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Print machine-readable JSON instead of formatted text (list, due, links, search, stats, review --batch)")
+	rootCmd.PersistentFlags().StringVar(&providerName, "provider", study.ProviderOllama, "LLM provider to use: ollama, openai (reads the API key from $"+study.EnvAPIKey+")")
+	rootCmd.PersistentFlags().StringVar(&promptLanguage, "lang", "", "Language for generated questions, answers and chat, e.g. Spanish (default English, or the config file's language)")
+	rootCmd.PersistentFlags().StringVar(&modelName, "model", "", "LLM model to use instead of the provider's default (or the config file's model)")
+	rootCmd.PersistentFlags().Float64Var(&temperature, "temperature", 0, "Sampling temperature for every LLM request, replacing the per-task defaults")
+	rootCmd.PersistentFlags().IntVar(&seed, "seed", 0, "Random seed sent with every LLM request, for reproducible output with models that honor it")
 }
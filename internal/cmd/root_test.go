@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/config"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+func TestTargetRetentionFlagAppliesToOneRun(t *testing.T) {
+	if err := executeRoot(t, "tune", "--target-retention", "0.8"); err != nil {
+		t.Fatal(err)
+	}
+	if got := study.CurrentSchedulerConfig().TargetRetention; got != 0.8 {
+		t.Errorf("scheduler target retention = %g, want 0.8", got)
+	}
+	cfg, err := config.LoadFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TargetRetention != 0 {
+		t.Errorf("--target-retention saved %g to the config file, want nothing saved", cfg.TargetRetention)
+	}
+}
+
+// useConfigFile saves c as the config file for the rest of the test.
+func useConfigFile(t *testing.T, c *config.Config) {
+	t.Helper()
+	if err := config.Save(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { config.Save(&config.Config{}) })
+}
+
+func TestLeechThresholdFromConfig(t *testing.T) {
+	zero, five := 0, 5
+	tests := []struct {
+		name      string
+		threshold *int
+		want      int
+	}{
+		{"unset keeps the default", nil, study.DefaultLeechThreshold},
+		{"a number replaces it", &five, 5},
+		{"an explicit 0 turns it off", &zero, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useConfigFile(t, &config.Config{LeechThreshold: tt.threshold})
+			if err := executeRoot(t, "tune"); err != nil {
+				t.Fatal(err)
+			}
+			if got := study.CurrentSchedulerConfig().LeechThreshold; got != tt.want {
+				t.Errorf("leech threshold = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerCommandModel(t *testing.T) {
+	t.Setenv(config.EnvModel, "")
+	useConfigFile(t, &config.Config{Model: "default", Models: map[string]string{"tune": "small"}})
+	if err := executeRoot(t, "tune"); err != nil {
+		t.Fatal(err)
+	}
+	if got := study.ActiveModel(); got != "small" {
+		t.Errorf("tune used model %q, want its own %q", got, "small")
+	}
+	if err := executeRoot(t, "tune", "--model", "flag"); err != nil {
+		t.Fatal(err)
+	}
+	if got := study.ActiveModel(); got != "flag" {
+		t.Errorf("--model gave model %q, want %q", got, "flag")
+	}
+}
+
+func TestModelPrecedence(t *testing.T) {
+	useConfigFile(t, &config.Config{Model: "file", Models: map[string]string{"tune": "file-tune"}})
+	tests := []struct {
+		env  string
+		args []string
+		want string
+	}{
+		{"", []string{"tune"}, "file-tune"},
+		{"", []string{"list"}, "file"},
+		{"env", []string{"tune"}, "env"},
+		{"env", []string{"tune", "--model", "flag"}, "flag"},
+		{"", []string{"tune", "--model", "flag"}, "flag"},
+	}
+	for _, tt := range tests {
+		t.Setenv(config.EnvModel, tt.env)
+		captureStdout(t, func() {
+			if err := executeRoot(t, tt.args...); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if got := study.ActiveModel(); got != tt.want {
+			t.Errorf("%v with $%s=%q used model %q, want %q", tt.args, config.EnvModel, tt.env, got, tt.want)
+		}
+		resetFlags(rootCmd)
+	}
+}
+
+func TestSummarySectionsFromConfig(t *testing.T) {
+	t.Cleanup(func() { study.SetSummarySections(nil) })
+	useConfigFile(t, &config.Config{SummarySections: []string{"Overview"}})
+	if err := executeRoot(t, "tune"); err != nil {
+		t.Fatal(err)
+	}
+	content := "## Summary\nDefault section.\n## Overview\nConfigured section.\n"
+	if got := study.ExtractSummary(content); strings.TrimSpace(got) != "Configured section." {
+		t.Errorf("ExtractSummary() = %q, want the section named in the config file", got)
+	}
+}
+
+// colorCommands exercise the colored parts of the read-only and review output.
+var colorCommands = [][]string{
+	{"list"},
+	{"search", "alpha"},
+	{"links", "alpha.md"},
+	{"due", "--list"},
+	{"stats"},
+}
+
+// addColorNotes adds the notes colorCommands work on.
+func addColorNotes(t *testing.T) {
+	t.Helper()
+	database := testDB(t)
+	addCardNote(t, database, "alpha", -time.Hour, "go")
+	addCardNote(t, database, "beta", time.Hour, "go")
+}
+
+// colorOutput runs colorCommands with the extra arguments, plus a review of
+// one card, on fresh notes and returns what they printed.
+func colorOutput(t *testing.T, extra ...string) string {
+	t.Helper()
+	addColorNotes(t)
+	var output strings.Builder
+	for _, args := range colorCommands {
+		output.WriteString(captureStdout(t, func() {
+			if err := executeRoot(t, append(args, extra...)...); err != nil {
+				t.Fatalf("%v: %v", args, err)
+			}
+		}))
+		resetFlags(rootCmd)
+	}
+	// Reveal the answer, skip the full note, then rate it Good.
+	review, err := runReview(t, "\nn\n2\n", append([]string{"--count", "1"}, extra...)...)
+	if err != nil {
+		t.Fatalf("review: %v", err)
+	}
+	if !strings.Contains(review, "Concise Answer") {
+		t.Fatalf("review didn't show a card:\n%s", review)
+	}
+	resetFlags(rootCmd)
+	return output.String() + review
+}
+
+func TestNoColorFlag(t *testing.T) {
+	noColor := color.NoColor
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	// Pretend stdout is a terminal, so color is on until --no-color turns it off.
+	color.NoColor = false
+	if output := colorOutput(t); !strings.Contains(output, "\x1b[") {
+		t.Fatalf("colored output has no escape codes, so the test can't tell:\n%s", output)
+	}
+	color.NoColor = false
+	if output := colorOutput(t, "--no-color"); strings.Contains(output, "\x1b[") {
+		t.Errorf("--no-color output has escape codes:\n%q", output)
+	}
+}
+
+// TestPipedOutputHelper is run by TestPipedOutputHasNoColor in a child process
+// whose stdout is a pipe, so color is decided the way it is for a real run.
+func TestPipedOutputHelper(t *testing.T) {
+	if os.Getenv("NEURON_PIPED_OUTPUT_HELPER") != "1" {
+		t.Skip("run by TestPipedOutputHasNoColor")
+	}
+	// Write to the real, piped stdout rather than captureStdout's pipe.
+	fmt.Fprint(os.Stdout, colorOutput(t))
+}
+
+func TestPipedOutputHasNoColor(t *testing.T) {
+	child := exec.Command(os.Args[0], "-test.run=^TestPipedOutputHelper$", "-test.v")
+	child.Env = append(os.Environ(), "NEURON_PIPED_OUTPUT_HELPER=1", "NO_COLOR=")
+	output, err := child.Output()
+	if err != nil {
+		t.Fatalf("helper failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "--- PASS: TestPipedOutputHelper") {
+		t.Fatalf("helper didn't run the commands:\n%q", output)
+	}
+	if strings.Contains(string(output), "\x1b[") {
+		t.Errorf("piped output has escape codes:\n%q", output)
+	}
+}
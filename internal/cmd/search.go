@@ -0,0 +1,186 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var searchLimit int
+var searchOffset int
+
+// searchResult is one match in search's JSON output.
+type searchResult struct {
+	Title    string   `json:"title"`
+	Filename string   `json:"filename"`
+	Tags     []string `json:"tags"`
+	Snippet  string   `json:"snippet,omitempty"`
+}
+
+// searchPage is search's JSON output: one page of matches and the total count.
+type searchPage struct {
+	Total   int            `json:"total"`
+	Offset  int            `json:"offset"`
+	Results []searchResult `json:"results"`
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Find notes by title, filename, alias, or content",
+	Long: `Lists the notes whose title, filename, aliases, or content contain the term
+(case-insensitive), ordered by title. Results come in pages of --limit notes;
+use --offset to see the next ones.
+
+Matches are highlighted in the titles and snippets; overlapping matches, like
+"aa" in "aaa", are highlighted as one.`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if searchLimit < 1 {
+			return fmt.Errorf("--limit must be at least 1, got %d", searchLimit)
+		}
+		if searchOffset < 0 {
+			return fmt.Errorf("--offset must not be negative, got %d", searchOffset)
+		}
+		term := strings.Join(args, " ")
+
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		total, err := db.CountSearchNotes(database, term)
+		if err != nil {
+			return fmt.Errorf("failed to count matches: %w", err)
+		}
+		notes, err := db.SearchNotesPage(database, term, searchLimit, searchOffset)
+		if err != nil {
+			return fmt.Errorf("failed to search notes: %w", err)
+		}
+
+		page := searchPage{Total: total, Offset: searchOffset, Results: make([]searchResult, 0, len(notes))}
+		for _, n := range notes {
+			tags := n.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			page.Results = append(page.Results, searchResult{Title: n.Title, Filename: n.Filename, Tags: tags, Snippet: searchSnippet(n.Content, term)})
+		}
+
+		return newOutputter().Print(page, func() {
+			if total == 0 {
+				fmt.Printf("No notes match %q.\n", term)
+				return
+			}
+			if len(notes) == 0 {
+				fmt.Printf("Only %d note(s) match %q; --offset %d is past the end.\n", total, term, searchOffset)
+				return
+			}
+			tagColor := color.New(color.FgCyan)
+			for _, result := range page.Results {
+				fmt.Printf("• %s", highlight(result.Title, term))
+				if len(result.Tags) > 0 {
+					tagColor.Printf("  [%s]", strings.Join(result.Tags, ", "))
+				}
+				fmt.Println()
+				if result.Snippet != "" {
+					fmt.Printf("    %s\n", highlight(result.Snippet, term))
+				}
+			}
+			first, last := searchOffset+1, searchOffset+len(notes)
+			fmt.Printf("\nShowing %d-%d of %d", first, last, total)
+			if last < total {
+				fmt.Printf(" (next page: --offset %d)", last)
+			}
+			fmt.Println()
+		})
+	},
+}
+
+// snippetContext is how many bytes of the line search shows on each side of a match.
+const snippetContext = 40
+
+// searchSnippet returns the part of the first content line matching term
+// (case-insensitive), trimmed to the text around the match. The title heading
+// is skipped since search prints the title anyway.
+func searchSnippet(content, term string) string {
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			continue
+		}
+		loc := pattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		start, end := max(0, loc[0]-snippetContext), min(len(line), loc[1]+snippetContext)
+		for start > 0 && !utf8.RuneStart(line[start]) {
+			start--
+		}
+		for end < len(line) && !utf8.RuneStart(line[end]) {
+			end++
+		}
+		snippet := line[start:end]
+		if start > 0 {
+			snippet = "…" + snippet
+		}
+		if end < len(line) {
+			snippet += "…"
+		}
+		return snippet
+	}
+	return ""
+}
+
+// highlightColor marks search matches. fatih/color drops the escape codes when
+// color is off (--no-color, $NO_COLOR, or output that isn't a terminal).
+var highlightColor = color.New(color.Bold, color.Underline)
+
+// highlight returns text with every case-insensitive occurrence of query styled
+// with highlightColor. Overlapping occurrences are merged into one highlight, so
+// "aa" in "aaa" highlights all three letters.
+func highlight(text, query string) string {
+	if query == "" {
+		return text
+	}
+	pattern := regexp.MustCompile("(?i)" + regexp.QuoteMeta(query))
+	// Look for a match starting at every rune, not just after the previous
+	// match, so overlapping occurrences are found too.
+	var spans [][2]int
+	for pos := 0; pos < len(text); {
+		loc := pattern.FindStringIndex(text[pos:])
+		if loc == nil {
+			break
+		}
+		start, end := pos+loc[0], pos+loc[1]
+		if last := len(spans) - 1; last >= 0 && start < spans[last][1] {
+			spans[last][1] = max(spans[last][1], end)
+		} else {
+			spans = append(spans, [2]int{start, end})
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		pos = start + size
+	}
+
+	var b strings.Builder
+	prev := 0
+	for _, span := range spans {
+		b.WriteString(text[prev:span[0]])
+		b.WriteString(highlightColor.Sprint(text[span[0]:span[1]]))
+		prev = span[1]
+	}
+	b.WriteString(text[prev:])
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().IntVar(&searchLimit, "limit", 20, "Show at most this many matches")
+	searchCmd.Flags().IntVar(&searchOffset, "offset", 0, "Skip this many matches, to page through the results")
+}
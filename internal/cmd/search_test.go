@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// useColor forces colored output on or off for the rest of the test.
+func useColor(t *testing.T, on bool) {
+	t.Helper()
+	noColor := color.NoColor
+	color.NoColor = !on
+	t.Cleanup(func() { color.NoColor = noColor })
+}
+
+func TestHighlight(t *testing.T) {
+	useColor(t, true)
+	mark := func(s string) string { return highlightColor.Sprint(s) }
+	if mark("go") == "go" {
+		t.Fatal("highlightColor added no escape codes with color on")
+	}
+	tests := []struct {
+		name        string
+		text, query string
+		want        string
+	}{
+		{"single match", "go routines", "go", mark("go") + " routines"},
+		{"multiple matches", "go and go", "go", mark("go") + " and " + mark("go")},
+		{"case-insensitive", "Go GO go", "go", mark("Go") + " " + mark("GO") + " " + mark("go")},
+		{"overlapping matches merge", "aaa", "aa", mark("aaa")},
+		{"overlap then separate", "aaa b aa", "aa", mark("aaa") + " b " + mark("aa")},
+		{"adjacent matches", "abab", "ab", mark("ab") + mark("ab")},
+		{"multibyte text", "café Café", "CAFÉ", mark("café") + " " + mark("Café")},
+		{"regexp characters are literal", "a.b axb", "a.b", mark("a.b") + " axb"},
+		{"no match", "nothing here", "go", "nothing here"},
+		{"empty query", "text", "", "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlight(tt.text, tt.query); got != tt.want {
+				t.Errorf("highlight(%q, %q) = %q, want %q", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightWithoutColor(t *testing.T) {
+	useColor(t, false)
+	if got := highlight("Go and go", "go"); got != "Go and go" {
+		t.Errorf("highlight without color = %q, want the text unchanged", got)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 
 import (
 	"bufio"
-	"database/sql"
 	"fmt"
 	"os"
 	"strings"
@@ -15,6 +14,7 @@ import (
 )
 
 var selfTestQuestionType string
+var selfTestNoSchedule bool
 
 var selfTestCmd = &cobra.Command{
 	Use:   "self-test [topic]",
@@ -23,12 +23,17 @@ var selfTestCmd = &cobra.Command{
 before seeing the AI-generated answer. This forces active recall and helps
 identify knowledge gaps.
 
+After each answer you rate your recall (1-3). When the session ends, the
+lowest rating is used to update the note's review schedule, so self-testing
+counts toward spaced repetition. Use --no-schedule for pure practice.
+
 Use --question-type to specify the type of questions:
 - factual: Questions about definitions, facts, and specific details
 - conceptual: Questions about relationships, principles, and "why" things work
 - application: Questions about applying concepts to real scenarios
 - mixed: A mix of all question types (default)`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		topic := args[0]
 
@@ -37,12 +42,8 @@ Use --question-type to specify the type of questions:
 			return err
 		}
 
-		noteToTest, err := db.GetNoteByTitleOrFilename(database, topic)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				fmt.Printf("Sorry, I couldn't find a note matching '%s'.\n", topic)
-				return nil
-			}
+		noteToTest, err := lookupNote(database, topic)
+		if err != nil || noteToTest == nil {
 			return err
 		}
 
@@ -61,27 +62,38 @@ Use --question-type to specify the type of questions:
 
 		// Show available commands at start
 		helpColor := color.New(color.FgGreen)
-		helpColor.Println("\n💡 Tip: Type 'help' anytime to see available commands\n")
+		helpColor.Print("\n💡 Tip: Type 'help' anytime to see available commands\n\n")
 
+		lowestRating := 0 // 0 means nothing was rated this session
 		questionCount := 0
+		var question string
+		reuseQuestion := false // set after a hint so the same question is asked again
 		for {
-			questionCount++
+			if !reuseQuestion {
+				questionCount++
 
-			// Generate question with variation hint
-			fmt.Printf("🧠 Generating %s question (#%d)...\n", qType, questionCount)
+				// Generate question with variation hint
+				fmt.Printf("🧠 Generating %s question (#%d)...\n", qType, questionCount)
 
-			// Add a small random element to prompt to force variation
-			question, err := study.GenerateQuestionWithVariation(noteToTest, qType, questionCount)
-			if err != nil {
-				return fmt.Errorf("failed to generate question: %w", err)
+				// Add a small random element to prompt to force variation
+				var err error
+				question, err = study.GenerateQuestionWithVariation(noteToTest, qType, questionCount)
+				if err != nil {
+					return fmt.Errorf("failed to generate question: %w", err)
+				}
 			}
+			reuseQuestion = false
 
 			questionColor := color.New(color.FgCyan)
 			questionColor.Printf("\n🤔 Question: %s\n", question)
 
 			// Check for special commands
 			fmt.Print("\nType your answer (or 'help' for commands): ")
-			userInput, _ := reader.ReadString('\n')
+			userInput, err := readLine(reader)
+			if err != nil {
+				fmt.Println("\nInput closed; ending the session.")
+				break
+			}
 			userInput = strings.TrimSpace(userInput)
 
 			// Check for special commands
@@ -90,6 +102,7 @@ Use --question-type to specify the type of questions:
 				helpColor.Println("\n🛠️  Available Commands:")
 				fmt.Println("  • 'help' or '?' - Show this help message")
 				fmt.Println("  • 'note' or 'show note' - Display the full note content")
+				fmt.Println("  • 'hint' - Get a short hint for this question")
 				fmt.Println("  • 'skip' - Skip this question")
 				fmt.Println("  • 'quit' or 'exit' - End the session")
 				fmt.Println("  • Type your answer to test your knowledge")
@@ -105,13 +118,21 @@ Use --question-type to specify the type of questions:
 			if strings.ToLower(userInput) == "note" || strings.ToLower(userInput) == "show note" {
 				fmt.Println("\n📖 Full Note Content:")
 				fmt.Println("-----------------------------------------------------------")
-				rendered, err := renderMarkdown(noteToTest.Content)
+				printMarkdown(noteToTest.Content)
+				fmt.Println("-----------------------------------------------------------")
+				continue
+			}
+
+			if strings.ToLower(userInput) == "hint" {
+				fmt.Println("\n🔎 Generating hint...")
+				hint, err := study.GenerateHint(question, noteToTest)
 				if err != nil {
-					fmt.Println(noteToTest.Content)
+					fmt.Printf("Could not generate a hint: %v\n", err)
 				} else {
-					fmt.Println(rendered)
+					hintColor := color.New(color.FgYellow)
+					hintColor.Printf("\n💭 Hint: %s\n", hint)
 				}
-				fmt.Println("-----------------------------------------------------------")
+				reuseQuestion = true
 				continue
 			}
 
@@ -126,43 +147,28 @@ Use --question-type to specify the type of questions:
 				continue
 			}
 
-			// Generate AI answer
-			fmt.Println("\n🤖 Generating AI answer for comparison...")
-			aiAnswer, err := study.GenerateAnswer(question, noteToTest)
-			if err != nil {
-				return fmt.Errorf("failed to generate AI answer: %w", err)
+			if err := showComparison(question, userInput, noteToTest); err != nil {
+				return err
 			}
 
-			// Compare answers
-			fmt.Println("\n🔍 Analyzing your answer...")
-			comparison, err := study.CompareAnswers(userInput, aiAnswer, question)
-			if err != nil {
-				return fmt.Errorf("failed to compare answers: %w", err)
+			if !selfTestNoSchedule {
+				rating, err := readRating(reader, false)
+				if err != nil {
+					fmt.Println("Input closed; ending the session.")
+					break
+				}
+				if lowestRating == 0 || rating < lowestRating {
+					lowestRating = rating
+				}
 			}
 
-			// Display results
-			fmt.Println("\n" + strings.Repeat("=", 60))
-			fmt.Println("📊 COMPARISON RESULTS")
-			fmt.Println(strings.Repeat("=", 60))
-
-			userColor := color.New(color.FgYellow)
-			aiColor := color.New(color.FgMagenta)
-			feedbackColor := color.New(color.FgGreen)
-
-			fmt.Print("\n💭 Your Answer: ")
-			userColor.Println(userInput)
-
-			fmt.Print("\n🤖 AI Answer: ")
-			aiColor.Println(aiAnswer)
-
-			fmt.Print("\n📝 Feedback: ")
-			feedbackColor.Println(comparison)
-
-			fmt.Println(strings.Repeat("=", 60))
-
 			// Ask if user wants to continue
 			fmt.Print("\nContinue with another question? (y/n): ")
-			continueInput, _ := reader.ReadString('\n')
+			continueInput, err := readLine(reader)
+			if err != nil {
+				fmt.Println("\nInput closed; ending the session.")
+				break
+			}
 			continueInput = strings.TrimSpace(strings.ToLower(continueInput))
 
 			if continueInput == "n" || continueInput == "no" {
@@ -171,11 +177,20 @@ Use --question-type to specify the type of questions:
 			}
 		}
 
+		if lowestRating > 0 {
+			if err := saveRating(database, noteToTest, lowestRating, 0); err != nil {
+				return err
+			}
+			fmt.Printf("✓ This note is scheduled for review in about %s.\n", untilDue(noteToTest.DueDate))
+		}
+
 		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(selfTestCmd)
+	addIndexFlag(selfTestCmd)
 	selfTestCmd.Flags().StringVar(&selfTestQuestionType, "question-type", "mixed", "Type of question to generate: factual, conceptual, application, mixed")
+	selfTestCmd.Flags().BoolVar(&selfTestNoSchedule, "no-schedule", false, "Practice only: don't ask for ratings or update the review schedule")
 }
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+func TestSelfTestRatingMovesDueDate(t *testing.T) {
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/selftest.md", "# Self test\nbody")
+	useFakeProvider(t, "80 - close enough")
+	selfTestNoSchedule = false
+
+	// Answer, rate it Good, then stop.
+	withStdin(t, "my answer\n2\nn\n")
+	if err := selfTestCmd.RunE(selfTestCmd, []string{n.Title}); err != nil {
+		t.Fatal(err)
+	}
+	after, err := db.GetNoteByTitleOrFilename(database, n.Title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.DueDate.After(time.Now()) {
+		t.Errorf("due date %v should have moved into the future from %v", after.DueDate, n.DueDate)
+	}
+	if reviews, _ := db.CountReviews(database); reviews != 1 {
+		t.Errorf("logged %d reviews, want 1", reviews)
+	}
+}
+
+func TestSelfTestNoScheduleKeepsDueDate(t *testing.T) {
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/practice.md", "# Practice\nbody")
+	useFakeProvider(t, "80 - close enough")
+	selfTestNoSchedule = true
+	t.Cleanup(func() { selfTestNoSchedule = false })
+
+	withStdin(t, "my answer\nn\n")
+	if err := selfTestCmd.RunE(selfTestCmd, []string{n.Title}); err != nil {
+		t.Fatal(err)
+	}
+	after, err := db.GetNoteByTitleOrFilename(database, n.Title)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !after.DueDate.Equal(n.DueDate) {
+		t.Errorf("due date changed from %v to %v with --no-schedule", n.DueDate, after.DueDate)
+	}
+}
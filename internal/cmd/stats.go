@@ -0,0 +1,219 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// statsSlowest is how many of the slowest notes stats lists.
+const statsSlowest = 5
+
+// statsRetention switches stats to the week-by-week retention view.
+var statsRetention bool
+
+// sparkBars are the sparkline levels, lowest first.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// thinkStats is the aggregated think time over the review log.
+type thinkStats struct {
+	Reviews   int             `json:"timed_reviews"`
+	AverageMS int64           `json:"average_think_ms"`
+	Notes     []noteThinkTime `json:"notes"`
+}
+
+// noteThinkTime is one note's average think time, across its timed reviews.
+type noteThinkTime struct {
+	Title     string `json:"title"`
+	Reviews   int    `json:"reviews"`
+	AverageMS int64  `json:"average_think_ms"`
+}
+
+// statsSummary is stats' JSON output.
+type statsSummary struct {
+	TotalNotes     int        `json:"total_notes"`
+	SuspendedNotes int        `json:"suspended_notes"`
+	RetiredNotes   int        `json:"retired_notes"`
+	TotalReviews   int        `json:"total_reviews"`
+	ThinkTime      thinkStats `json:"think_time"`
+}
+
+// summarizeThinkTimes averages the timed reviews overall and per note. Notes are
+// ordered slowest first, ties broken by title so the output is stable.
+func summarizeThinkTimes(timings []db.ReviewTiming) thinkStats {
+	type total struct {
+		title string
+		count int
+		sum   time.Duration
+	}
+	byNote := make(map[int]*total)
+	var sum time.Duration
+	for _, t := range timings {
+		sum += t.Think
+		nt := byNote[t.NoteID]
+		if nt == nil {
+			nt = &total{title: t.Title}
+			byNote[t.NoteID] = nt
+		}
+		nt.count++
+		nt.sum += t.Think
+	}
+
+	stats := thinkStats{Reviews: len(timings), Notes: make([]noteThinkTime, 0, len(byNote))}
+	if len(timings) > 0 {
+		stats.AverageMS = (sum / time.Duration(len(timings))).Milliseconds()
+	}
+	for _, nt := range byNote {
+		stats.Notes = append(stats.Notes, noteThinkTime{
+			Title:     nt.title,
+			Reviews:   nt.count,
+			AverageMS: (nt.sum / time.Duration(nt.count)).Milliseconds(),
+		})
+	}
+	sort.Slice(stats.Notes, func(i, j int) bool {
+		if stats.Notes[i].AverageMS != stats.Notes[j].AverageMS {
+			return stats.Notes[i].AverageMS > stats.Notes[j].AverageMS
+		}
+		return stats.Notes[i].Title < stats.Notes[j].Title
+	})
+	return stats
+}
+
+// weekRetention is one week of stats --retention's JSON output.
+type weekRetention struct {
+	Week      string  `json:"week"`
+	Reviews   int     `json:"reviews"`
+	Again     int     `json:"again"`
+	Recalled  int     `json:"recalled"`
+	Retention float64 `json:"retention"`
+}
+
+// sparkline draws one bar per value, each between 0 and 1.
+func sparkline(values []float64) string {
+	bars := make([]rune, len(values))
+	for i, v := range values {
+		level := int(v * float64(len(sparkBars)-1))
+		bars[i] = sparkBars[min(max(level, 0), len(sparkBars)-1)]
+	}
+	return string(bars)
+}
+
+// printRetention shows the fraction of Good/Easy ratings per week of the review log.
+func printRetention(database *sql.DB) error {
+	weeks, err := db.RetentionByWeek(database)
+	if err != nil {
+		return fmt.Errorf("failed to load the review log: %w", err)
+	}
+	rows := make([]weekRetention, 0, len(weeks))
+	values := make([]float64, 0, len(weeks))
+	for _, w := range weeks {
+		rows = append(rows, weekRetention{
+			Week:      w.WeekStart.Format(time.DateOnly),
+			Reviews:   w.Reviews(),
+			Again:     w.Again,
+			Recalled:  w.Recalled,
+			Retention: w.Retention(),
+		})
+		values = append(values, w.Retention())
+	}
+
+	return newOutputter().Print(rows, func() {
+		fmt.Println("--- Retention by Week ---")
+		if len(rows) == 0 {
+			fmt.Println("\nNo reviews logged yet. Run 'neuron review' or 'neuron mix' to start collecting them.")
+			return
+		}
+		fmt.Printf("  %-10s  %7s  %5s  %9s  %9s\n", "Week of", "Reviews", "Again", "Good/Easy", "Retention")
+		for _, r := range rows {
+			fmt.Printf("  %-10s  %7d  %5d  %9d  %8.0f%%\n", r.Week, r.Reviews, r.Again, r.Recalled, r.Retention*100)
+		}
+		fmt.Printf("\n  %s  (oldest → newest)\n", sparkline(values))
+		fmt.Println("\nRetention is the share of reviews rated Good or Easy. If it stays low, your intervals may be too aggressive.")
+	})
+}
+
+// formatThinkMS renders a think time in milliseconds as seconds, e.g. "12.3s".
+func formatThinkMS(ms int64) string {
+	return fmt.Sprintf("%.1fs", float64(ms)/1000)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show review statistics, such as how long you think before answering",
+	Long: `Counts your notes, including how many are suspended or retired, and
+summarizes the review log: how many ratings have been recorded and how long,
+on average, you spend between seeing a question and revealing its answer in
+review and mix. The notes you take longest on are listed first; with --json every
+timed note is included.
+
+Use --retention to see, week by week, the share of reviews rated Good or Easy
+rather than Again: a rough measure of retention that shows whether your
+intervals are too aggressive.`,
+	Args:        cobra.NoArgs,
+	Annotations: supportsJSON,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		if statsRetention {
+			return printRetention(database)
+		}
+
+		counts, err := db.CountNotes(database)
+		if err != nil {
+			return fmt.Errorf("failed to count notes: %w", err)
+		}
+		total, err := db.CountReviews(database)
+		if err != nil {
+			return fmt.Errorf("failed to count reviews: %w", err)
+		}
+		timings, err := db.ReviewTimings(database)
+		if err != nil {
+			return fmt.Errorf("failed to load review times: %w", err)
+		}
+		summary := statsSummary{
+			TotalNotes:     counts.Total,
+			SuspendedNotes: counts.Suspended,
+			RetiredNotes:   counts.Retired,
+			TotalReviews:   total,
+			ThinkTime:      summarizeThinkTimes(timings),
+		}
+
+		return newOutputter().Print(summary, func() {
+			fmt.Println("--- Review Statistics ---")
+			fmt.Printf("  %-20s %5d\n", "Notes", summary.TotalNotes)
+			fmt.Printf("  %-20s %5d\n", "Suspended", summary.SuspendedNotes)
+			fmt.Printf("  %-20s %5d\n", "Retired", summary.RetiredNotes)
+			fmt.Printf("  %-20s %5d\n", "Reviews logged", summary.TotalReviews)
+			if summary.ThinkTime.Reviews == 0 {
+				fmt.Println("\nNo timed reviews yet. Run 'neuron review' or 'neuron mix' to start collecting them.")
+				return
+			}
+			fmt.Printf("  %-20s %5s\n", "Average think time", formatThinkMS(summary.ThinkTime.AverageMS))
+
+			color.New(color.FgCyan, color.Bold).Println("\n🐢 Slowest notes:")
+			for i, n := range summary.ThinkTime.Notes {
+				if i == statsSlowest {
+					break
+				}
+				reviews := "reviews"
+				if n.Reviews == 1 {
+					reviews = "review"
+				}
+				fmt.Printf("  • %s — %s (%d %s)\n", n.Title, formatThinkMS(n.AverageMS), n.Reviews, reviews)
+			}
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsRetention, "retention", false, "Show the share of Good/Easy ratings per week instead")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+)
+
+func TestSummarizeThinkTimes(t *testing.T) {
+	stats := summarizeThinkTimes([]db.ReviewTiming{
+		{NoteID: 1, Title: "Fast", Think: 2 * time.Second},
+		{NoteID: 2, Title: "Slow", Think: 10 * time.Second},
+		{NoteID: 1, Title: "Fast", Think: 4 * time.Second},
+		{NoteID: 3, Title: "Also slow", Think: 10 * time.Second},
+	})
+	if stats.Reviews != 4 || stats.AverageMS != 6500 {
+		t.Errorf("reviews %d, average %dms; want 4, 6500ms", stats.Reviews, stats.AverageMS)
+	}
+	want := []noteThinkTime{
+		{Title: "Also slow", Reviews: 1, AverageMS: 10000},
+		{Title: "Slow", Reviews: 1, AverageMS: 10000},
+		{Title: "Fast", Reviews: 2, AverageMS: 3000},
+	}
+	if len(stats.Notes) != len(want) {
+		t.Fatalf("got %d notes, want %d", len(stats.Notes), len(want))
+	}
+	for i := range want {
+		if stats.Notes[i] != want[i] {
+			t.Errorf("note %d = %+v, want %+v", i, stats.Notes[i], want[i])
+		}
+	}
+}
+
+func TestSummarizeThinkTimesWithoutTimings(t *testing.T) {
+	stats := summarizeThinkTimes(nil)
+	if stats.Reviews != 0 || stats.AverageMS != 0 || len(stats.Notes) != 0 {
+		t.Errorf("got %+v, want an empty summary", stats)
+	}
+}
+
+func TestUntimedReviewsAreLeftOutOfThinkTimes(t *testing.T) {
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/timed.md", "# Timed\nbody")
+	if err := saveRating(database, n, 2, 3*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	// Quiz and self-test don't time the question.
+	if err := saveRating(database, n, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	timings, err := db.ReviewTimings(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timings) != 1 || timings[0].Think != 3*time.Second {
+		t.Errorf("timings = %+v, want only the 3s review", timings)
+	}
+	if count, err := db.CountReviews(database); err != nil || count != 2 {
+		t.Errorf("CountReviews = %d, %v; want both reviews logged", count, err)
+	}
+}
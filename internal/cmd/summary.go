@@ -0,0 +1,57 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var summarySave bool
+
+var summarizeCmd = &cobra.Command{
+	Use:   "summary [topic]",
+	Short: "Get a quick AI-generated TL;DR of a note",
+	Long: `Condenses a note into three bullet points and one key takeaway.
+Use --save to write the summary into the note file under a "## Summary"
+section (replacing an existing one), which review questions then focus on.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, n, err := findTopicNote(args[0])
+		if err != nil || n == nil {
+			return err
+		}
+
+		fmt.Printf("🧠 Summarizing %s...\n", n.Title)
+		summary, err := study.GenerateSummary(n)
+		if err != nil {
+			return fmt.Errorf("failed to generate summary: %w", err)
+		}
+
+		printMarkdown("## Summary: " + n.Title + "\n\n" + summary)
+
+		if !summarySave {
+			return nil
+		}
+		// Edit the file on disk, not the database copy, so nothing written since the last import is lost.
+		content, err := os.ReadFile(n.Filename)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", n.Filename, err)
+		}
+		updated := note.SetSection(string(content), "Summary", summary)
+		if err := os.WriteFile(n.Filename, []byte(updated), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", n.Filename, err)
+		}
+		fmt.Printf("✓ Saved the summary to %s. Run 'neuron import' to sync it.\n", n.Filename)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(summarizeCmd)
+	summarizeCmd.Flags().BoolVar(&summarySave, "save", false, "Write the summary into the note under a '## Summary' section")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSummarySave(t *testing.T) {
+	database := testDB(t)
+	// The file has changed since it was imported; --save must keep the new text.
+	path := writeNoteFile(t, t.TempDir(), "maps.md", "# Maps\n\n## Details\nBuckets, edited after import.\n")
+	addTestNote(t, database, path, "# Maps\n\n## Details\nBuckets.\n")
+	useFakeProvider(t, "- Maps are hash tables.\n\n**Key takeaway:** Lookups are O(1).")
+	t.Cleanup(func() { summarySave = false })
+
+	for _, save := range []bool{false, true} {
+		summarySave = save
+		var err error
+		output := captureStdout(t, func() { err = summarizeCmd.RunE(summarizeCmd, []string{"maps.md"}) })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(output, "Lookups are O(1).") {
+			t.Errorf("save=%v: summary isn't printed:\n%s", save, output)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved := strings.Contains(string(data), "## Summary"); saved != save {
+			t.Errorf("save=%v: file has a summary section = %v:\n%s", save, saved, data)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Maps\n\n## Summary\n\n- Maps are hash tables.\n\n**Key takeaway:** Lookups are O(1).\n\n## Details\nBuckets, edited after import.\n"
+	if string(data) != want {
+		t.Errorf("saved note =\n%q\nwant\n%q", data, want)
+	}
+}
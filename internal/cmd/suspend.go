@@ -0,0 +1,95 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/spf13/cobra"
+)
+
+var suspendCmd = &cobra.Command{
+	Use:   "suspend [topic]",
+	Short: "Keep a note out of reviews until it is unsuspended",
+	Long: `Suspends a note, e.g. while it is still under construction.
+Suspended notes are skipped by review, mix, and other due-note queries.
+Its schedule is kept, so 'neuron unsuspend' picks up where you left off.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setNoteSuspended(args[0], true)
+	},
+}
+
+var unsuspendCmd = &cobra.Command{
+	Use:   "unsuspend [topic]",
+	Short: "Return a suspended note to reviews",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setNoteSuspended(args[0], false)
+	},
+}
+
+var buryCmd = &cobra.Command{
+	Use:   "bury [topic]",
+	Short: "Postpone a note until tomorrow without changing its schedule",
+	Long: `Moves a note's due date to tomorrow. Unlike rating it, burying keeps
+its interval and ease factor unchanged.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, n, err := findTopicNote(args[0])
+		if err != nil || n == nil {
+			return err
+		}
+
+		now := time.Now()
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+		if err := db.BuryNote(database, n.ID, tomorrow); err != nil {
+			return fmt.Errorf("failed to bury note: %w", err)
+		}
+		fmt.Printf("✓ Buried '%s' until tomorrow.\n", n.Title)
+		return nil
+	},
+}
+
+// findTopicNote looks up the note matching topic. It returns a nil note (and no error)
+// after telling the user when nothing, or more than one note, matches.
+func findTopicNote(topic string) (*sql.DB, *note.Note, error) {
+	database, err := db.GetDB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	n, err := lookupNote(database, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	return database, n, nil
+}
+
+// setNoteSuspended suspends or unsuspends the note matching topic.
+func setNoteSuspended(topic string, suspended bool) error {
+	database, n, err := findTopicNote(topic)
+	if err != nil || n == nil {
+		return err
+	}
+	if err := db.SetSuspended(database, n.ID, suspended); err != nil {
+		return fmt.Errorf("failed to update note: %w", err)
+	}
+	if suspended {
+		fmt.Printf("✓ Suspended '%s'. It won't show up in reviews until you unsuspend it.\n", n.Title)
+	} else {
+		fmt.Printf("✓ '%s' is back in your reviews.\n", n.Title)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(suspendCmd)
+	rootCmd.AddCommand(unsuspendCmd)
+	rootCmd.AddCommand(buryCmd)
+	for _, c := range []*cobra.Command{suspendCmd, unsuspendCmd, buryCmd} {
+		addIndexFlag(c)
+	}
+}
@@ -3,7 +3,6 @@ package cmd
 
 import (
 	"bufio"
-	"database/sql"
 	"fmt"
 	"os"
 	"strings"
@@ -14,10 +13,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var teachSave string
+var teachResume string
+
 var teachCmd = &cobra.Command{
-	Use:   "teach [topic]",
-	Short: "Deepen your understanding of a topic using the Feynman Technique",
-	Args:  cobra.ExactArgs(1),
+	Use:         "teach [topic]",
+	Short:       "Deepen your understanding of a topic using the Feynman Technique",
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		topic := args[0]
 
@@ -26,12 +29,8 @@ var teachCmd = &cobra.Command{
 			return err
 		}
 
-		noteToTeach, err := db.GetNoteByTitleOrFilename(database, topic)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				fmt.Printf("Sorry, I couldn't find a note matching '%s'.\n", topic)
-				return nil
-			}
+		noteToTeach, err := lookupNote(database, topic)
+		if err != nil || noteToTeach == nil {
 			return err
 		}
 
@@ -50,6 +49,18 @@ var teachCmd = &cobra.Command{
 			},
 		}
 
+		// Continue an earlier conversation instead of starting fresh
+		awaitingUser := false
+		if teachResume != "" {
+			messages, err = study.LoadTranscript(teachResume)
+			if err != nil {
+				return fmt.Errorf("failed to resume conversation: %w", err)
+			}
+			fmt.Printf("Resuming conversation from %s (%d messages).\n", teachResume, len(messages))
+			awaitingUser = messages[len(messages)-1].Role == "assistant"
+		}
+		defer saveConversation(teachSave, &messages)
+
 		aiColor := color.New(color.FgCyan)
 		userColor := color.New(color.FgYellow, color.Bold)
 
@@ -57,19 +68,28 @@ var teachCmd = &cobra.Command{
 
 		// Show available commands at start
 		helpColor := color.New(color.FgGreen)
-		helpColor.Println("\n💡 Tip: Type 'help' anytime to see available commands\n")
+		helpColor.Print("\n💡 Tip: Type 'help' anytime to see available commands\n\n")
 
 		for {
-			aiResponse, err := study.SendChatMessage(messages)
-			if err != nil {
-				return err
+			if awaitingUser {
+				// The resumed transcript ends with a question we haven't answered yet
+				awaitingUser = false
+				aiColor.Printf("\n🤖 AI Student: %s\n", messages[len(messages)-1].Content)
+			} else {
+				aiResponse, err := chatReply(messages, aiColor, "🤖 AI Student: ")
+				if err != nil {
+					return err
+				}
+				messages = append(messages, aiResponse)
 			}
-			messages = append(messages, aiResponse)
 
-			aiColor.Printf("\n🤖 AI Student: %s\n", aiResponse.Content)
 			userColor.Print("You: ")
 
-			userInput, _ := reader.ReadString('\n')
+			userInput, err := readLine(reader)
+			if err != nil {
+				fmt.Println("\nInput closed; ending the Feynman session.")
+				break
+			}
 			userInput = strings.TrimSpace(userInput)
 
 			// Check for special commands
@@ -94,4 +114,8 @@ var teachCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(teachCmd)
+	addIndexFlag(teachCmd)
+	teachCmd.Flags().StringVar(&teachSave, "save", "", "Write the conversation to this file when the session ends (.json or Markdown)")
+	teachCmd.Flags().BoolVar(&noStream, "no-stream", false, "Print each reply once it is complete instead of as it is generated")
+	teachCmd.Flags().StringVar(&teachResume, "resume", "", "Continue a conversation saved with --save")
 }
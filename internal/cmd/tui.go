@@ -0,0 +1,261 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"golang.org/x/term"
+)
+
+// tuiPhase is what the full-screen review is showing.
+type tuiPhase int
+
+const (
+	tuiQuestion tuiPhase = iota // the question, with the answer hidden
+	tuiAnswer                   // the answer, waiting for a rating
+	tuiDone                     // nothing left to review, or the user quit
+)
+
+// tuiEffect is the work a key press asks of the session. The model only records
+// state; the session does the slow parts (the LLM, the database) and redraws.
+type tuiEffect int
+
+const (
+	tuiNone   tuiEffect = iota
+	tuiReveal           // fetch and show the answer
+	tuiRate             // save the model's rating and load the next card
+	tuiQuit             // leave the session
+)
+
+// Control keys that end a TUI review, since raw mode delivers them as bytes.
+const (
+	keyCtrlC = 3
+	keyCtrlD = 4
+)
+
+// tuiModel is the state of a full-screen review, kept free of I/O so the key
+// handling can be reasoned about on its own.
+type tuiModel struct {
+	phase    tuiPhase
+	title    string
+	question string
+	answer   string
+	rating   int    // set when a key rates the card
+	reviewed int    // cards rated this session
+	status   string // a one-line message, such as why the session ended
+}
+
+// update applies a key press and returns the new model with the effect it asks for.
+// Space or Enter reveals the answer; 1/2/3 (or a/g/e) rate it; q quits.
+func (m tuiModel) update(key byte) (tuiModel, tuiEffect) {
+	if key == 'q' || key == 'Q' || key == keyCtrlC || key == keyCtrlD {
+		m.phase = tuiDone
+		return m, tuiQuit
+	}
+	switch m.phase {
+	case tuiQuestion:
+		if key == ' ' || key == '\r' || key == '\n' {
+			m.phase = tuiAnswer
+			return m, tuiReveal
+		}
+	case tuiAnswer:
+		if rating, ok := ratingShortcuts[strings.ToLower(string(key))]; ok {
+			m.rating = rating
+			m.reviewed++
+			return m, tuiRate
+		}
+	}
+	return m, tuiNone
+}
+
+// show puts a new card on screen, question first.
+func (m tuiModel) show(title, question, answer string) tuiModel {
+	m.phase = tuiQuestion
+	m.title, m.question, m.answer = title, question, answer
+	m.rating = 0
+	m.status = ""
+	return m
+}
+
+// warn adds a message to the status line, above any already there.
+func (m tuiModel) warn(message string) tuiModel {
+	if m.status != "" {
+		message += "\n" + m.status
+	}
+	m.status = message
+	return m
+}
+
+// finish ends the session with a closing message.
+func (m tuiModel) finish(status string) tuiModel {
+	m.phase = tuiDone
+	m.status = status
+	return m
+}
+
+// view renders the model as plain text lines.
+func (m tuiModel) view() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "🧠 Neuron review — %d rated\n", m.reviewed)
+	b.WriteString(strings.Repeat("─", 59) + "\n\n")
+	if m.phase == tuiDone {
+		b.WriteString(m.status + "\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "📝 %s\n\n🤔 %s\n\n", m.title, m.question)
+	switch m.phase {
+	case tuiQuestion:
+		b.WriteString("[space] reveal   [q] quit\n")
+	case tuiAnswer:
+		fmt.Fprintf(&b, "💡 %s\n\n", m.answer)
+		b.WriteString("[1] Again   [2] Good   [3] Easy   [q] quit\n")
+	}
+	if m.status != "" {
+		b.WriteString("\n" + m.status + "\n")
+	}
+	return b.String()
+}
+
+// tuiSession runs the full-screen review: it feeds keys to the model and carries
+// out the effects they ask for.
+type tuiSession struct {
+	database *sql.DB
+	qType    study.QuestionType
+	length   study.AnswerLength
+	order    db.ReviewOrder
+	limits   *dailyLimits
+	useCache bool
+
+	model    tuiModel
+	note     *note.Note
+	hasReply bool // the answer is already known (card, cache, or offline)
+	started  time.Time
+	think    time.Duration // from started until the answer was revealed
+}
+
+// runTUIReview reviews due notes one after another in a full-screen view until
+// none are left, the daily limit is hit, or the user quits.
+func runTUIReview(database *sql.DB, qType study.QuestionType, length study.AnswerLength, order db.ReviewOrder, limits *dailyLimits, useCache bool) error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return fmt.Errorf("--tui needs an interactive terminal")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch the terminal to raw mode: %w", err)
+	}
+	defer term.Restore(fd, oldState)
+
+	s := &tuiSession{database: database, qType: qType, length: length, order: order, limits: limits, useCache: useCache}
+	if err := s.next(); err != nil {
+		return err
+	}
+	key := make([]byte, 1)
+	for s.model.phase != tuiDone {
+		s.draw()
+		if _, err := os.Stdin.Read(key); err != nil {
+			break
+		}
+		var effect tuiEffect
+		s.model, effect = s.model.update(key[0])
+		switch effect {
+		case tuiReveal:
+			s.think = time.Since(s.started)
+			s.reveal()
+		case tuiRate:
+			wasNew := study.IsNew(s.note)
+			headline, advice, err := rateNote(database, s.note, s.model.rating, s.think)
+			if err != nil {
+				return err
+			}
+			s.limits.record(database, wasNew)
+			if err := s.next(); err != nil {
+				return err
+			}
+			// Printing here would break the raw-mode screen, so the warning
+			// about the card just rated goes into the next one's status line.
+			if headline != "" {
+				s.model = s.model.warn(headline + "\n" + advice)
+			}
+		case tuiQuit:
+			s.model = s.model.finish("Session ended.")
+		}
+	}
+	s.draw()
+	return nil
+}
+
+// next loads the next due note and its question, or ends the session.
+func (s *tuiSession) next() error {
+	if s.limits.reviewsLeft() == 0 {
+		s.model = s.model.finish(fmt.Sprintf("🛑 Daily review limit reached (%d/%d).", s.limits.reviewsDone, s.limits.maxReviews))
+		return nil
+	}
+	dueNote, err := db.GetDueNoteOrdered(s.database, s.order, !s.limits.newAllowed())
+	if err == sql.ErrNoRows {
+		s.model = s.model.finish("🎉 No more notes are due. Great job!")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch note: %w", err)
+	}
+	s.note = dueNote
+
+	question, answer, known := "", "", false
+	if card, ok := pickCard(dueNote); ok {
+		question, answer, known = card.Front, card.Back, true
+	} else if s.useCache {
+		question, answer, known = cachedQuestionAnswer(s.database, dueNote, s.qType)
+	}
+	if !known && offline {
+		var fullNote bool
+		question, answer, fullNote = offlineQuestionAnswer(dueNote)
+		if fullNote {
+			answer = dueNote.Content
+		}
+		known = true
+	}
+	if !known {
+		s.model = s.model.show(dueNote.Title, "", "")
+		s.model.status = fmt.Sprintf("🧠 Generating %s question...", s.qType)
+		s.draw()
+		question, err = study.GenerateQuestion(dueNote, s.qType)
+		if err != nil {
+			return fmt.Errorf("failed to generate question: %w", err)
+		}
+	}
+	s.hasReply = known
+	s.model = s.model.show(dueNote.Title, question, answer)
+	s.started = time.Now()
+	return nil
+}
+
+// reveal fills in the answer, asking the model for it unless it is already known.
+func (s *tuiSession) reveal() {
+	if s.hasReply {
+		return
+	}
+	s.model.status = "🤖 Generating concise answer..."
+	s.draw()
+	answer, err := study.GenerateAnswer(s.model.question, s.note, s.length)
+	if err != nil {
+		answer = fmt.Sprintf("Could not generate an answer: %v", err)
+	} else if s.useCache {
+		storeQuestionAnswer(s.database, s.note, s.qType, s.model.question, answer)
+	}
+	s.model.answer = answer
+	s.model.status = ""
+	s.hasReply = true
+}
+
+// draw clears the screen and prints the model. Raw mode needs explicit carriage returns.
+func (s *tuiSession) draw() {
+	fmt.Print("\x1b[H\x1b[2J" + strings.ReplaceAll(s.model.view(), "\n", "\r\n"))
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+func TestTUIModelUpdate(t *testing.T) {
+	question := tuiModel{}.show("Title", "Q?", "A.")
+	answer := question
+	answer.phase = tuiAnswer
+
+	tests := []struct {
+		name     string
+		model    tuiModel
+		key      byte
+		phase    tuiPhase
+		effect   tuiEffect
+		rating   int
+		reviewed int
+	}{
+		{"space reveals", question, ' ', tuiAnswer, tuiReveal, 0, 0},
+		{"enter reveals", question, '\r', tuiAnswer, tuiReveal, 0, 0},
+		{"ratings wait for the answer", question, '2', tuiQuestion, tuiNone, 0, 0},
+		{"other keys do nothing", question, 'x', tuiQuestion, tuiNone, 0, 0},
+		{"1 rates Again", answer, '1', tuiAnswer, tuiRate, study.RatingAgain, 1},
+		{"g rates Good", answer, 'g', tuiAnswer, tuiRate, study.RatingGood, 1},
+		{"E rates Easy", answer, 'E', tuiAnswer, tuiRate, study.RatingEasy, 1},
+		{"space doesn't rate", answer, ' ', tuiAnswer, tuiNone, 0, 0},
+		{"q quits from the question", question, 'q', tuiDone, tuiQuit, 0, 0},
+		{"q quits from the answer", answer, 'q', tuiDone, tuiQuit, 0, 0},
+		{"Ctrl-C quits", answer, keyCtrlC, tuiDone, tuiQuit, 0, 0},
+		{"Ctrl-D quits", question, keyCtrlD, tuiDone, tuiQuit, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, effect := tt.model.update(tt.key)
+			if m.phase != tt.phase || effect != tt.effect || m.rating != tt.rating || m.reviewed != tt.reviewed {
+				t.Errorf("phase %d, effect %d, rating %d, reviewed %d; want %d, %d, %d, %d",
+					m.phase, effect, m.rating, m.reviewed, tt.phase, tt.effect, tt.rating, tt.reviewed)
+			}
+		})
+	}
+}
+
+func TestTUIModelSession(t *testing.T) {
+	m := tuiModel{}.show("First", "Q1?", "A1.")
+	if strings.Contains(m.view(), "A1.") {
+		t.Error("the answer is shown before it is revealed")
+	}
+
+	m, _ = m.update(' ')
+	if !strings.Contains(m.view(), "A1.") {
+		t.Error("the answer is hidden after it is revealed")
+	}
+	m, effect := m.update('2')
+	if effect != tuiRate {
+		t.Fatalf("rating gave effect %d, want tuiRate", effect)
+	}
+
+	m = m.show("Second", "Q2?", "A2.")
+	if m.phase != tuiQuestion || m.rating != 0 || m.reviewed != 1 {
+		t.Errorf("next card: phase %d, rating %d, reviewed %d; want question, 0, 1", m.phase, m.rating, m.reviewed)
+	}
+	view := m.view()
+	if !strings.Contains(view, "Q2?") || strings.Contains(view, "A2.") || !strings.Contains(view, "1 rated") {
+		t.Errorf("next card view:\n%s", view)
+	}
+
+	m, effect = m.update('q')
+	m = m.finish("Session ended.")
+	if effect != tuiQuit || !strings.Contains(m.view(), "Session ended.") {
+		t.Errorf("quit: effect %d, view:\n%s", effect, m.view())
+	}
+}
+
+func TestTUIModelWarn(t *testing.T) {
+	m := tuiModel{}.show("Title", "Q?", "A.").warn("🩸 leech")
+	if !strings.Contains(m.view(), "🩸 leech") {
+		t.Errorf("warning missing from view:\n%s", m.view())
+	}
+	m = tuiModel{}.finish("Done.").warn("🩸 leech")
+	if m.status != "🩸 leech\nDone." {
+		t.Errorf("status = %q, want the warning above the closing message", m.status)
+	}
+}
+
+func TestRateNoteReturnsLeechWarning(t *testing.T) {
+	database := testDB(t)
+	scheduler := study.CurrentSchedulerConfig()
+	t.Cleanup(func() { study.SetSchedulerConfig(scheduler) })
+	c := study.DefaultSchedulerConfig()
+	c.LeechThreshold = 1
+	study.SetSchedulerConfig(c)
+
+	n := addTestNote(t, database, "/notes/leech.md", "# Leech\nbody")
+	n.Interval = 10
+	n.State = note.StateReview
+	headline, advice, err := rateNote(database, n, study.RatingAgain, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(headline, "now tagged 'leech'") || advice == "" {
+		t.Errorf("headline %q, advice %q; want a new leech warning", headline, advice)
+	}
+
+	headline, _, err = rateNote(database, n, study.RatingGood, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headline != "" {
+		t.Errorf("rating Good warned %q, want no warning", headline)
+	}
+}
@@ -0,0 +1,126 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+// tuneSimulate is the rating sequence tune --simulate feeds through the scheduler.
+var tuneSimulate string
+
+// ratingLabels names the recall ratings.
+var ratingLabels = map[int]string{
+	study.RatingAgain: "Again",
+	study.RatingGood:  "Good",
+	study.RatingEasy:  "Easy",
+}
+
+var tuneCmd = &cobra.Command{
+	Use:   "tune",
+	Short: "Show the scheduler's parameters and simulate rating sequences",
+	Long: `Prints the spaced repetition parameters in effect, after config.yaml and
+flags such as --target-retention are applied: the ease bounds, the Again penalty
+and Easy bonus, how intervals grow, learning steps and so on.
+
+Use --simulate to see how a sequence of ratings would schedule a new note, for
+example --simulate "2 2 1 3 2" (or "g g a e g"). Each rating is applied as if
+the review happened on time, and the interval, ease factor and next review are
+printed after every step. No note is changed, so it is a safe way to try out
+again_penalty, easy_bonus and the other settings before reviewing with them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var ratings []int
+		if tuneSimulate != "" {
+			var err error
+			if ratings, err = parseRatings(tuneSimulate); err != nil {
+				return err
+			}
+		}
+
+		printSchedulerConfig(study.CurrentSchedulerConfig())
+		if ratings != nil {
+			printSimulation(study.SimulateReviews(ratings, note.DefaultEaseFactor), note.DefaultEaseFactor)
+		}
+		return nil
+	},
+}
+
+// parseRatings reads a rating sequence separated by spaces or commas, where each
+// rating is written as review accepts it: 1-3, a/g/e, or again/good/easy.
+func parseRatings(s string) ([]int, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--simulate needs at least one rating, e.g. \"2 2 1 3 2\"")
+	}
+	ratings := make([]int, 0, len(fields))
+	for _, field := range fields {
+		rating, ok := ratingShortcuts[strings.ToLower(field)]
+		if !ok {
+			return nil, fmt.Errorf("invalid rating %q in --simulate: use 1, 2, 3 or a, g, e", field)
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
+
+// printSchedulerConfig lists the scheduler's parameters.
+func printSchedulerConfig(c study.SchedulerConfig) {
+	fmt.Println("--- Scheduler ---")
+	row := func(label, value string) { fmt.Printf("  %-18s %s\n", label, value) }
+	row("Ease factor", fmt.Sprintf("%.2f to %.2f, new notes start at %.2f", c.EaseFloor, c.EaseCeiling, note.DefaultEaseFactor))
+	if len(c.TagEase) > 0 {
+		row("Tag ease", formatPairs(c.TagEase))
+	}
+	row("Again penalty", fmt.Sprintf("-%.2f ease", c.AgainPenalty))
+	row("Easy bonus", fmt.Sprintf("+%.2f ease", c.EasyBonus))
+	row("Initial interval", fmt.Sprintf("%g day(s)", note.DefaultInterval))
+	row("Interval growth", fmt.Sprintf("×%g below %g days, then × the ease factor", study.YoungIntervalMultiplier, study.MatureInterval))
+	row("Target retention", fmt.Sprintf("%.2f", c.TargetRetention))
+
+	steps := "none, new notes graduate on their first review"
+	if len(c.LearningSteps) > 0 {
+		labels := make([]string, len(c.LearningSteps))
+		for i, step := range c.LearningSteps {
+			labels[i] = formatWait(step)
+		}
+		steps = strings.Join(labels, ", ")
+	}
+	row("Learning steps", steps)
+	lapse := "1 day(s)"
+	if c.LapseInterval > 0 {
+		lapse = formatWait(c.LapseInterval)
+	}
+	row("Lapse interval", lapse)
+	leech := "off"
+	if c.LeechThreshold > 0 {
+		leech = fmt.Sprintf("after %d lapses", c.LeechThreshold)
+	}
+	row("Leech threshold", leech)
+	fuzz := "off"
+	if c.Fuzz {
+		fuzz = "on, so the next reviews below vary from run to run"
+	}
+	row("Fuzz", fuzz)
+}
+
+// printSimulation prints the schedule after each step of a simulated sequence.
+func printSimulation(steps []study.SimulatedReview, ease float64) {
+	fmt.Printf("\n--- Simulation: a new note starting at ease %.2f ---\n", ease)
+	fmt.Printf("  %2s  %-6s  %-8s  %8s  %5s  %s\n", "#", "Rating", "State", "Interval", "Ease", "Next review")
+	for i, step := range steps {
+		fmt.Printf("  %2d  %-6s  %-8s  %7.1fd  %5.2f  in %s\n",
+			i+1, ratingLabels[step.Rating], step.State, step.Interval, step.EaseFactor, formatWait(step.DueIn.Round(time.Second)))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(tuneCmd)
+	tuneCmd.Flags().StringVar(&tuneSimulate, "simulate", "", "Ratings to apply to a new note, e.g. \"2 2 1 3 2\", printing the schedule after each")
+}
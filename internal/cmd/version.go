@@ -0,0 +1,60 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/spf13/cobra"
+)
+
+// Build information, injected at build time with e.g.
+// go build -ldflags "-X github.com/soyomarvaldezg/neuron-cli/internal/cmd.version=v1.2.0 -X ...cmd.commit=abc1234 -X ...cmd.date=2026-01-31"
+var (
+	version = "dev"
+	commit  = ""
+	date    = ""
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version and build information",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Print(versionInfo())
+	},
+}
+
+// readBuildInfo returns what the Go toolchain recorded about this binary.
+// It is a variable so the fallback can be exercised without a real build.
+var readBuildInfo = debug.ReadBuildInfo
+
+// versionInfo describes this build. Without ldflags the version and commit fall back
+// to what the Go toolchain recorded, so `go install ...@v1.2.0` still reports v1.2.0.
+func versionInfo() string {
+	v, c, d := version, commit, date
+	if info, ok := readBuildInfo(); ok {
+		if v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && c == "" {
+				c = setting.Value
+			}
+		}
+	}
+	if c == "" {
+		c = "unknown"
+	}
+	if d == "" {
+		d = "unknown"
+	}
+	return fmt.Sprintf("neuron %s\n  commit:  %s\n  built:   %s\n  go:      %s\n  os/arch: %s/%s\n", v, c, d, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	rootCmd.Version = versionInfo()
+	rootCmd.SetVersionTemplate("{{.Version}}")
+}
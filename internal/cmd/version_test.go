@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+// useBuildInfo sets the ldflags variables and what the toolchain recorded for the rest of the test.
+func useBuildInfo(t *testing.T, v, c, d string, info *debug.BuildInfo) {
+	t.Helper()
+	oldVersion, oldCommit, oldDate, oldRead := version, commit, date, readBuildInfo
+	t.Cleanup(func() { version, commit, date, readBuildInfo = oldVersion, oldCommit, oldDate, oldRead })
+	version, commit, date = v, c, d
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, info != nil }
+}
+
+func TestVersionInfo(t *testing.T) {
+	installed := &debug.BuildInfo{
+		Main:     debug.Module{Version: "v1.2.0"},
+		Settings: []debug.BuildSetting{{Key: "vcs.time", Value: "2026-01-30"}, {Key: "vcs.revision", Value: "feedface"}},
+	}
+	tests := []struct {
+		name                  string
+		version, commit, date string
+		info                  *debug.BuildInfo
+		want                  []string
+	}{
+		{
+			name:    "ldflags win over the build info",
+			version: "v2.0.0", commit: "abc1234", date: "2026-01-31",
+			info: installed,
+			want: []string{"neuron v2.0.0\n", "commit:  abc1234\n", "built:   2026-01-31\n"},
+		},
+		{
+			name:    "go install falls back to the module version and revision",
+			version: "dev",
+			info:    installed,
+			want:    []string{"neuron v1.2.0\n", "commit:  feedface\n", "built:   unknown\n"},
+		},
+		{
+			name:    "a local build stays dev",
+			version: "dev",
+			info:    &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}},
+			want:    []string{"neuron dev\n", "commit:  unknown\n", "built:   unknown\n"},
+		},
+		{
+			name:    "no build info",
+			version: "dev",
+			want:    []string{"neuron dev\n", "commit:  unknown\n", "built:   unknown\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useBuildInfo(t, tt.version, tt.commit, tt.date, tt.info)
+			got := versionInfo()
+			for _, want := range append(tt.want, "go:      "+runtime.Version()+"\n", "os/arch: "+runtime.GOOS+"/"+runtime.GOARCH+"\n") {
+				if !strings.Contains(got, want) {
+					t.Errorf("versionInfo() is missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestVersionLdflags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the binary")
+	}
+	const pkg = "github.com/soyomarvaldezg/neuron-cli/internal/cmd"
+	binary := filepath.Join(t.TempDir(), "neuron")
+	ldflags := "-X " + pkg + ".version=v9.9.9 -X " + pkg + ".commit=0ddba11 -X " + pkg + ".date=2026-02-01"
+	build := exec.Command("go", "build", "-o", binary, "-ldflags", ldflags, "github.com/soyomarvaldezg/neuron-cli")
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, output)
+	}
+
+	for _, args := range [][]string{{"version"}, {"--version"}} {
+		output, err := exec.Command(binary, args...).Output()
+		if err != nil {
+			t.Fatalf("neuron %v: %v", args, err)
+		}
+		for _, want := range []string{"neuron v9.9.9\n", "commit:  0ddba11\n", "built:   2026-02-01\n"} {
+			if !strings.Contains(string(output), want) {
+				t.Errorf("neuron %v is missing %q:\n%s", args, want, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+// watchDebounce is how long import --watch waits after the last file event
+// before syncing, so an editor's burst of writes, renames and temporary files
+// for one save is imported once.
+const watchDebounce = 500 * time.Millisecond
+
+// fileStamp is what import --watch compares to tell that a file changed, taken
+// from the directory walk so unchanged files are never opened.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// snapshotNotes stamps every note file under root.
+func snapshotNotes(root string, extensions map[string]bool, ignore *importIgnore) (map[string]fileStamp, error) {
+	files := make(map[string]fileStamp)
+	err := walkNoteFiles(root, extensions, ignore, func(path string, info os.FileInfo) {
+		files[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	})
+	return files, err
+}
+
+// diffSnapshots returns the files that are new or changed in current, and the
+// ones that are gone from it.
+func diffSnapshots(previous, current map[string]fileStamp) (changed, deleted []string) {
+	for path, stamp := range current {
+		if old, ok := previous[path]; !ok || old != stamp {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			deleted = append(deleted, path)
+		}
+	}
+	sort.Strings(changed)
+	sort.Strings(deleted)
+	return changed, deleted
+}
+
+// watchNotes syncs note files under root as they are added, edited, or deleted,
+// until ctx is cancelled. File events only say that something changed; once they
+// have been quiet for debounce, the folder's modification times and sizes are
+// compared with the last scan to find which notes to sync.
+func watchNotes(ctx context.Context, database *sql.DB, root string, extensions map[string]bool, ignore *importIgnore, warnSizeKB int, debounce time.Duration) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("cannot watch %s: %w", root, err)
+	}
+	defer watcher.Close()
+	if err := watchDirs(watcher, root, ignore); err != nil {
+		return fmt.Errorf("cannot watch %s: %w", root, err)
+	}
+	previous, err := snapshotNotes(root, extensions, ignore)
+	if err != nil {
+		return fmt.Errorf("error walking the path %q: %w", root, err)
+	}
+	fmt.Printf("\n👀 Watching %s for changes. Press Ctrl-C to stop.\n", root)
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nStopped watching.")
+			return nil
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Error watching %s: %v", root, err)
+			continue
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			timer.Reset(debounce)
+			continue
+		case <-timer.C:
+		}
+
+		// Folders created since the last scan need watching too.
+		if err := watchDirs(watcher, root, ignore); err != nil {
+			log.Printf("Error watching %s: %v", root, err)
+		}
+		current, err := snapshotNotes(root, extensions, ignore)
+		if err != nil {
+			log.Printf("Error scanning %s: %v", root, err)
+			continue
+		}
+		if len(current) == 0 && len(previous) > 0 {
+			// Every file vanishing at once is an unmounted drive or a moved
+			// folder far more often than a deliberate deletion.
+			log.Printf("No note files found in %s; not removing anything until they're back.", root)
+			continue
+		}
+		changed, deleted := diffSnapshots(previous, current)
+		previous = current
+		if len(changed) > 0 || len(deleted) > 0 {
+			syncWatchedFiles(database, changed, deleted, warnSizeKB)
+		}
+	}
+}
+
+// watchDirs adds root and every folder under it that .neuronignore doesn't skip
+// to watcher, since file events aren't reported for subfolders. Folders already
+// being watched are left as they are.
+func watchDirs(watcher *fsnotify.Watcher, root string, ignore *importIgnore) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if rel, relErr := filepath.Rel(root, path); relErr == nil && rel != "." && ignore.skip(filepath.ToSlash(rel), true) {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// syncWatchedFiles imports the changed files and removes the deleted ones.
+func syncWatchedFiles(database *sql.DB, changed, deleted []string, warnSizeKB int) {
+	started := time.Now()
+	var notes []*note.Note
+	for _, result := range parseNotes(changed, importWorkers) {
+		if result.err != nil {
+			log.Printf("Error parsing %s: %v. Skipping.", result.path, result.err)
+			continue
+		}
+		result.note.AddTags(importAddTags...)
+		result.note.EaseFactor = study.InitialEase(result.note.Tags)
+		warnOversized(result.note, warnSizeKB)
+		notes = append(notes, result.note)
+	}
+	if len(notes) > 0 {
+		syncResults, err := db.InsertNotesTx(database, notes)
+		if err != nil {
+			log.Printf("Error syncing changed notes: %v", err)
+		}
+		for i, syncResult := range syncResults {
+			switch syncResult {
+			case db.SyncAdded:
+				fmt.Printf("✓ Added: %s\n", notes[i].Title)
+			case db.SyncUpdated:
+				fmt.Printf("✓ Updated: %s\n", notes[i].Title)
+			}
+		}
+	}
+	if len(deleted) > 0 {
+		deleteNotes(database, deleted)
+	}
+	if err := db.SetLastImport(database, started); err != nil {
+		log.Printf("Error recording the import time: %v", err)
+	}
+}
+
+// sortedKeys returns the keys of set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	now := time.Now()
+	previous := map[string]fileStamp{
+		"same.md":    {modTime: now, size: 10},
+		"touched.md": {modTime: now, size: 10},
+		"grown.md":   {modTime: now, size: 10},
+		"gone.md":    {modTime: now, size: 10},
+	}
+	current := map[string]fileStamp{
+		"same.md":    {modTime: now, size: 10},
+		"touched.md": {modTime: now.Add(time.Second), size: 10},
+		"grown.md":   {modTime: now, size: 20},
+		"new.md":     {modTime: now, size: 5},
+	}
+	changed, deleted := diffSnapshots(previous, current)
+	if want := []string{"grown.md", "new.md", "touched.md"}; !slices.Equal(changed, want) {
+		t.Errorf("changed = %q, want %q", changed, want)
+	}
+	if want := []string{"gone.md"}; !slices.Equal(deleted, want) {
+		t.Errorf("deleted = %q, want %q", deleted, want)
+	}
+}
+
+func TestWatchNotesSyncsChanges(t *testing.T) {
+	database := testDB(t)
+	dir, err := canonicalPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept := writeNoteFile(t, dir, "kept.md", "# Kept\nbody")
+	gone := writeNoteFile(t, dir, "gone.md", "# Gone\nbody")
+	if err := runImport(t, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := loadImportIgnore(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watchNotes(ctx, database, dir, parseExtensions("md"), ignore, defaultWarnSizeKB, 10*time.Millisecond)
+	}()
+	// Let the watcher take its first snapshot before changing anything.
+	time.Sleep(50 * time.Millisecond)
+	added := writeNoteFile(t, dir, "added.md", "# Added\nbody")
+	if err := os.Remove(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		filenames := storedFilenames(t)
+		if filenames[added] && !filenames[gone] {
+			if !filenames[kept] {
+				t.Errorf("the watcher removed %s", kept)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the watcher didn't sync the changes: stored %v", filenames)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchNotesWatchesNewFolders(t *testing.T) {
+	database := testDB(t)
+	dir, err := canonicalPath(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := loadImportIgnore(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watchNotes(ctx, database, dir, parseExtensions("md"), ignore, defaultWarnSizeKB, 10*time.Millisecond)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// Give the watcher time to pick up the new folder, then write into it.
+	time.Sleep(100 * time.Millisecond)
+	nested := writeNoteFile(t, dir, "sub/nested.md", "# Nested\nbody")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !storedFilenames(t)[nested] {
+		if time.Now().After(deadline) {
+			t.Fatalf("the watcher didn't import %s", nested)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
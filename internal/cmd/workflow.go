@@ -4,7 +4,9 @@ package cmd
 import (
 	"bufio"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -17,6 +19,7 @@ import (
 
 func init() {
 	rootCmd.AddCommand(workflowCmd)
+	addIndexFlag(workflowCmd)
 
 	// Define the flags for workflow command
 	workflowCmd.Flags().StringP("phase", "p", "foundational", "Phase of the workflow to run (foundational, verification, extension)")
@@ -32,7 +35,8 @@ Phase 2: Metacognitive Verification
 Phase 3: Use AI to Extend
 
 Each phase provides specific activities to optimize learning.`,
-	Args: cobra.ExactArgs(1),
+	Args:        cobra.ExactArgs(1),
+	Annotations: usesLLM,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		topic := args[0]
 
@@ -41,12 +45,8 @@ Each phase provides specific activities to optimize learning.`,
 			return err
 		}
 
-		noteToWorkflow, err := db.GetNoteByTitleOrFilename(database, topic)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				fmt.Printf("Sorry, I couldn't find a note matching '%s'.\n", topic)
-				return nil
-			}
+		noteToWorkflow, err := lookupNote(database, topic)
+		if err != nil || noteToWorkflow == nil {
 			return err
 		}
 
@@ -79,20 +79,27 @@ Each phase provides specific activities to optimize learning.`,
 
 		// Show available commands at start
 		helpColor := color.New(color.FgGreen)
-		helpColor.Println("\n💡 Tip: Type 'help' anytime to see available commands\n")
+		helpColor.Print("\n💡 Tip: Type 'help' anytime to see available commands\n\n")
 
 		// Run the appropriate phase
 		switch strings.ToLower(phase) {
 		case "foundational":
-			return runFoundationalPhase(reader, noteToWorkflow, qType, database)
+			err = runFoundationalPhase(reader, noteToWorkflow, qType, database)
 		case "verification", "metacognitive":
-			return runVerificationPhase(reader, noteToWorkflow, qType, database)
+			err = runVerificationPhase(reader, noteToWorkflow, qType, database)
 		case "extension", "ai":
-			return runExtensionPhase(reader, noteToWorkflow, qType, database)
+			err = runExtensionPhase(reader, noteToWorkflow, qType, database)
 		default:
 			fmt.Printf("Unknown phase: %s. Valid phases are: foundational, verification, extension\n", phase)
 			return nil
 		}
+
+		// The phases return io.EOF when input runs out mid-session.
+		if errors.Is(err, io.EOF) {
+			fmt.Println("\nInput closed; ending the workflow session.")
+			return nil
+		}
+		return err
 	},
 }
 
@@ -114,7 +121,10 @@ func runFoundationalPhase(reader *bufio.Reader, note *note.Note, qType string, d
 		fmt.Println("  7. Exit phase")
 
 		fmt.Print("\nChoose an option (1-7): ")
-		choice, _ := reader.ReadString('\n')
+		choice, err := readLine(reader)
+		if err != nil {
+			return err
+		}
 		choice = strings.TrimSpace(choice)
 
 		switch choice {
@@ -129,10 +139,12 @@ func runFoundationalPhase(reader *bufio.Reader, note *note.Note, qType string, d
 			questionColor.Printf("\n🤔 Question: %s\n", question)
 
 			fmt.Print("\nPress Enter to see answer...")
-			_, _ = reader.ReadString('\n')
+			if _, err := readLine(reader); err != nil {
+				return err
+			}
 
 			fmt.Println("\n🤖 Generating answer...")
-			answer, err := study.GenerateAnswer(question, note)
+			answer, err := study.GenerateAnswer(question, note, study.AnswerMedium)
 			if err != nil {
 				return fmt.Errorf("failed to generate answer: %w", err)
 			}
@@ -158,12 +170,7 @@ func runFoundationalPhase(reader *bufio.Reader, note *note.Note, qType string, d
 		case "5":
 			fmt.Println("\n📖 Full Note Content:")
 			fmt.Println("-----------------------------------------------------------")
-			rendered, err := renderMarkdown(note.Content)
-			if err != nil {
-				fmt.Println(note.Content)
-			} else {
-				fmt.Println(rendered)
-			}
+			printMarkdown(note.Content)
 			fmt.Println("-----------------------------------------------------------")
 
 		case "6":
@@ -205,7 +212,10 @@ func runVerificationPhase(reader *bufio.Reader, note *note.Note, qType string, d
 		fmt.Println("  8. Exit phase")
 
 		fmt.Print("\nChoose an option (1-8): ")
-		choice, _ := reader.ReadString('\n')
+		choice, err := readLine(reader)
+		if err != nil {
+			return err
+		}
 		choice = strings.TrimSpace(choice)
 
 		switch choice {
@@ -232,10 +242,12 @@ func runVerificationPhase(reader *bufio.Reader, note *note.Note, qType string, d
 			questionColor.Printf("\n🤔 Question: %s\n", question)
 
 			fmt.Print("\nPress Enter to see answer...")
-			_, _ = reader.ReadString('\n')
+			if _, err := readLine(reader); err != nil {
+				return err
+			}
 
 			fmt.Println("\n🤖 Generating answer...")
-			answer, err := study.GenerateAnswer(question, note)
+			answer, err := study.GenerateAnswer(question, note, study.AnswerMedium)
 			if err != nil {
 				return fmt.Errorf("failed to generate answer: %w", err)
 			}
@@ -249,12 +261,7 @@ func runVerificationPhase(reader *bufio.Reader, note *note.Note, qType string, d
 		case "6":
 			fmt.Println("\n📖 Full Note Content:")
 			fmt.Println("-----------------------------------------------------------")
-			rendered, err := renderMarkdown(note.Content)
-			if err != nil {
-				fmt.Println(note.Content)
-			} else {
-				fmt.Println(rendered)
-			}
+			printMarkdown(note.Content)
 			fmt.Println("-----------------------------------------------------------")
 
 		case "7":
@@ -299,7 +306,10 @@ func runExtensionPhase(reader *bufio.Reader, note *note.Note, qType string, data
 		fmt.Println("  8. Exit phase")
 
 		fmt.Print("\nChoose an option (1-8): ")
-		choice, _ := reader.ReadString('\n')
+		choice, err := readLine(reader)
+		if err != nil {
+			return err
+		}
 		choice = strings.TrimSpace(choice)
 
 		switch choice {
@@ -326,10 +336,12 @@ func runExtensionPhase(reader *bufio.Reader, note *note.Note, qType string, data
 			questionColor.Printf("\n🤔 Question: %s\n", question)
 
 			fmt.Print("\nPress Enter to see answer...")
-			_, _ = reader.ReadString('\n')
+			if _, err := readLine(reader); err != nil {
+				return err
+			}
 
 			fmt.Println("\n🤖 Generating answer...")
-			answer, err := study.GenerateAnswer(question, note)
+			answer, err := study.GenerateAnswer(question, note, study.AnswerMedium)
 			if err != nil {
 				return fmt.Errorf("failed to generate answer: %w", err)
 			}
@@ -343,12 +355,7 @@ func runExtensionPhase(reader *bufio.Reader, note *note.Note, qType string, data
 		case "6":
 			fmt.Println("\n📖 Full Note Content:")
 			fmt.Println("-----------------------------------------------------------")
-			rendered, err := renderMarkdown(note.Content)
-			if err != nil {
-				fmt.Println(note.Content)
-			} else {
-				fmt.Println(rendered)
-			}
+			printMarkdown(note.Content)
 			fmt.Println("-----------------------------------------------------------")
 
 		case "7":
@@ -382,7 +389,10 @@ func runCollaborativeExploration(reader *bufio.Reader, note *note.Note) error {
 	fmt.Println("Share your approach, and I'll help you explore alternatives and improvements.")
 
 	fmt.Print("\nDescribe your current approach or solution: ")
-	approach, _ := reader.ReadString('\n')
+	approach, err := readLine(reader)
+	if err != nil {
+		return err
+	}
 	approach = strings.TrimSpace(approach)
 
 	if approach == "" {
@@ -458,7 +468,10 @@ func runSolutionOptimization(reader *bufio.Reader, note *note.Note) error {
 	fmt.Println("Share your current solution, and I'll suggest improvements.")
 
 	fmt.Print("\nDescribe your current solution: ")
-	solution, _ := reader.ReadString('\n')
+	solution, err := readLine(reader)
+	if err != nil {
+		return err
+	}
 	solution = strings.TrimSpace(solution)
 
 	if solution == "" {
@@ -532,24 +545,33 @@ func runSelfTestMode(reader *bufio.Reader, note *note.Note, qType string, databa
 	fmt.Printf("\n🧠 Self-Testing with %s questions...\n", qType)
 
 	questionCount := 0
+	var question string
+	reuseQuestion := false // set after a hint so the same question is asked again
 	for {
-		questionCount++
+		if !reuseQuestion {
+			questionCount++
 
-		// Generate question with variation hint
-		fmt.Printf("🧠 Generating %s question (#%d)...\n", qType, questionCount)
+			// Generate question with variation hint
+			fmt.Printf("🧠 Generating %s question (#%d)...\n", qType, questionCount)
 
-		// Add a small random element to prompt to force variation
-		question, err := study.GenerateQuestionWithVariation(note, study.QuestionType(qType), questionCount)
-		if err != nil {
-			return fmt.Errorf("failed to generate question: %w", err)
+			// Add a small random element to prompt to force variation
+			var err error
+			question, err = study.GenerateQuestionWithVariation(note, study.QuestionType(qType), questionCount)
+			if err != nil {
+				return fmt.Errorf("failed to generate question: %w", err)
+			}
 		}
+		reuseQuestion = false
 
 		questionColor := color.New(color.FgCyan)
 		questionColor.Printf("\n🤔 Question: %s\n", question)
 
 		// Check for special commands
 		fmt.Print("\nType your answer (or 'help' for commands): ")
-		userInput, _ := reader.ReadString('\n')
+		userInput, err := readLine(reader)
+		if err != nil {
+			return err
+		}
 		userInput = strings.TrimSpace(userInput)
 
 		// Check for special commands
@@ -558,6 +580,7 @@ func runSelfTestMode(reader *bufio.Reader, note *note.Note, qType string, databa
 			helpColor.Println("\n🛠️  Available Commands:")
 			fmt.Println("  • 'help' or '?' - Show this help message")
 			fmt.Println("  • 'note' or 'show note' - Display the full note content")
+			fmt.Println("  • 'hint' - Get a short hint for this question")
 			fmt.Println("  • 'skip' - Skip this question")
 			fmt.Println("  • 'quit' or 'exit' - End self-test and return to menu")
 			fmt.Println("  • Type your answer to test your knowledge")
@@ -573,13 +596,21 @@ func runSelfTestMode(reader *bufio.Reader, note *note.Note, qType string, databa
 		if strings.ToLower(userInput) == "note" || strings.ToLower(userInput) == "show note" {
 			fmt.Println("\n📖 Full Note Content:")
 			fmt.Println("-----------------------------------------------------------")
-			rendered, err := renderMarkdown(note.Content)
+			printMarkdown(note.Content)
+			fmt.Println("-----------------------------------------------------------")
+			continue
+		}
+
+		if strings.ToLower(userInput) == "hint" {
+			fmt.Println("\n🔎 Generating hint...")
+			hint, err := study.GenerateHint(question, note)
 			if err != nil {
-				fmt.Println(note.Content)
+				fmt.Printf("Could not generate a hint: %v\n", err)
 			} else {
-				fmt.Println(rendered)
+				hintColor := color.New(color.FgYellow)
+				hintColor.Printf("\n💭 Hint: %s\n", hint)
 			}
-			fmt.Println("-----------------------------------------------------------")
+			reuseQuestion = true
 			continue
 		}
 
@@ -596,7 +627,7 @@ func runSelfTestMode(reader *bufio.Reader, note *note.Note, qType string, databa
 
 		// Generate AI answer
 		fmt.Println("\n🤖 Generating AI answer for comparison...")
-		aiAnswer, err := study.GenerateAnswer(question, note)
+		aiAnswer, err := study.GenerateAnswer(question, note, study.AnswerMedium)
 		if err != nil {
 			return fmt.Errorf("failed to generate AI answer: %w", err)
 		}
@@ -630,7 +661,10 @@ func runSelfTestMode(reader *bufio.Reader, note *note.Note, qType string, databa
 
 		// Ask if user wants to continue
 		fmt.Print("\nContinue with another question? (y/n): ")
-		continueInput, _ := reader.ReadString('\n')
+		continueInput, err := readLine(reader)
+		if err != nil {
+			return err
+		}
 		continueInput = strings.TrimSpace(strings.ToLower(continueInput))
 
 		if continueInput == "n" || continueInput == "no" {
@@ -645,119 +679,5 @@ func runReflectionMode(reader *bufio.Reader, note *note.Note) error {
 	fmt.Println("\n🔍 Reflection Mode (Red Team Pattern)")
 	fmt.Println("I'll challenge your assumptions and explore edge cases.")
 
-	// First round: Get initial explanation
-	fmt.Print("\n📝 Explain the concept in your own words: ")
-	userExplanation, _ := reader.ReadString('\n')
-	userExplanation = strings.TrimSpace(userExplanation)
-
-	// Check for special commands
-	if strings.ToLower(userExplanation) == "help" || strings.ToLower(userExplanation) == "?" {
-		helpColor := color.New(color.FgGreen)
-		helpColor.Println("\n🛠️  Available Commands:")
-		fmt.Println("  • 'help' or '?' - Show this help message")
-		fmt.Println("  • 'note' or 'show note' - Display the full note content")
-		fmt.Println("  • 'quit' or 'exit' - End reflection and return to menu")
-		fmt.Println("  • Type your explanation to begin reflection")
-		fmt.Println()
-		return runReflectionMode(reader, note)
-	}
-
-	if strings.ToLower(userExplanation) == "quit" || strings.ToLower(userExplanation) == "exit" {
-		fmt.Println("Reflection completed. Returning to phase menu.")
-		return nil
-	}
-
-	if strings.ToLower(userExplanation) == "note" || strings.ToLower(userExplanation) == "show note" {
-		fmt.Println("\n📖 Full Note Content:")
-		fmt.Println("-----------------------------------------------------------")
-		rendered, err := renderMarkdown(note.Content)
-		if err != nil {
-			fmt.Println(note.Content)
-		} else {
-			fmt.Println(rendered)
-		}
-		fmt.Println("-----------------------------------------------------------")
-		return runReflectionMode(reader, note)
-	}
-
-	if userExplanation == "" {
-		fmt.Println("Please provide an explanation or type a command.")
-		return runReflectionMode(reader, note)
-	}
-
-	// Now we have the initial explanation, start the reflection loop
-	for {
-		// Generate reflection challenges based on current explanation
-		fmt.Println("\n🔍 Generating reflection challenges...")
-		challenges, err := study.GenerateReflectionChallenges(userExplanation, note.Content)
-		if err != nil {
-			return fmt.Errorf("failed to generate reflection challenges: %w", err)
-		}
-
-		// Display challenges
-		fmt.Println("\n" + strings.Repeat("=", 60))
-		fmt.Println("🎯 REFLECTION CHALLENGES")
-		fmt.Println(strings.Repeat("=", 60))
-
-		challengeColor := color.New(color.FgCyan)
-		fmt.Print("\n")
-		challengeColor.Println(challenges)
-
-		fmt.Println(strings.Repeat("=", 60))
-
-		// Ask if user wants to respond to challenges
-		fmt.Print("\n💭 Would you like to respond to these challenges? (y/n): ")
-		responseInput, _ := reader.ReadString('\n')
-		responseInput = strings.TrimSpace(strings.ToLower(responseInput))
-
-		if responseInput == "y" || responseInput == "yes" {
-			fmt.Println("\n💬 Share your thoughts on these challenges:")
-			userResponse, _ := reader.ReadString('\n')
-			userResponse = strings.TrimSpace(userResponse)
-
-			if userResponse != "" {
-				fmt.Println("\n🤝 Your response has been noted. This deeper reflection strengthens your understanding!")
-				// Update the explanation with the user's response for the next round
-				userExplanation = userExplanation + "\n\nReflection: " + userResponse
-			}
-		}
-
-		// Ask if user wants to continue with another reflection round
-		fmt.Print("\n🔄 Continue with another reflection round? (y/n): ")
-		continueInput, _ := reader.ReadString('\n')
-		continueInput = strings.TrimSpace(strings.ToLower(continueInput))
-
-		if continueInput == "n" || continueInput == "no" {
-			fmt.Println("Reflection completed. Returning to phase menu.")
-			return nil
-		}
-
-		// For subsequent rounds, ask if they want to refine their explanation or explore new aspects
-		fmt.Print("\n📝 Would you like to (1) refine your explanation or (2) explore new aspects? [1/2]: ")
-		choiceInput, _ := reader.ReadString('\n')
-		choiceInput = strings.TrimSpace(choiceInput)
-
-		if choiceInput == "1" {
-			fmt.Print("\n✏️ Refine your explanation based on the challenges: ")
-			refinedExplanation, _ := reader.ReadString('\n')
-			refinedExplanation = strings.TrimSpace(refinedExplanation)
-
-			if refinedExplanation != "" {
-				userExplanation = refinedExplanation
-				fmt.Println("✅ Your explanation has been updated for the next reflection round.")
-			}
-		} else if choiceInput == "2" {
-			fmt.Print("\n🔍 What aspect of the concept would you like to explore next? ")
-			newAspect, _ := reader.ReadString('\n')
-			newAspect = strings.TrimSpace(newAspect)
-
-			if newAspect != "" {
-				userExplanation = userExplanation + "\n\nNew aspect to explore: " + newAspect
-				fmt.Println("✅ New aspect added for the next reflection round.")
-			}
-		} else {
-			// Default to continuing with the current explanation
-			fmt.Println("✅ Continuing with your current explanation for the next reflection round.")
-		}
-	}
+	return runReflection(reader, note, "End reflection and return to menu", "Reflection completed. Returning to phase menu.")
 }
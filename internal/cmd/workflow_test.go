@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// runWorkflow runs a workflow phase on the note with input as stdin and returns its output.
+func runWorkflow(t *testing.T, phase, topic, input string) string {
+	t.Helper()
+	if err := workflowCmd.Flags().Set("phase", phase); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetFlags(workflowCmd) })
+	withStdin(t, input)
+	var err error
+	output := captureStdout(t, func() { err = workflowCmd.RunE(workflowCmd, []string{topic}) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+func TestWorkflowReflectionHelpReprompts(t *testing.T) {
+	database := testDB(t)
+	n := addTestNote(t, database, "/notes/reflect.md", "# Reflect\n\n## Summary\nThink hard.\n")
+	provider := useFakeProvider(t, "")
+	var prompts []string
+	provider.respond = func(prompt string) string {
+		prompts = append(prompts, prompt)
+		return "What breaks first?"
+	}
+
+	// Reflection mode, help, an explanation, no response, then stop.
+	output := runWorkflow(t, "verification", n.Title, "4\nhelp\nmy explanation\nx\nn\n")
+
+	if got := strings.Count(output, "PHASE 2: METACOGNITIVE VERIFICATION"); got != 1 {
+		t.Errorf("phase intro printed %d times, want 1:\n%s", got, output)
+	}
+	help, explain, found := strings.Cut(output, "Available Commands")
+	if !found {
+		t.Fatalf("help wasn't shown:\n%s", output)
+	}
+	if !strings.Contains(help, "Explain the concept in your own words") || !strings.Contains(explain, "Explain the concept in your own words") {
+		t.Errorf("the explanation prompt isn't shown both before and after help:\n%s", output)
+	}
+	if !strings.Contains(output, "Reflection completed. Returning to phase menu.") {
+		t.Errorf("reflection didn't end with the workflow's message:\n%s", output)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "USER'S EXPLANATION: my explanation") {
+		t.Errorf("prompts = %q, want one reflection prompt on the explanation", prompts)
+	}
+}
@@ -0,0 +1,134 @@
+// Package cmd implements the command line interface for Neuron CLI.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/db"
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+	"github.com/spf13/cobra"
+)
+
+var worksheetTag string
+var worksheetCount int
+var worksheetOut string
+var worksheetQuestionType string
+
+// worksheetItem is one question on a worksheet and its answer key entry.
+type worksheetItem struct {
+	Title    string
+	Question string
+	Answer   string
+}
+
+var worksheetCmd = &cobra.Command{
+	Use:   "worksheet",
+	Short: "Generate a printable Markdown worksheet with an answer key",
+	Long: `Generates one question per note and writes a Markdown worksheet with all
+questions first and an answer key at the bottom, ready to print or read offline.
+Notes come from the ones currently due, or from a tag with --tag.
+This does not change your review schedule.`,
+	Args:        cobra.NoArgs,
+	Annotations: usesLLM,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		database, err := db.GetDB()
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		var notes []*note.Note
+		if worksheetTag != "" {
+			notes, err = db.GetNotesByTag(database, worksheetTag, worksheetCount)
+		} else {
+			notes, err = db.GetDueNotes(database, worksheetCount)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch notes: %w", err)
+		}
+		if len(notes) == 0 {
+			if worksheetTag != "" {
+				fmt.Printf("No notes are tagged '%s'.\n", worksheetTag)
+			} else {
+				fmt.Println("🎉 No notes are due for review. Use --tag to build a worksheet from a topic.")
+			}
+			return nil
+		}
+
+		qType := study.QuestionType(worksheetQuestionType)
+		if qType == "" {
+			qType = study.QuestionTypeMixed
+		}
+
+		items, err := buildWorksheet(notes, qType)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = os.Stdout
+		if worksheetOut != "" {
+			file, err := os.Create(worksheetOut)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", worksheetOut, err)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := writeWorksheet(out, items, time.Now()); err != nil {
+			return fmt.Errorf("failed to write worksheet: %w", err)
+		}
+
+		if worksheetOut != "" {
+			fmt.Printf("✓ Wrote %d questions to %s\n", len(items), worksheetOut)
+		}
+		return nil
+	},
+}
+
+// buildWorksheet generates a question and answer for every note.
+// Progress goes to stderr so it never mixes with a worksheet written to stdout.
+func buildWorksheet(notes []*note.Note, qType study.QuestionType) ([]worksheetItem, error) {
+	items := make([]worksheetItem, 0, len(notes))
+	for i, n := range notes {
+		fmt.Fprintf(os.Stderr, "🧠 Generating %s question %d of %d: %s\n", qType, i+1, len(notes), n.Title)
+		question, err := study.GenerateQuestion(n, qType)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate question for %s: %w", n.Title, err)
+		}
+		answer, err := study.GenerateAnswer(question, n, study.AnswerMedium)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate answer for %s: %w", n.Title, err)
+		}
+		items = append(items, worksheetItem{Title: n.Title, Question: question, Answer: answer})
+	}
+	return items, nil
+}
+
+// writeWorksheet writes the questions as a numbered list, followed by an answer key
+// that uses the same numbering.
+func writeWorksheet(w io.Writer, items []worksheetItem, date time.Time) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Neuron Worksheet — %s\n\n", date.Format("2006-01-02"))
+	b.WriteString("## Questions\n\n")
+	for i, item := range items {
+		fmt.Fprintf(&b, "%d. %s\n   _(%s)_\n\n", i+1, item.Question, item.Title)
+	}
+	b.WriteString("---\n\n## Answer Key\n\n")
+	for i, item := range items {
+		fmt.Fprintf(&b, "%d. %s\n\n", i+1, strings.ReplaceAll(strings.TrimSpace(item.Answer), "\n", "\n   "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(worksheetCmd)
+	worksheetCmd.Flags().StringVar(&worksheetTag, "tag", "", "Use notes with this tag instead of the ones that are due")
+	worksheetCmd.Flags().IntVar(&worksheetCount, "count", 10, "Maximum number of questions")
+	worksheetCmd.Flags().StringVarP(&worksheetOut, "out", "o", "", "Write to this file instead of stdout")
+	worksheetCmd.Flags().StringVar(&worksheetQuestionType, "question-type", "mixed", "Type of question to generate: factual, conceptual, application, mixed")
+}
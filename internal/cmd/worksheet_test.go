@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+	"github.com/soyomarvaldezg/neuron-cli/internal/study"
+)
+
+// numberedItems counts the "N. " list items in a worksheet section.
+var numberedItems = regexp.MustCompile(`(?m)^\d+\. `)
+
+// worksheetSections splits a worksheet into its questions and its answer key.
+func worksheetSections(t *testing.T, worksheet string) (string, string) {
+	t.Helper()
+	questions, answers, found := strings.Cut(worksheet, "## Answer Key")
+	if !found {
+		t.Fatalf("worksheet has no answer key:\n%s", worksheet)
+	}
+	return questions, answers
+}
+
+func TestWriteWorksheet(t *testing.T) {
+	items := []worksheetItem{
+		{Title: "Maps", Question: "What backs a map?", Answer: "A hash table."},
+		{Title: "Slices", Question: "What is a slice?", Answer: "A view of an array.\nIt has a length and a capacity."},
+		{Title: "Channels", Question: "When does a send block?", Answer: "When nobody receives."},
+	}
+	var b strings.Builder
+	if err := writeWorksheet(&b, items, time.Date(2026, 3, 14, 9, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+	worksheet := b.String()
+	if !strings.HasPrefix(worksheet, "# Neuron Worksheet — 2026-03-14\n") {
+		t.Errorf("worksheet doesn't start with its dated title:\n%s", worksheet)
+	}
+	questions, answers := worksheetSections(t, worksheet)
+	if got := len(numberedItems.FindAllString(questions, -1)); got != 3 {
+		t.Errorf("%d questions, want 3:\n%s", got, questions)
+	}
+	if got := len(numberedItems.FindAllString(answers, -1)); got != 3 {
+		t.Errorf("%d answers, want 3:\n%s", got, answers)
+	}
+	for _, want := range []string{"2. What is a slice?\n   _(Slices)_"} {
+		if !strings.Contains(questions, want) {
+			t.Errorf("questions are missing %q:\n%s", want, questions)
+		}
+	}
+	// A multi-line answer stays inside its list item.
+	if !strings.Contains(answers, "2. A view of an array.\n   It has a length and a capacity.") {
+		t.Errorf("multi-line answer isn't indented under its number:\n%s", answers)
+	}
+}
+
+// numberReplies makes the fake provider answer questions and answers with
+// numbered replies, so each pair can be told apart.
+func numberReplies(p *fakeProvider) {
+	questions, answers := 0, 0
+	p.respond = func(prompt string) string {
+		if strings.Contains(prompt, "providing pedagogically effective answers") {
+			answers++
+			return fmt.Sprintf("Answer %d.", answers)
+		}
+		questions++
+		return fmt.Sprintf("Question %d?", questions)
+	}
+}
+
+func TestBuildWorksheet(t *testing.T) {
+	numberReplies(useFakeProvider(t, ""))
+	notes := []*note.Note{{Title: "A", Content: "a"}, {Title: "B", Content: "b"}, {Title: "C", Content: "c"}}
+	items, err := buildWorksheet(notes, study.QuestionTypeMixed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("%d items for 3 notes, want 3", len(items))
+	}
+	for i, item := range items {
+		want := worksheetItem{Title: notes[i].Title, Question: fmt.Sprintf("Question %d?", i+1), Answer: fmt.Sprintf("Answer %d.", i+1)}
+		if item != want {
+			t.Errorf("item %d = %+v, want %+v", i+1, item, want)
+		}
+	}
+}
+
+func TestWorksheetCommandWritesFile(t *testing.T) {
+	database := testDB(t)
+	for _, name := range []string{"maps", "slices", "channels", "untagged"} {
+		var tags []string
+		if name != "untagged" {
+			tags = []string{"go"}
+		}
+		addTestNote(t, database, "/notes/"+name+".md", "# "+name+"\n\nbody", tags...)
+	}
+	numberReplies(useFakeProvider(t, ""))
+	out := filepath.Join(t.TempDir(), "worksheet.md")
+	worksheetTag, worksheetOut = "go", out
+	t.Cleanup(func() { worksheetTag, worksheetOut = "", "" })
+
+	var err error
+	output := captureStdout(t, func() { err = worksheetCmd.RunE(worksheetCmd, nil) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "Wrote 3 questions to "+out) {
+		t.Errorf("output doesn't report the file:\n%s", output)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	questions, answers := worksheetSections(t, string(data))
+	if q, a := len(numberedItems.FindAllString(questions, -1)), len(numberedItems.FindAllString(answers, -1)); q != 3 || a != 3 {
+		t.Errorf("worksheet has %d questions and %d answers, want 3 of each:\n%s", q, a, data)
+	}
+	if strings.Contains(questions, "untagged") {
+		t.Errorf("worksheet for --tag go includes an untagged note:\n%s", data)
+	}
+}
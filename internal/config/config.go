@@ -0,0 +1,259 @@
+// Package config loads and saves user settings that persist between runs.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config holds the persisted settings. Zero values mean "use the built-in default".
+type Config struct {
+	TargetRetention float64 `yaml:"target_retention,omitempty"`
+
+	// LeechThreshold is the lapse count at which a note is tagged as a leech
+	// (default 8). An explicit 0 turns leech detection off.
+	LeechThreshold *int `yaml:"leech_threshold,omitempty"`
+
+	// Fuzz jitters long intervals by up to ±5% so notes learned together don't
+	// all come due on the same day (default off). --fuzz turns it on for one run.
+	Fuzz *bool `yaml:"fuzz,omitempty"`
+
+	// LearningSteps are the delays ("1m", "10m", "1d") a new note goes through before graduating.
+	LearningSteps []string `yaml:"learning_steps,omitempty"`
+
+	// LapseInterval is when a note rated "Again" comes back ("10m", "3d"; default 1 day).
+	LapseInterval string `yaml:"lapse_interval,omitempty"`
+
+	// AgainPenalty is subtracted from a note's ease factor when it is rated
+	// "Again" (default 0.2), and EasyBonus added on "Easy" (default 0.15).
+	AgainPenalty *float64 `yaml:"again_penalty,omitempty"`
+	EasyBonus    *float64 `yaml:"easy_bonus,omitempty"`
+
+	// TagEase sets the starting ease factor of new notes by tag, e.g. languages: 2.0
+	// (default 2.5). A lower ease means the intervals grow more slowly.
+	TagEase map[string]float64 `yaml:"tag_ease,omitempty"`
+
+	// Language is what generated questions, answers and chat replies are written
+	// in (default English). --lang overrides it for one run.
+	Language string `yaml:"language,omitempty"`
+
+	// ReflectionPersona replaces the devil's-advocate persona used by reflect.
+	ReflectionPersona string `yaml:"reflection_persona,omitempty"`
+
+	// Model, Temperature and Seed tune the LLM. --model, --temperature and --seed override them for one run.
+	Model       string   `yaml:"model,omitempty"`
+	Temperature *float64 `yaml:"temperature,omitempty"`
+	Seed        *int     `yaml:"seed,omitempty"`
+
+	// Models overrides Model for individual commands, keyed by command name,
+	// e.g. review: llama3.2:3b and deep-dive: llama3:70b.
+	Models map[string]string `yaml:"models,omitempty"`
+
+	// MaxConcurrency caps how many LLM requests run at once (default 1, right for a
+	// local Ollama). Raise it for hosted providers.
+	MaxConcurrency int `yaml:"max_concurrency,omitempty"`
+
+	// Stream prints teach and deep-dive replies as they are generated (default true).
+	// --no-stream turns it off for one run.
+	Stream *bool `yaml:"stream,omitempty"`
+
+	// MaxPromptChars caps how much note text is sent with each prompt (default 6000).
+	MaxPromptChars int `yaml:"max_prompt_chars,omitempty"`
+
+	// SummarySections replaces the note section titles sent to the model
+	// instead of the whole note (default "summary" and "key takeaways").
+	SummarySections []string `yaml:"summary_sections,omitempty"`
+
+	// OllamaHost is the base URL of the Ollama server (default http://localhost:11434).
+	OllamaHost string `yaml:"ollama_host,omitempty"`
+
+	// NotesDir is the directory import syncs when no path is given.
+	NotesDir string `yaml:"notes_dir,omitempty"`
+
+	Database DatabaseConfig `yaml:"database,omitempty"`
+	Import   ImportConfig   `yaml:"import,omitempty"`
+}
+
+// DatabaseConfig holds SQLite connection settings. Unset values keep the defaults
+// (WAL and foreign keys on, a 5 second busy timeout).
+type DatabaseConfig struct {
+	WAL           *bool `yaml:"wal,omitempty"`
+	ForeignKeys   *bool `yaml:"foreign_keys,omitempty"`
+	BusyTimeoutMS int   `yaml:"busy_timeout_ms,omitempty"`
+}
+
+// ImportConfig holds import settings.
+type ImportConfig struct {
+	// IgnoreDirs replaces the directory names import always skips
+	// (.obsidian, .trash, .git, node_modules). An empty list skips none.
+	IgnoreDirs []string `yaml:"ignore_dirs,omitempty"`
+
+	// WarnSizeKB is the note size above which import suggests splitting the note (default 20).
+	WarnSizeKB int `yaml:"warn_size_kb,omitempty"`
+}
+
+// Environment variables that override the config file. Command-line flags,
+// where a setting has one, override both.
+const (
+	EnvModel      = "NEURON_MODEL"
+	EnvOllamaHost = "NEURON_OLLAMA_HOST"
+	EnvNotesDir   = "NEURON_NOTES_DIR"
+)
+
+// NotesDirectory returns the configured notes directory with a leading "~/"
+// expanded to the home directory, or "" when none is set.
+func (c *Config) NotesDirectory() string {
+	dir := c.NotesDir
+	if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, rest)
+		}
+	}
+	return dir
+}
+
+// applyEnv replaces settings with the environment variables that are set.
+func (c *Config) applyEnv() {
+	if model := os.Getenv(EnvModel); model != "" {
+		c.Model = model
+	}
+	if host := os.Getenv(EnvOllamaHost); host != "" {
+		c.OllamaHost = host
+	}
+	if dir := os.Getenv(EnvNotesDir); dir != "" {
+		c.NotesDir = dir
+	}
+}
+
+// ModelFor returns the model a command should use: $NEURON_MODEL when set, then
+// the command's entry under models, then model. "" means the provider's default.
+func (c *Config) ModelFor(command string) string {
+	if os.Getenv(EnvModel) != "" {
+		return c.Model
+	}
+	if model := c.Models[command]; model != "" {
+		return model
+	}
+	return c.Model
+}
+
+// Path returns the location of the config file, next to the database.
+func Path() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "neuron-cli", "config.yaml"), nil
+}
+
+// Load returns the effective settings: the config file overridden by the
+// environment. Use LoadFile to change and Save the file itself.
+func Load() (*Config, error) {
+	cfg, err := LoadFile()
+	if err != nil {
+		return nil, err
+	}
+	cfg.applyEnv()
+	return cfg, nil
+}
+
+// LoadFile reads the config file alone. A missing file yields an empty Config.
+func LoadFile() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read config %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config file, creating its directory if needed.
+func Save(cfg *Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Template is the commented default file written by "neuron config init". Every
+// setting is commented out, so the built-in defaults apply until one is uncommented.
+const Template = `# Neuron CLI settings. Uncomment a line to change the default.
+# Environment variables (NEURON_MODEL, NEURON_OLLAMA_HOST, NEURON_NOTES_DIR)
+# override this file, and command-line flags override both.
+
+# Directory that "neuron import" syncs when no path is given.
+# notes_dir: ~/notes
+
+# LLM model and server.
+# model: llama3:8b-instruct-q4_K_M
+# ollama_host: http://localhost:11434
+# A different model for some commands, by command name.
+# models:
+#   review: llama3.2:3b
+#   deep-dive: llama3:70b
+# temperature: 0.7
+# seed: 42
+# LLM requests in flight at once; raise it for a hosted provider.
+# max_concurrency: 1
+# Type out teach and deep-dive replies as they are generated.
+# stream: true
+
+# Share of reviews you aim to recall (0.70-0.99).
+# target_retention: 0.9
+# Lapses before a note is tagged "leech" (0 turns it off).
+# leech_threshold: 8
+# Jitter intervals over 3 days by up to ±5% so notes learned together spread out.
+# fuzz: false
+# Delays a new note goes through before graduating to daily intervals.
+# learning_steps: [1m, 10m, 1d]
+# When a note you forgot ("Again") comes back: 10m for the same session, 3d, ...
+# lapse_interval: 1d
+# Ease lost on "Again" and gained on "Easy"; 'neuron tune' shows their effect.
+# again_penalty: 0.2
+# easy_bonus: 0.15
+# Starting ease of new notes by tag (default 2.5; lower grows intervals more slowly).
+# tag_ease:
+#   languages: 2.0
+#   trivia: 2.7
+
+# Language for generated questions, answers, feedback and chat (default English).
+# language: Spanish
+
+# Persona for "neuron reflect".
+# reflection_persona: a skeptical senior engineer
+
+# How much of a note is sent with each prompt, and which sections summarize it.
+# max_prompt_chars: 6000
+# summary_sections: [summary, key takeaways]
+
+# database:
+#   wal: true
+#   foreign_keys: true
+#   busy_timeout_ms: 5000
+
+# import:
+#   ignore_dirs: [.obsidian, .trash, .git, node_modules]
+#   warn_size_kb: 20
+`
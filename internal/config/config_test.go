@@ -0,0 +1,92 @@
+package config
+
+import "testing"
+
+func TestModelFor(t *testing.T) {
+	c := &Config{Model: "default", Models: map[string]string{"review": "small", "teach": ""}}
+	tests := []struct {
+		command, env, want string
+	}{
+		{"review", "", "small"},
+		{"deep-dive", "", "default"},
+		{"teach", "", "default"},
+		{"review", "from-env", "from-env"},
+		{"deep-dive", "from-env", "from-env"},
+	}
+	for _, tt := range tests {
+		t.Setenv(EnvModel, tt.env)
+		cfg := *c
+		cfg.applyEnv()
+		if got := cfg.ModelFor(tt.command); got != tt.want {
+			t.Errorf("ModelFor(%q) with $%s=%q = %q, want %q", tt.command, EnvModel, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestModelForWithoutModels(t *testing.T) {
+	t.Setenv(EnvModel, "")
+	if got := (&Config{}).ModelFor("review"); got != "" {
+		t.Errorf("ModelFor with nothing set = %q, want the provider's default", got)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	file := &Config{Model: "file-model", OllamaHost: "http://file:11434", NotesDir: "/file/notes", Import: ImportConfig{WarnSizeKB: 50}}
+	if err := Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                  string
+		model, host, notesDir string
+		wantModel, wantHost   string
+		wantNotesDir          string
+	}{
+		{"file only", "", "", "", "file-model", "http://file:11434", "/file/notes"},
+		{"environment wins", "env-model", "http://env:11434", "/env/notes", "env-model", "http://env:11434", "/env/notes"},
+		{"each variable on its own", "env-model", "", "", "env-model", "http://file:11434", "/file/notes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvModel, tt.model)
+			t.Setenv(EnvOllamaHost, tt.host)
+			t.Setenv(EnvNotesDir, tt.notesDir)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Model != tt.wantModel || cfg.OllamaHost != tt.wantHost || cfg.NotesDir != tt.wantNotesDir {
+				t.Errorf("Load() = model %q, host %q, notes %q; want %q, %q, %q",
+					cfg.Model, cfg.OllamaHost, cfg.NotesDir, tt.wantModel, tt.wantHost, tt.wantNotesDir)
+			}
+			// Settings without a variable always come from the file.
+			if cfg.Import.WarnSizeKB != 50 {
+				t.Errorf("Load() warn_size_kb = %d, want the file's 50", cfg.Import.WarnSizeKB)
+			}
+
+			// LoadFile is for editing the file, so it never sees the environment.
+			onDisk, err := LoadFile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if onDisk.Model != file.Model || onDisk.OllamaHost != file.OllamaHost || onDisk.NotesDir != file.NotesDir {
+				t.Errorf("LoadFile() = %+v, want the file's settings", onDisk)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(EnvModel, "env-model")
+	t.Setenv(EnvOllamaHost, "")
+	t.Setenv(EnvNotesDir, "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Model != "env-model" || cfg.OllamaHost != "" {
+		t.Errorf("Load() without a file = %+v, want only the environment's model", cfg)
+	}
+}
@@ -0,0 +1,92 @@
+// Package db handles all database interactions for Neuron CLI.
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Backup writes a consistent snapshot of the database to dest using VACUUM INTO,
+// which is safe while the database is open. dest must not exist yet.
+func Backup(db *sql.DB, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("could not create backup directory: %w", err)
+	}
+	_, err := db.Exec(`VACUUM INTO ?;`, dest)
+	return err
+}
+
+// ValidateBackup checks that src is a Neuron database and returns how many notes it holds.
+func ValidateBackup(src string) (int, error) {
+	if _, err := os.Stat(src); err != nil {
+		return 0, err
+	}
+	backup, err := sql.Open("sqlite3", "file:"+src+"?mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	defer backup.Close()
+	var count int
+	if err := backup.QueryRow(`SELECT COUNT(*) FROM notes;`).Scan(&count); err != nil {
+		return 0, fmt.Errorf("%s is not a Neuron database: %w", src, err)
+	}
+	return count, nil
+}
+
+// Restore replaces the database file with the backup at src. It must run before
+// GetDB opens the database in this process.
+func Restore(src string) error {
+	if dbInstance != nil {
+		return fmt.Errorf("cannot restore while the database is open")
+	}
+	if _, err := ValidateBackup(src); err != nil {
+		return err
+	}
+	dbPath, err := GetDatabasePath()
+	if err != nil {
+		return err
+	}
+
+	// Copy next to the database first so the final rename is atomic.
+	tmpPath := dbPath + ".restore"
+	if err := copyFile(src, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	// Leftover WAL files belong to the old database and must not be replayed onto the backup.
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			os.Remove(tmpPath)
+			return err
+		}
+	}
+	return os.Rename(tmpPath, dbPath)
+}
+
+// copyFile copies src to dest, syncing it to disk.
+func copyFile(src, dest string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
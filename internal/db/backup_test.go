@@ -0,0 +1,73 @@
+package db
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	database := openTestDB(t)
+	addTestNote(t, database, "/notes/a.md", "# A")
+	addTestNote(t, database, "/notes/b.md", "# B")
+
+	backup := filepath.Join(t.TempDir(), "backups", "neuron.db")
+	if err := Backup(database, backup); err != nil {
+		t.Fatal(err)
+	}
+	if err := Backup(database, backup); err == nil {
+		t.Error("Backup overwrote an existing file")
+	}
+	if count, err := ValidateBackup(backup); err != nil || count != 2 {
+		t.Fatalf("ValidateBackup = %d, %v; want 2 notes", count, err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dbPath, err := GetDatabasePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A stale WAL file from the database being replaced must not survive.
+	if err := os.WriteFile(dbPath+"-wal", []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Restore(backup); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dbPath + "-wal"); !os.IsNotExist(err) {
+		t.Errorf("stale WAL file left behind: %v", err)
+	}
+
+	restored, err := sql.Open("sqlite3", dsn(dbPath, DefaultOptions()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	if got := noteByFilename(t, restored, "/notes/b.md").Content; got != "# B" {
+		t.Errorf("restored note content = %q, want %q", got, "# B")
+	}
+}
+
+func TestValidateBackupRejectsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	notSQLite := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notSQLite, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	otherDB := filepath.Join(dir, "other.db")
+	other, err := sql.Open("sqlite3", otherDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Exec(`CREATE TABLE things (id INTEGER);`); err != nil {
+		t.Fatal(err)
+	}
+	other.Close()
+
+	for _, path := range []string{notSQLite, otherDB, filepath.Join(dir, "missing.db")} {
+		if _, err := ValidateBackup(path); err == nil {
+			t.Errorf("ValidateBackup(%s) accepted it", filepath.Base(path))
+		}
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,8 +21,50 @@ import (
 var (
 	dbInstance *sql.DB
 	once       sync.Once
+
+	// writeMu serializes note writes. SQLite allows a single writer anyway,
+	// and InsertNote's read-then-write must not interleave for the same file.
+	writeMu sync.Mutex
+
+	// options controls how GetDB opens the database. Change it with SetOptions before the first GetDB.
+	options = DefaultOptions()
 )
 
+// Options are the SQLite connection settings.
+type Options struct {
+	WAL         bool // Use write-ahead logging so reads don't block on a writer.
+	ForeignKeys bool // Enforce foreign key constraints.
+	BusyTimeout int  // Milliseconds to wait for a lock before failing with "database is locked".
+}
+
+// DefaultOptions returns the recommended connection settings.
+func DefaultOptions() Options {
+	return Options{WAL: true, ForeignKeys: true, BusyTimeout: 5000}
+}
+
+// SetOptions changes the connection settings. It has no effect once GetDB has connected.
+func SetOptions(o Options) {
+	options = o
+}
+
+// dsn builds the connection string. The settings are passed as DSN parameters
+// rather than one-off PRAGMAs so every pooled connection gets them.
+func dsn(path string, o Options) string {
+	params := []string{fmt.Sprintf("_busy_timeout=%d", o.BusyTimeout)}
+	// The journal mode is stored in the file, so turning WAL off has to switch it back explicitly.
+	if o.WAL {
+		params = append(params, "_journal_mode=WAL")
+	} else {
+		params = append(params, "_journal_mode=DELETE")
+	}
+	if o.ForeignKeys {
+		params = append(params, "_foreign_keys=on")
+	} else {
+		params = append(params, "_foreign_keys=off")
+	}
+	return "file:" + path + "?" + strings.Join(params, "&")
+}
+
 // GetDatabasePath determines the correct, centralized path for the database file.
 func GetDatabasePath() (string, error) {
 	configDir, err := os.UserConfigDir()
@@ -40,7 +85,7 @@ func GetDB() (*sql.DB, error) {
 		if err != nil {
 			log.Fatalf("FATAL: Could not determine database path: %v", err)
 		}
-		dbInstance, err = sql.Open("sqlite3", dbPath)
+		dbInstance, err = sql.Open("sqlite3", dsn(dbPath, options))
 		if err != nil {
 			log.Fatalf("FATAL: Could not open database at %s: %v", dbPath, err)
 		}
@@ -49,39 +94,468 @@ func GetDB() (*sql.DB, error) {
 		}
 		log.Println("Database connection established at:", dbPath)
 
-		if err = createTables(dbInstance); err != nil {
-			log.Fatalf("FATAL: Could not create database tables: %v", err)
+		if err = migrate(dbInstance); err != nil {
+			log.Fatalf("FATAL: Could not migrate database schema: %v", err)
 		}
 	})
 	return dbInstance, nil
 }
 
-func createTables(db *sql.DB) error {
-	notesTableSQL := `CREATE TABLE IF NOT EXISTS notes (id INTEGER PRIMARY KEY, filename TEXT NOT NULL UNIQUE, title TEXT NOT NULL, tags TEXT, content TEXT NOT NULL, created_at TIMESTAMP, due_date TIMESTAMP NOT NULL, interval REAL, ease_factor REAL);`
-	_, err := db.Exec(notesTableSQL)
-	return err
+// CheckDatabase opens the database on a separate connection and verifies it can be
+// written to, without the fatal errors of GetDB. It returns the database path.
+func CheckDatabase() (string, error) {
+	dbPath, err := GetDatabasePath()
+	if err != nil {
+		return "", err
+	}
+	conn, err := sql.Open("sqlite3", dsn(dbPath, options))
+	if err != nil {
+		return dbPath, err
+	}
+	defer conn.Close()
+	if err := conn.Ping(); err != nil {
+		return dbPath, fmt.Errorf("could not open: %w", err)
+	}
+	// Rewriting user_version with its own value inside a rolled-back transaction
+	// needs a write lock but changes nothing.
+	tx, err := conn.Begin()
+	if err != nil {
+		return dbPath, err
+	}
+	defer tx.Rollback()
+	var version int
+	if err := tx.QueryRow(`PRAGMA user_version;`).Scan(&version); err != nil {
+		return dbPath, fmt.Errorf("could not read: %w", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d;", version)); err != nil {
+		return dbPath, fmt.Errorf("not writable: %w", err)
+	}
+	return dbPath, nil
 }
 
-func InsertNote(db *sql.DB, n *note.Note) error {
-	tagsJSON, _ := json.Marshal(n.Tags)
-	query := `INSERT INTO notes (filename, title, tags, content, created_at, due_date, interval, ease_factor) VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT(filename) DO UPDATE SET title=excluded.title, tags=excluded.tags, content=excluded.content, created_at=excluded.created_at;`
-	stmt, err := db.Prepare(query)
+// noteColumns is the column list scanNote expects, in order.
+const noteColumns = `id, filename, title, tags, content, created_at, due_date, interval, ease_factor, aliases, modified_at, lapses, suspended, state, learning_step, question_prompt, retired`
+
+// SyncResult describes what InsertNote did with a note.
+type SyncResult int
+
+const (
+	SyncAdded     SyncResult = iota // The note was new.
+	SyncUpdated                     // The note existed and its content changed.
+	SyncUnchanged                   // The note existed with identical content; nothing was written.
+)
+
+// execer is the subset of *sql.DB and *sql.Tx used to write notes.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// InsertNote adds a note or updates the existing row with the same filename.
+// Rows whose stored content hash matches are left untouched.
+func InsertNote(db *sql.DB, n *note.Note) (SyncResult, error) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	return insertNote(db, n)
+}
+
+// NormalizeFilenames rewrites stored filenames to the form canonical returns, so
+// a note imported through another path to the same file (a symlink, or a relative
+// path) matches its existing row. canonical reports false to leave a filename as
+// it is. A row is also left alone when another row already has the canonical
+// name; the next import's cleanup offers to remove it. It returns how many rows changed.
+func NormalizeFilenames(db *sql.DB, canonical func(filename string) (string, bool)) (int, error) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	renames, err := FilenameRenames(db, canonical)
+	if err != nil || len(renames) == 0 {
+		return 0, err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	for filename, normalized := range renames {
+		if _, err := tx.Exec(`UPDATE notes SET filename = ? WHERE filename = ?;`, normalized, filename); err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	return len(renames), tx.Commit()
+}
+
+// FilenameRenames returns the renames NormalizeFilenames would make, from stored
+// filename to canonical one, without changing anything.
+func FilenameRenames(db *sql.DB, canonical func(filename string) (string, bool)) (map[string]string, error) {
+	rows, err := db.Query(`SELECT filename FROM notes;`)
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]bool)
+	var filenames []string
+	for rows.Next() {
+		var filename string
+		if err := rows.Scan(&filename); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		stored[filename] = true
+		filenames = append(filenames, filename)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	renames := make(map[string]string)
+	for _, filename := range filenames {
+		normalized, ok := canonical(filename)
+		if !ok || normalized == filename || stored[normalized] {
+			continue
+		}
+		renames[filename] = normalized
+		delete(stored, filename)
+		stored[normalized] = true
+	}
+	return renames, nil
+}
+
+// InsertNotesTx inserts notes in a single transaction, so an import either
+// fully applies or leaves the database untouched. Results are in input order.
+func InsertNotesTx(db *sql.DB, notes []*note.Note) ([]SyncResult, error) {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	tx, err := db.Begin()
 	if err != nil {
+		return nil, err
+	}
+	results := make([]SyncResult, 0, len(notes))
+	for _, n := range notes {
+		result, err := insertNote(tx, n)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to insert %s: %w", n.Filename, err)
+		}
+		results = append(results, result)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// PreviewSync reports what InsertNotesTx would do with each note without writing anything.
+// renames, from FilenameRenames, are treated as already made, so a note is compared
+// with the stored row that would take its filename.
+func PreviewSync(db *sql.DB, notes []*note.Note, renames map[string]string) ([]SyncResult, error) {
+	renamedFrom := make(map[string]string, len(renames))
+	for from, to := range renames {
+		renamedFrom[to] = from
+	}
+	results := make([]SyncResult, 0, len(notes))
+	for _, n := range notes {
+		stored := n
+		if from, ok := renamedFrom[n.Filename]; ok {
+			renamed := *n
+			renamed.Filename = from
+			stored = &renamed
+		}
+		result, _, err := syncStatus(db, stored)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", n.Filename, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// syncStatus compares a note with its stored row and returns the note's content hash.
+func syncStatus(db execer, n *note.Note) (SyncResult, string, error) {
+	hash := note.ContentHash(n.Content)
+	var existingHash sql.NullString
+	err := db.QueryRow(`SELECT content_hash FROM notes WHERE filename = ?;`, n.Filename).Scan(&existingHash)
+	switch {
+	case err == sql.ErrNoRows:
+		return SyncAdded, hash, nil
+	case err != nil:
+		return 0, "", err
+	case existingHash.Valid && existingHash.String == hash:
+		return SyncUnchanged, hash, nil
+	}
+	return SyncUpdated, hash, nil
+}
+
+func insertNote(db execer, n *note.Note) (SyncResult, error) {
+	result, hash, err := syncStatus(db, n)
+	if err != nil || result == SyncUnchanged {
+		return result, err
+	}
+
+	tags := n.Tags
+	if result == SyncUpdated {
+		if tags, err = keepLeechTag(db, n); err != nil {
+			return 0, err
+		}
+	}
+	tagsJSON, _ := json.Marshal(tags)
+	aliasesJSON, _ := json.Marshal(n.Aliases)
+	query := `INSERT INTO notes (filename, title, tags, content, created_at, due_date, interval, ease_factor, aliases, modified_at, content_hash, question_prompt) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT(filename) DO UPDATE SET title=excluded.title, tags=excluded.tags, content=excluded.content, created_at=excluded.created_at, aliases=excluded.aliases, modified_at=excluded.modified_at, content_hash=excluded.content_hash, question_prompt=excluded.question_prompt;`
+	_, err = db.Exec(query, n.Filename, n.Title, string(tagsJSON), n.Content, n.CreatedAt, n.DueDate, n.Interval, n.EaseFactor, string(aliasesJSON), n.ModifiedAt, hash, n.QuestionPrompt)
+	if err != nil {
+		return 0, err
+	}
+	return result, replaceLinks(db, n)
+}
+
+// keepLeechTag returns the note's tags plus the leech tag when the stored row
+// carries it, since reviews add that tag and the file never has it.
+func keepLeechTag(db execer, n *note.Note) ([]string, error) {
+	if n.HasTag(note.LeechTag) {
+		return n.Tags, nil
+	}
+	var tagsJSON string
+	if err := db.QueryRow(`SELECT tags FROM notes WHERE filename = ?;`, n.Filename).Scan(&tagsJSON); err != nil {
+		return nil, err
+	}
+	stored := &note.Note{}
+	if err := json.Unmarshal([]byte(tagsJSON), &stored.Tags); err != nil {
+		return nil, err
+	}
+	if !stored.HasTag(note.LeechTag) {
+		return n.Tags, nil
+	}
+	return append(append([]string(nil), n.Tags...), note.LeechTag), nil
+}
+
+// replaceLinks stores the note's wikilink targets, replacing any from a previous import.
+func replaceLinks(db execer, n *note.Note) error {
+	var noteID int
+	if err := db.QueryRow(`SELECT id FROM notes WHERE filename = ?;`, n.Filename).Scan(&noteID); err != nil {
 		return err
 	}
-	defer stmt.Close()
-	_, err = stmt.Exec(n.Filename, n.Title, string(tagsJSON), n.Content, n.CreatedAt, n.DueDate, n.Interval, n.EaseFactor)
-	return err
+	if _, err := db.Exec(`DELETE FROM links WHERE source_id = ?;`, noteID); err != nil {
+		return err
+	}
+	for _, target := range n.Links {
+		if _, err := db.Exec(`INSERT OR IGNORE INTO links (source_id, target) VALUES (?, ?);`, noteID, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLinkTargets returns the raw wikilink targets of a note.
+func GetLinkTargets(db *sql.DB, noteID int) ([]string, error) {
+	rows, err := db.Query(`SELECT target FROM links WHERE source_id = ? ORDER BY target;`, noteID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var targets []string
+	for rows.Next() {
+		var target string
+		if err := rows.Scan(&target); err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+// AllLinks returns the raw wikilink targets of every note, keyed by source note ID.
+func AllLinks(db *sql.DB) (map[int][]string, error) {
+	rows, err := db.Query(`SELECT source_id, target FROM links ORDER BY source_id, target;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	links := make(map[int][]string)
+	for rows.Next() {
+		var sourceID int
+		var target string
+		if err := rows.Scan(&sourceID, &target); err != nil {
+			return nil, err
+		}
+		links[sourceID] = append(links[sourceID], target)
+	}
+	return links, rows.Err()
+}
+
+// ResolveLink finds the note a wikilink target refers to, matching (case-insensitively)
+// its title, its filename without the extension, or one of its aliases.
+func ResolveLink(db *sql.DB, target string) (*note.Note, error) {
+	// LIKE narrows the filename candidates; the stem comparison below is the real test,
+	// since the extension is whatever --ext allowed at import.
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE lower(title) = lower(?1) OR filename LIKE '%' || ?2 || '.%' ESCAPE '\' OR EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(notes.aliases) THEN notes.aliases ELSE '[]' END) WHERE lower(json_each.value) = lower(?1)) ORDER BY id;`
+	rows, err := db.Query(query, target, likeEscaper.Replace(target))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		n, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(n.Title, target) || strings.EqualFold(filenameStem(n.Filename), target) || slices.ContainsFunc(n.Aliases, func(alias string) bool { return strings.EqualFold(alias, target) }) {
+			return n, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return nil, sql.ErrNoRows
+}
+
+// filenameStem returns the base name of filename without its extension.
+func filenameStem(filename string) string {
+	return strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+}
+
+// GetBacklinks returns the notes that link to n by title, filename, or alias.
+func GetBacklinks(db *sql.DB, n *note.Note) ([]*note.Note, error) {
+	names := []string{n.Title, filenameStem(n.Filename)}
+	names = append(names, n.Aliases...)
+
+	placeholders := make([]string, len(names))
+	args := make([]any, 0, len(names)+1)
+	for i, name := range names {
+		placeholders[i] = "lower(?)"
+		args = append(args, name)
+	}
+	args = append(args, n.ID)
+
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE id IN (SELECT source_id FROM links WHERE lower(target) IN (` + strings.Join(placeholders, ", ") + `)) AND id != ? ORDER BY title;`
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// FindRelatedNotes ranks the other notes by how closely they relate to n: one point
+// per shared tag (case-insensitive) and one for each direction they are wikilinked.
+// Ties are broken by title, notes with nothing in common are left out, and at most
+// limit notes are returned.
+func FindRelatedNotes(db *sql.DB, n *note.Note, limit int) ([]*note.Note, error) {
+	notes, err := AllNotes(db)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]bool)
+	for _, tag := range n.Tags {
+		tags[strings.ToLower(tag)] = true
+	}
+	scores := make(map[int]int)
+	for _, other := range notes {
+		for _, tag := range other.Tags {
+			if tags[strings.ToLower(tag)] {
+				scores[other.ID]++
+			}
+		}
+	}
+
+	targets, err := GetLinkTargets(db, n.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range targets {
+		linked, err := ResolveLink(db, target)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scores[linked.ID]++
+	}
+	backlinks, err := GetBacklinks(db, n)
+	if err != nil {
+		return nil, err
+	}
+	for _, backlink := range backlinks {
+		scores[backlink.ID]++
+	}
+
+	var related []*note.Note
+	for _, other := range notes {
+		if other.ID != n.ID && scores[other.ID] > 0 {
+			related = append(related, other)
+		}
+	}
+	sort.SliceStable(related, func(i, j int) bool {
+		if scores[related[i].ID] != scores[related[j].ID] {
+			return scores[related[i].ID] > scores[related[j].ID]
+		}
+		return related[i].Title < related[j].Title
+	})
+	if limit >= 0 && len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
 }
 
 func GetDueNote(db *sql.DB) (*note.Note, error) {
-	query := `SELECT id, filename, title, tags, content, created_at, due_date, interval, ease_factor FROM notes WHERE due_date <= ? ORDER BY due_date ASC LIMIT 1;`
-	row := db.QueryRow(query, time.Now())
-	return scanNote(row)
+	return GetDueNoteOrdered(db, OrderDue, false)
+}
+
+// ReviewOrder chooses which due note GetDueNoteOrdered returns first.
+type ReviewOrder string
+
+// Review orders accepted by GetDueNoteOrdered.
+const (
+	OrderNew    ReviewOrder = "new"    // most recently created first
+	OrderOld    ReviewOrder = "old"    // oldest created first
+	OrderDue    ReviewOrder = "due"    // most overdue first
+	OrderRandom ReviewOrder = "random" // any due note
+)
+
+// reviewOrderClauses maps each ReviewOrder to its ORDER BY clause.
+var reviewOrderClauses = map[ReviewOrder]string{
+	OrderNew:    "created_at DESC",
+	OrderOld:    "created_at ASC",
+	OrderDue:    "due_date ASC",
+	OrderRandom: "RANDOM()",
+}
+
+// ParseReviewOrder validates an order name given on the command line.
+func ParseReviewOrder(name string) (ReviewOrder, error) {
+	order := ReviewOrder(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := reviewOrderClauses[order]; !ok {
+		return "", fmt.Errorf("unknown order %q (valid orders: new, old, due, random)", name)
+	}
+	return order, nil
+}
+
+// GetDueNoteOrdered returns the first due note in the given order. With excludeNew,
+// notes that have never been reviewed are skipped.
+func GetDueNoteOrdered(db *sql.DB, order ReviewOrder, excludeNew bool) (*note.Note, error) {
+	clause, ok := reviewOrderClauses[order]
+	if !ok {
+		return nil, fmt.Errorf("unknown review order %q", order)
+	}
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ?`
+	if excludeNew {
+		query += ` AND state != '` + note.StateNew + `'`
+	}
+	query += ` ORDER BY ` + clause + ` LIMIT 1;`
+	return scanNote(db.QueryRow(query, time.Now()))
 }
 
 func GetDueNotes(db *sql.DB, limit int) ([]*note.Note, error) {
-	query := `SELECT id, filename, title, tags, content, created_at, due_date, interval, ease_factor FROM notes WHERE due_date <= ? ORDER BY RANDOM() LIMIT ?;`
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ? ORDER BY RANDOM() LIMIT ?;`
 	rows, err := db.Query(query, time.Now(), limit)
 	if err != nil {
 		return nil, err
@@ -98,24 +572,577 @@ func GetDueNotes(db *sql.DB, limit int) ([]*note.Note, error) {
 	return notes, nil
 }
 
+// GetDueNoteExcludingNew is like GetDueNote but skips notes that have never been reviewed.
+func GetDueNoteExcludingNew(db *sql.DB) (*note.Note, error) {
+	return GetDueNoteOrdered(db, OrderDue, true)
+}
+
+// GetDueNotesExcludingNew is like GetDueNotes but skips notes that have never been reviewed.
+func GetDueNotesExcludingNew(db *sql.DB, limit int) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ? AND state != '` + note.StateNew + `' ORDER BY RANDOM() LIMIT ?;`
+	rows, err := db.Query(query, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// GetDueNotesWeighted returns up to limit due notes in random order, drawn from the
+// 2×limit most overdue ones, so long-overdue notes come up more often than barely due ones.
+func GetDueNotesWeighted(db *sql.DB, limit int) ([]*note.Note, error) {
+	return getDueNotesWeighted(db, limit, false)
+}
+
+// GetDueNotesWeightedExcludingNew is like GetDueNotesWeighted but skips notes that have never been reviewed.
+func GetDueNotesWeightedExcludingNew(db *sql.DB, limit int) ([]*note.Note, error) {
+	return getDueNotesWeighted(db, limit, true)
+}
+
+func getDueNotesWeighted(db *sql.DB, limit int, excludeNew bool) ([]*note.Note, error) {
+	filter := ""
+	if excludeNew {
+		filter = ` AND state != '` + note.StateNew + `'`
+	}
+	query := `SELECT ` + noteColumns + ` FROM (SELECT * FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ?` + filter + ` ORDER BY due_date ASC LIMIT ?) ORDER BY RANDOM() LIMIT ?;`
+	rows, err := db.Query(query, time.Now(), 2*limit, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// CountDueNotes returns how many notes are currently due for review.
+func CountDueNotes(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ?;`, time.Now()).Scan(&count)
+	return count, err
+}
+
+// CountDueWithin returns how many notes will be due within d from now, including overdue ones.
+func CountDueWithin(db *sql.DB, d time.Duration) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ?;`, time.Now().Add(d)).Scan(&count)
+	return count, err
+}
+
+// dayKey formats a time as the local calendar date used to key daily_stats.
+func dayKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+// GetDailyStats returns how many reviews and new cards were done on the calendar day containing t.
+func GetDailyStats(db *sql.DB, t time.Time) (int, int, error) {
+	var reviews, newCards int
+	err := db.QueryRow(`SELECT reviews_done, new_done FROM daily_stats WHERE date = ?;`, dayKey(t)).Scan(&reviews, &newCards)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil
+	}
+	return reviews, newCards, err
+}
+
+// RecordReview counts one review (and optionally one new card) toward the day containing t.
+func RecordReview(db *sql.DB, t time.Time, wasNew bool) error {
+	newCards := 0
+	if wasNew {
+		newCards = 1
+	}
+	query := `INSERT INTO daily_stats (date, reviews_done, new_done) VALUES (?, 1, ?) ON CONFLICT(date) DO UPDATE SET reviews_done = reviews_done + 1, new_done = new_done + excluded.new_done;`
+	_, err := db.Exec(query, dayKey(t), newCards)
+	return err
+}
+
+// LogReview appends a rating to the review log. think is how long the question was
+// shown before the answer was revealed; zero means it wasn't timed.
+func LogReview(db *sql.DB, noteID, rating int, think time.Duration, at time.Time) error {
+	var thinkMS sql.NullInt64
+	if think > 0 {
+		thinkMS = sql.NullInt64{Int64: think.Milliseconds(), Valid: true}
+	}
+	_, err := db.Exec(`INSERT INTO review_log (note_id, reviewed_at, rating, think_ms) VALUES (?, ?, ?, ?);`, noteID, at, rating, thinkMS)
+	return err
+}
+
+// ReviewTiming is one timed review from the review log.
+type ReviewTiming struct {
+	NoteID int
+	Title  string
+	Think  time.Duration
+}
+
+// ReviewTimings returns every timed review of a note that still exists, oldest first.
+func ReviewTimings(db *sql.DB) ([]ReviewTiming, error) {
+	rows, err := db.Query(`SELECT review_log.note_id, notes.title, review_log.think_ms FROM review_log JOIN notes ON notes.id = review_log.note_id WHERE review_log.think_ms IS NOT NULL ORDER BY review_log.reviewed_at;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var timings []ReviewTiming
+	for rows.Next() {
+		var t ReviewTiming
+		var thinkMS int64
+		if err := rows.Scan(&t.NoteID, &t.Title, &thinkMS); err != nil {
+			return nil, err
+		}
+		t.Think = time.Duration(thinkMS) * time.Millisecond
+		timings = append(timings, t)
+	}
+	return timings, rows.Err()
+}
+
+// WeekStat is one week of the review log, split by how each review was rated.
+type WeekStat struct {
+	WeekStart time.Time // the Monday the week starts on
+	Again     int       // reviews rated Again
+	Recalled  int       // reviews rated Good or Easy
+}
+
+// Reviews is the number of ratings logged that week.
+func (w WeekStat) Reviews() int {
+	return w.Again + w.Recalled
+}
+
+// Retention is the fraction of the week's reviews that were recalled.
+func (w WeekStat) Retention() float64 {
+	if w.Reviews() == 0 {
+		return 0
+	}
+	return float64(w.Recalled) / float64(w.Reviews())
+}
+
+// RetentionByWeek groups the review log by week (Monday to Sunday, by the date each
+// review was logged), oldest week first. Weeks without reviews are left out.
+func RetentionByWeek(db *sql.DB) ([]WeekStat, error) {
+	rows, err := db.Query(`SELECT date(substr(reviewed_at, 1, 10), 'weekday 0', '-6 days') AS week,
+		SUM(CASE WHEN rating = 1 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN rating > 1 THEN 1 ELSE 0 END)
+		FROM review_log GROUP BY week ORDER BY week;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var weeks []WeekStat
+	for rows.Next() {
+		var week string
+		var w WeekStat
+		if err := rows.Scan(&week, &w.Again, &w.Recalled); err != nil {
+			return nil, err
+		}
+		if w.WeekStart, err = time.ParseInLocation(time.DateOnly, week, time.Local); err != nil {
+			return nil, err
+		}
+		weeks = append(weeks, w)
+	}
+	return weeks, rows.Err()
+}
+
+// CountReviews returns how many ratings the review log holds.
+func CountReviews(db *sql.DB) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM review_log;`).Scan(&count)
+	return count, err
+}
+
+// Meta keys recording the last completed import.
+const (
+	lastImportKey     = "last_import"      // when it started
+	lastImportPathKey = "last_import_path" // the directory it read
+)
+
+// getMeta returns a meta value. The bool is false if the key is not set.
+func getMeta(db *sql.DB, key string) (string, bool, error) {
+	var value string
+	err := db.QueryRow(`SELECT value FROM meta WHERE key = ?;`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	return value, err == nil, err
+}
+
+// setMeta stores a meta value, replacing any previous one.
+func setMeta(db *sql.DB, key, value string) error {
+	_, err := db.Exec(`INSERT INTO meta (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value;`, key, value)
+	return err
+}
+
+// GetLastImport returns when the last import started. The bool is false if none was recorded.
+func GetLastImport(db *sql.DB) (time.Time, bool, error) {
+	value, ok, err := getMeta(db, lastImportKey)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid last import time %q: %w", value, err)
+	}
+	return t, true, nil
+}
+
+// SetLastImport records when the latest import started.
+func SetLastImport(db *sql.DB, t time.Time) error {
+	return setMeta(db, lastImportKey, t.Format(time.RFC3339Nano))
+}
+
+// GetLastImportPath returns the directory of the last import. The bool is false if none was recorded.
+func GetLastImportPath(db *sql.DB) (string, bool, error) {
+	return getMeta(db, lastImportPathKey)
+}
+
+// SetLastImportPath records the directory the latest import read.
+func SetLastImportPath(db *sql.DB, path string) error {
+	return setMeta(db, lastImportPathKey, path)
+}
+
+// AllNotes returns every note in the database ordered by id.
+func AllNotes(db *sql.DB) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes ORDER BY id ASC;`
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// GetRandomNotes returns up to limit random notes that aren't suspended or retired, due or not.
+func GetRandomNotes(db *sql.DB, limit int) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 ORDER BY RANDOM() LIMIT ?;`
+	return queryNotes(db, query, limit)
+}
+
+// GetNotesByTag returns up to limit random notes carrying the tag (case-insensitive),
+// leaving out suspended and retired ones.
+func GetNotesByTag(db *sql.DB, tag string, limit int) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 AND ` + hasTagClause + ` ORDER BY RANDOM() LIMIT ?;`
+	return queryNotes(db, query, tag, limit)
+}
+
+// hasTagClause matches notes carrying the tag bound to its placeholder (case-insensitive).
+const hasTagClause = `EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(notes.tags) THEN notes.tags ELSE '[]' END) WHERE lower(json_each.value) = lower(?))`
+
+// GetDueNotesByTag returns up to limit due notes carrying the tag, most overdue first.
+func GetDueNotesByTag(db *sql.DB, tag string, limit int) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 AND due_date <= ? AND ` + hasTagClause + ` ORDER BY due_date ASC LIMIT ?;`
+	return queryNotes(db, query, time.Now(), tag, limit)
+}
+
+// GetUpcomingNotesByTag returns up to limit notes carrying the tag that aren't due
+// yet, the ones due soonest first.
+func GetUpcomingNotesByTag(db *sql.DB, tag string, limit int) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 AND due_date > ? AND ` + hasTagClause + ` ORDER BY due_date ASC LIMIT ?;`
+	return queryNotes(db, query, time.Now(), tag, limit)
+}
+
+// queryNotes runs a query selecting noteColumns and scans every row.
+func queryNotes(db *sql.DB, query string, args ...any) ([]*note.Note, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
 func GetAnyNote(db *sql.DB) (*note.Note, error) {
-	query := `SELECT id, filename, title, tags, content, created_at, due_date, interval, ease_factor FROM notes ORDER BY RANDOM() LIMIT 1;`
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE suspended = 0 AND retired = 0 ORDER BY RANDOM() LIMIT 1;`
 	row := db.QueryRow(query)
 	return scanNote(row)
 }
 
+// AmbiguousMatchError is returned by GetNoteByTitleOrFilename when several notes
+// match the search term equally well.
+type AmbiguousMatchError struct {
+	Term    string
+	Matches []*note.Note
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	return fmt.Sprintf("%q matches %d notes equally well", e.Term, len(e.Matches))
+}
+
+// Match ranks used by GetNoteByTitleOrFilename; lower is better.
+const (
+	rankExactTitle = iota
+	rankExactAlias
+	rankTitlePrefix
+	rankTitleSubstring
+	rankOther // the filename or an alias contains the term
+)
+
+// matchRank says how well n matches term, which SearchNotes already found in it.
+func matchRank(n *note.Note, term string) int {
+	title, term := strings.ToLower(n.Title), strings.ToLower(term)
+	switch {
+	case title == term:
+		return rankExactTitle
+	case slices.ContainsFunc(n.Aliases, func(a string) bool { return strings.EqualFold(a, term) }):
+		return rankExactAlias
+	case strings.HasPrefix(title, term):
+		return rankTitlePrefix
+	case strings.Contains(title, term):
+		return rankTitleSubstring
+	}
+	return rankOther
+}
+
+// GetNoteByTitleOrFilename returns the note that best matches searchTerm: an exact
+// title, then an exact alias, a title prefix, a title substring, and finally a
+// filename or alias substring (all case-insensitive). It returns sql.ErrNoRows when
+// nothing matches and an *AmbiguousMatchError when several notes tie for the best rank.
 func GetNoteByTitleOrFilename(db *sql.DB, searchTerm string) (*note.Note, error) {
-	query := `SELECT id, filename, title, tags, content, created_at, due_date, interval, ease_factor FROM notes WHERE title LIKE ? OR filename LIKE ? LIMIT 1;`
-	row := db.QueryRow(query, "%"+searchTerm+"%", "%"+searchTerm+"%")
-	return scanNote(row)
+	notes, err := FindNotes(db, searchTerm)
+	if err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	best := matchRank(notes[0], searchTerm)
+	tied := 1
+	for tied < len(notes) && matchRank(notes[tied], searchTerm) == best {
+		tied++
+	}
+	if tied > 1 {
+		return nil, &AmbiguousMatchError{Term: searchTerm, Matches: notes[:tied]}
+	}
+	return notes[0], nil
+}
+
+// FindNotes returns every note whose title, filename, or aliases contain term,
+// best match first (ranked as in GetNoteByTitleOrFilename), then by title.
+func FindNotes(db *sql.DB, term string) ([]*note.Note, error) {
+	notes, err := SearchNotes(db, term)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return matchRank(notes[i], term) < matchRank(notes[j], term)
+	})
+	return notes, nil
+}
+
+// SearchNotes returns every note whose title, filename, or aliases contain term, ordered by title.
+func SearchNotes(db *sql.DB, term string) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes WHERE title LIKE ?1 ESCAPE '\' OR filename LIKE ?1 ESCAPE '\' OR aliases LIKE ?1 ESCAPE '\' ORDER BY title;`
+	pattern := containsPattern(term)
+	rows, err := db.Query(query, pattern)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// likeEscaper escapes text for a LIKE pattern with ESCAPE '\', so "%" and "_"
+// in it aren't wildcards.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsPattern returns a LIKE pattern (with ESCAPE '\') matching values that
+// contain term literally.
+func containsPattern(term string) string {
+	return "%" + likeEscaper.Replace(term) + "%"
+}
+
+// searchNotesWhere matches ?1 against a note's title, filename, aliases and content.
+const searchNotesWhere = ` WHERE title LIKE ?1 ESCAPE '\' OR filename LIKE ?1 ESCAPE '\' OR aliases LIKE ?1 ESCAPE '\' OR content LIKE ?1 ESCAPE '\'`
+
+// SearchNotesPage returns one page of the notes whose title, filename, aliases or
+// content contain term, ordered by title and then id so that pages never overlap:
+// up to limit notes after skipping offset.
+func SearchNotesPage(db *sql.DB, term string, limit, offset int) ([]*note.Note, error) {
+	query := `SELECT ` + noteColumns + ` FROM notes` + searchNotesWhere + ` ORDER BY title, id LIMIT ?2 OFFSET ?3;`
+	rows, err := db.Query(query, containsPattern(term), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var notes []*note.Note
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+// CountSearchNotes returns how many notes SearchNotesPage can page through for term.
+func CountSearchNotes(db *sql.DB, term string) (int, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM notes`+searchNotesWhere+`;`, containsPattern(term)).Scan(&count)
+	return count, err
 }
 
+// UpdateNoteSRS saves the scheduling fields of a note, along with its tags,
+// since reviews can add a leech tag.
 func UpdateNoteSRS(db *sql.DB, n *note.Note) error {
-	query := `UPDATE notes SET due_date = ?, interval = ?, ease_factor = ? WHERE id = ?;`
-	_, err := db.Exec(query, n.DueDate, n.Interval, n.EaseFactor, n.ID)
+	tagsJSON, _ := json.Marshal(n.Tags)
+	query := `UPDATE notes SET due_date = ?, interval = ?, ease_factor = ?, lapses = ?, tags = ?, state = ?, learning_step = ? WHERE id = ?;`
+	_, err := db.Exec(query, n.DueDate, n.Interval, n.EaseFactor, n.Lapses, string(tagsJSON), n.State, n.LearningStep, n.ID)
+	return err
+}
+
+// resetSRSSet is the SET clause that restores a note's schedule to that of a freshly imported note.
+const resetSRSSet = `SET interval = ?, ease_factor = ?, due_date = ?, state = '` + note.StateNew + `', learning_step = 0`
+
+// ResetSRS restarts a note's schedule: default interval and ease factor, due now.
+func ResetSRS(db *sql.DB, noteID int) error {
+	_, err := db.Exec(`UPDATE notes `+resetSRSSet+` WHERE id = ?;`, note.DefaultInterval, note.DefaultEaseFactor, time.Now(), noteID)
+	return err
+}
+
+// ResetAllSRS restarts the schedule of every note and returns how many were reset.
+func ResetAllSRS(db *sql.DB) (int64, error) {
+	result, err := db.Exec(`UPDATE notes `+resetSRSSet+`;`, note.DefaultInterval, note.DefaultEaseFactor, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ResetSRSByTag restarts the schedule of every note with the tag (case-insensitive).
+func ResetSRSByTag(db *sql.DB, tag string) (int64, error) {
+	query := `UPDATE notes ` + resetSRSSet + ` WHERE EXISTS (SELECT 1 FROM json_each(CASE WHEN json_valid(notes.tags) THEN notes.tags ELSE '[]' END) WHERE lower(json_each.value) = lower(?));`
+	result, err := db.Exec(query, note.DefaultInterval, note.DefaultEaseFactor, time.Now(), tag)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// SetSuspended suspends or unsuspends a note. Suspended notes are never picked for review.
+func SetSuspended(db *sql.DB, noteID int, suspended bool) error {
+	_, err := db.Exec(`UPDATE notes SET suspended = ? WHERE id = ?;`, suspended, noteID)
+	return err
+}
+
+// SetRetired retires or unretires a note. Unlike suspending, retiring is meant
+// for notes you have mastered; retired notes are never picked for review.
+func SetRetired(db *sql.DB, noteID int, retired bool) error {
+	_, err := db.Exec(`UPDATE notes SET retired = ? WHERE id = ?;`, retired, noteID)
 	return err
 }
 
+// NoteCounts is how many notes there are in total, and how many of them are
+// suspended or retired.
+type NoteCounts struct {
+	Total     int
+	Suspended int
+	Retired   int
+}
+
+// CountNotes counts the notes by whether they are suspended or retired.
+func CountNotes(db *sql.DB) (NoteCounts, error) {
+	var c NoteCounts
+	err := db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(suspended), 0), COALESCE(SUM(retired), 0) FROM notes;`).Scan(&c.Total, &c.Suspended, &c.Retired)
+	return c, err
+}
+
+// BuryNote moves a note's due date to until without touching its interval or ease factor.
+func BuryNote(db *sql.DB, noteID int, until time.Time) error {
+	_, err := db.Exec(`UPDATE notes SET due_date = ? WHERE id = ?;`, until, noteID)
+	return err
+}
+
+// MergeNotes saves target's content, tags and due date and folds the note with id
+// sourceID into it: the source's links and review history move to target, then the
+// source is deleted. Everything happens in one transaction.
+func MergeNotes(db *sql.DB, target *note.Note, sourceID int) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	tagsJSON, _ := json.Marshal(target.Tags)
+	steps := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE notes SET content = ?, tags = ?, due_date = ? WHERE id = ?;`, []any{target.Content, string(tagsJSON), target.DueDate, target.ID}},
+		{`INSERT OR IGNORE INTO links (source_id, target) SELECT ?, target FROM links WHERE source_id = ?;`, []any{target.ID, sourceID}},
+		{`DELETE FROM links WHERE source_id = ?;`, []any{sourceID}},
+		{`UPDATE review_log SET note_id = ? WHERE note_id = ?;`, []any{target.ID, sourceID}},
+		{`DELETE FROM qa_cache WHERE note_id IN (?, ?);`, []any{sourceID, target.ID}},
+		{`DELETE FROM notes WHERE id = ?;`, []any{sourceID}},
+	}
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, step.args...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCachedQA returns the cached question/answer pair for a note and question type.
+// It returns sql.ErrNoRows when nothing is cached or the note content has changed since caching.
+func GetCachedQA(db *sql.DB, noteID int, questionType, contentHash string) (string, string, error) {
+	query := `SELECT question, answer FROM qa_cache WHERE note_id = ? AND question_type = ? AND content_hash = ?;`
+	var question, answer string
+	err := db.QueryRow(query, noteID, questionType, contentHash).Scan(&question, &answer)
+	return question, answer, err
+}
+
+// SaveCachedQA stores (or replaces) the question/answer pair for a note and question type.
+func SaveCachedQA(db *sql.DB, noteID int, questionType, contentHash, question, answer string) error {
+	query := `INSERT INTO qa_cache (note_id, question_type, content_hash, question, answer, created_at) VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT(note_id, question_type) DO UPDATE SET content_hash=excluded.content_hash, question=excluded.question, answer=excluded.answer, created_at=excluded.created_at;`
+	_, err := db.Exec(query, noteID, questionType, contentHash, question, answer, time.Now())
+	return err
+}
+
+// ClearQACache removes every cached question/answer pair and returns how many were deleted.
+func ClearQACache(db *sql.DB) (int64, error) {
+	res, err := db.Exec(`DELETE FROM qa_cache;`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // scanNote is a helper to reduce code duplication when scanning a single row into a Note struct.
 type scannable interface {
 	Scan(dest ...any) error
@@ -124,12 +1151,22 @@ type scannable interface {
 func scanNote(row scannable) (*note.Note, error) {
 	var n note.Note
 	var tagsJSON string
-	err := row.Scan(&n.ID, &n.Filename, &n.Title, &tagsJSON, &n.Content, &n.CreatedAt, &n.DueDate, &n.Interval, &n.EaseFactor)
+	var aliasesJSON sql.NullString
+	var modifiedAt sql.NullTime
+	err := row.Scan(&n.ID, &n.Filename, &n.Title, &tagsJSON, &n.Content, &n.CreatedAt, &n.DueDate, &n.Interval, &n.EaseFactor, &aliasesJSON, &modifiedAt, &n.Lapses, &n.Suspended, &n.State, &n.LearningStep, &n.QuestionPrompt, &n.Retired)
 	if err != nil {
 		return nil, err
 	}
+	n.ModifiedAt = modifiedAt.Time
+	n.Cards = note.ExtractCards(n.Content)
 	if err := json.Unmarshal([]byte(tagsJSON), &n.Tags); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal tags for note %d: %w", n.ID, err)
 	}
+	// Notes imported before aliases existed have a NULL column.
+	if aliasesJSON.Valid && aliasesJSON.String != "" {
+		if err := json.Unmarshal([]byte(aliasesJSON.String), &n.Aliases); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal aliases for note %d: %w", n.ID, err)
+		}
+	}
 	return &n, nil
 }
@@ -0,0 +1,792 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// openTestDB returns a migrated database in a temporary directory.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := sql.Open("sqlite3", dsn(filepath.Join(t.TempDir(), "neuron.db"), DefaultOptions()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := migrate(database); err != nil {
+		t.Fatal(err)
+	}
+	return database
+}
+
+// addTestNote inserts a note that is due now and returns it as stored.
+func addTestNote(t *testing.T, database *sql.DB, filename, content string, tags ...string) *note.Note {
+	t.Helper()
+	n := &note.Note{
+		Filename:   filename,
+		Title:      filepath.Base(filename),
+		Tags:       tags,
+		Content:    content,
+		CreatedAt:  time.Now(),
+		DueDate:    time.Now().Add(-time.Hour),
+		Interval:   note.DefaultInterval,
+		EaseFactor: note.DefaultEaseFactor,
+	}
+	if _, err := InsertNote(database, n); err != nil {
+		t.Fatal(err)
+	}
+	return noteByFilename(t, database, filename)
+}
+
+// noteByFilename reads a note back from the database.
+func noteByFilename(t *testing.T, database *sql.DB, filename string) *note.Note {
+	t.Helper()
+	n, err := scanNote(database.QueryRow(`SELECT `+noteColumns+` FROM notes WHERE filename = ?;`, filename))
+	if err != nil {
+		t.Fatalf("reading %s: %v", filename, err)
+	}
+	return n
+}
+
+func TestQACacheHitAndMiss(t *testing.T) {
+	database := openTestDB(t)
+	n := addTestNote(t, database, "/notes/cache.md", "# Cache\nbody")
+	hash := note.ContentHash(n.Content)
+
+	if _, _, err := GetCachedQA(database, n.ID, "factual", hash); err != sql.ErrNoRows {
+		t.Fatalf("empty cache: got err %v, want sql.ErrNoRows", err)
+	}
+	if err := SaveCachedQA(database, n.ID, "factual", hash, "Q?", "A."); err != nil {
+		t.Fatal(err)
+	}
+	question, answer, err := GetCachedQA(database, n.ID, "factual", hash)
+	if err != nil || question != "Q?" || answer != "A." {
+		t.Fatalf("hit: got %q, %q, %v", question, answer, err)
+	}
+	if _, _, err := GetCachedQA(database, n.ID, "conceptual", hash); err != sql.ErrNoRows {
+		t.Errorf("other question type: got err %v, want sql.ErrNoRows", err)
+	}
+	if _, _, err := GetCachedQA(database, n.ID, "factual", note.ContentHash("edited")); err != sql.ErrNoRows {
+		t.Errorf("edited note: got err %v, want sql.ErrNoRows", err)
+	}
+
+	cleared, err := ClearQACache(database)
+	if err != nil || cleared != 1 {
+		t.Fatalf("ClearQACache = %d, %v; want 1", cleared, err)
+	}
+	if _, _, err := GetCachedQA(database, n.ID, "factual", hash); err != sql.ErrNoRows {
+		t.Errorf("after clear: got err %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestMergeNotes(t *testing.T) {
+	database := openTestDB(t)
+	source := addTestNote(t, database, "/notes/source.md", "Source", "go")
+	target := addTestNote(t, database, "/notes/target.md", "Target", "basics")
+	for _, link := range []struct {
+		id     int
+		target string
+	}{{source.ID, "Shared"}, {source.ID, "Only source"}, {target.ID, "Shared"}} {
+		if _, err := database.Exec(`INSERT INTO links (source_id, target) VALUES (?, ?);`, link.id, link.target); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := LogReview(database, source.ID, 2, time.Second, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCachedQA(database, target.ID, "factual", "hash", "Q?", "A."); err != nil {
+		t.Fatal(err)
+	}
+
+	target.Content = "Target\n\nSource"
+	target.Tags = []string{"basics", "go"}
+	if err := MergeNotes(database, target, source.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := scanNote(database.QueryRow(`SELECT `+noteColumns+` FROM notes WHERE id = ?;`, source.ID)); err != sql.ErrNoRows {
+		t.Errorf("source still stored: %+v, %v", n, err)
+	}
+	merged := noteByFilename(t, database, "/notes/target.md")
+	if merged.Content != target.Content || !merged.HasTag("go") || !merged.HasTag("basics") {
+		t.Errorf("merged note: content %q, tags %q", merged.Content, merged.Tags)
+	}
+	links, err := GetLinkTargets(database, target.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 2 {
+		t.Errorf("target links = %q, want Only source and Shared", links)
+	}
+	var reviews int
+	if err := database.QueryRow(`SELECT count(*) FROM review_log WHERE note_id = ?;`, target.ID).Scan(&reviews); err != nil {
+		t.Fatal(err)
+	}
+	if reviews != 1 {
+		t.Errorf("target has %d reviews, want the source's one", reviews)
+	}
+	// The target's content changed, so its cached question is stale.
+	if _, _, err := GetCachedQA(database, target.ID, "factual", "hash"); err != sql.ErrNoRows {
+		t.Errorf("cached question survived the merge: %v", err)
+	}
+}
+
+func TestSuspendedAndRetiredNotesAreNeverServed(t *testing.T) {
+	database := openTestDB(t)
+	active := addTestNote(t, database, "/notes/active.md", "# Active", "go")
+	suspended := addTestNote(t, database, "/notes/suspended.md", "# Suspended", "go")
+	retired := addTestNote(t, database, "/notes/retired.md", "# Retired", "go")
+	if err := SetSuspended(database, suspended.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetRetired(database, retired.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	queries := map[string]func() ([]*note.Note, error){
+		"GetDueNotes":             func() ([]*note.Note, error) { return GetDueNotes(database, 10) },
+		"GetDueNotesExcludingNew": func() ([]*note.Note, error) { return GetDueNotesExcludingNew(database, 10) },
+		"GetRandomNotes":          func() ([]*note.Note, error) { return GetRandomNotes(database, 10) },
+		"GetNotesByTag":           func() ([]*note.Note, error) { return GetNotesByTag(database, "go", 10) },
+		"GetDueNotesByTag":        func() ([]*note.Note, error) { return GetDueNotesByTag(database, "GO", 10) },
+	}
+	for _, order := range []ReviewOrder{OrderNew, OrderOld, OrderDue, OrderRandom} {
+		queries["GetDueNoteOrdered "+string(order)] = func() ([]*note.Note, error) {
+			n, err := GetDueNoteOrdered(database, order, false)
+			return []*note.Note{n}, err
+		}
+	}
+	// Mark the active note reviewed so GetDueNotesExcludingNew has something to return.
+	active.State = note.StateReview
+	active.DueDate = time.Now().Add(-time.Hour)
+	if err := UpdateNoteSRS(database, active); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, query := range queries {
+		notes, err := query()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, n := range notes {
+			if n.ID != active.ID {
+				t.Errorf("%s returned %s", name, n.Filename)
+			}
+		}
+	}
+	if count, err := CountDueNotes(database); err != nil || count != 1 {
+		t.Errorf("CountDueNotes = %d, %v; want only the active note", count, err)
+	}
+}
+
+func TestSearchNotesPagesAreDisjoint(t *testing.T) {
+	database := openTestDB(t)
+	want := make(map[int]bool)
+	for i := 0; i < 7; i++ {
+		n := &note.Note{
+			Filename:   fmt.Sprintf("/notes/paged-%d.md", i),
+			Title:      fmt.Sprintf("Paged %d", i%3),
+			Content:    "shared body",
+			CreatedAt:  time.Now(),
+			DueDate:    time.Now(),
+			Interval:   note.DefaultInterval,
+			EaseFactor: note.DefaultEaseFactor,
+		}
+		if _, err := InsertNote(database, n); err != nil {
+			t.Fatal(err)
+		}
+		want[noteByFilename(t, database, n.Filename).ID] = true
+	}
+
+	seen := make(map[int]bool)
+	for offset := 0; offset < len(want); offset += 3 {
+		page, err := SearchNotesPage(database, "shared", 3, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, n := range page {
+			if seen[n.ID] {
+				t.Errorf("%s appears on more than one page", n.Filename)
+			}
+			seen[n.ID] = true
+		}
+	}
+	if len(seen) != len(want) {
+		t.Errorf("pages cover %d notes, want %d", len(seen), len(want))
+	}
+	for id := range want {
+		if !seen[id] {
+			t.Errorf("note %d is on no page", id)
+		}
+	}
+}
+
+func TestReimportKeepsLeechTag(t *testing.T) {
+	database := openTestDB(t)
+	n := addTestNote(t, database, "/notes/leech.md", "# Leech\nbody", "go")
+	n.Lapses = 8
+	n.Tags = append(n.Tags, note.LeechTag)
+	if err := UpdateNoteSRS(database, n); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := &note.Note{
+		Filename:   n.Filename,
+		Title:      "Leech",
+		Tags:       []string{"go", "rewritten"},
+		Content:    "# Leech\nrewritten body",
+		CreatedAt:  n.CreatedAt,
+		DueDate:    time.Now(),
+		Interval:   note.DefaultInterval,
+		EaseFactor: note.DefaultEaseFactor,
+	}
+	if result, err := InsertNote(database, edited); err != nil || result != SyncUpdated {
+		t.Fatalf("re-import = %v, %v; want SyncUpdated", result, err)
+	}
+	stored := noteByFilename(t, database, n.Filename)
+	if !stored.HasTag(note.LeechTag) || !stored.HasTag("rewritten") {
+		t.Errorf("tags after re-import = %v, want the file's tags plus %q", stored.Tags, note.LeechTag)
+	}
+	leeches, err := GetNotesByTag(database, note.LeechTag, -1)
+	if err != nil || len(leeches) != 1 {
+		t.Errorf("GetNotesByTag(leech) = %d notes, %v; want 1", len(leeches), err)
+	}
+	if len(edited.Tags) != 2 {
+		t.Errorf("re-import changed the caller's tags to %v", edited.Tags)
+	}
+}
+
+func TestResolveLinkByFilenameWithAnyExtension(t *testing.T) {
+	database := openTestDB(t)
+	target := addTestNote(t, database, "/notes/Foo.markdown", "# Foo\nbody")
+	addTestNote(t, database, "/notes/foo.bar.md", "# Other\nbody")
+	source := &note.Note{
+		Filename:   "/notes/source.md",
+		Title:      "Source",
+		Content:    "See [[foo]].",
+		Links:      []string{"foo"},
+		CreatedAt:  time.Now(),
+		DueDate:    time.Now(),
+		Interval:   note.DefaultInterval,
+		EaseFactor: note.DefaultEaseFactor,
+	}
+	if _, err := InsertNote(database, source); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range []string{"foo", "FOO", "Foo.markdown"} {
+		got, err := ResolveLink(database, link)
+		if err != nil {
+			t.Fatalf("ResolveLink(%q): %v", link, err)
+		}
+		if got.ID != target.ID {
+			t.Errorf("ResolveLink(%q) = %s, want %s", link, got.Filename, target.Filename)
+		}
+	}
+	if _, err := ResolveLink(database, "fo"); err != sql.ErrNoRows {
+		t.Errorf("ResolveLink(\"fo\") err = %v, want sql.ErrNoRows", err)
+	}
+	if _, err := ResolveLink(database, "f%"); err != sql.ErrNoRows {
+		t.Errorf("ResolveLink(\"f%%\") err = %v, want sql.ErrNoRows", err)
+	}
+
+	backlinks, err := GetBacklinks(database, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backlinks) != 1 || backlinks[0].Filename != source.Filename {
+		t.Errorf("GetBacklinks = %v, want %s", backlinks, source.Filename)
+	}
+}
+
+// addScheduledNote inserts a note created and due the given time from now.
+func addScheduledNote(t *testing.T, database *sql.DB, filename string, created, due time.Duration) *note.Note {
+	t.Helper()
+	n := addTestNote(t, database, filename, "# "+filename+"\nbody")
+	n.CreatedAt = time.Now().Add(created)
+	if _, err := database.Exec(`UPDATE notes SET created_at = ? WHERE id = ?;`, n.CreatedAt, n.ID); err != nil {
+		t.Fatal(err)
+	}
+	n.DueDate = time.Now().Add(due)
+	if err := UpdateNoteSRS(database, n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestGetDueNoteOrdered(t *testing.T) {
+	database := openTestDB(t)
+	day := 24 * time.Hour
+	newest := addScheduledNote(t, database, "/notes/newest.md", -day, -time.Hour)
+	oldest := addScheduledNote(t, database, "/notes/oldest.md", -10*day, -2*time.Hour)
+	overdue := addScheduledNote(t, database, "/notes/overdue.md", -5*day, -5*time.Hour)
+	// Newer and older than all of them, but not due.
+	addScheduledNote(t, database, "/notes/not-due-new.md", 0, time.Hour)
+	addScheduledNote(t, database, "/notes/not-due-old.md", -30*day, 2*time.Hour)
+
+	tests := []struct {
+		order ReviewOrder
+		want  *note.Note
+	}{
+		{OrderNew, newest},
+		{OrderOld, oldest},
+		{OrderDue, overdue},
+	}
+	for _, tt := range tests {
+		got, err := GetDueNoteOrdered(database, tt.order, false)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.order, err)
+		}
+		if got.ID != tt.want.ID {
+			t.Errorf("order %s returned %s, want %s", tt.order, got.Filename, tt.want.Filename)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		got, err := GetDueNoteOrdered(database, OrderRandom, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.DueDate.After(time.Now()) {
+			t.Errorf("order random returned %s, which isn't due", got.Filename)
+		}
+	}
+
+	// Only the oldest note has been reviewed, so it is the only candidate without new notes.
+	oldest.State = note.StateReview
+	if err := UpdateNoteSRS(database, oldest); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetDueNoteOrdered(database, OrderNew, true); err != nil || got.ID != oldest.ID {
+		t.Errorf("excluding new notes returned %v, %v; want %s", got, err, oldest.Filename)
+	}
+}
+
+func TestParseReviewOrder(t *testing.T) {
+	for _, name := range []string{"new", "old", "due", "random", " DUE "} {
+		if _, err := ParseReviewOrder(name); err != nil {
+			t.Errorf("ParseReviewOrder(%q): %v", name, err)
+		}
+	}
+	if _, err := ParseReviewOrder("newest"); err == nil {
+		t.Error("ParseReviewOrder accepted an unknown order")
+	}
+}
+
+// addTitledNote inserts a note with the given title and aliases.
+func addTitledNote(t *testing.T, database *sql.DB, filename, title string, aliases ...string) *note.Note {
+	t.Helper()
+	n := &note.Note{
+		Filename:   filename,
+		Title:      title,
+		Aliases:    aliases,
+		Content:    "# " + title + "\nbody",
+		CreatedAt:  time.Now(),
+		DueDate:    time.Now(),
+		Interval:   note.DefaultInterval,
+		EaseFactor: note.DefaultEaseFactor,
+	}
+	if _, err := InsertNote(database, n); err != nil {
+		t.Fatal(err)
+	}
+	return noteByFilename(t, database, filename)
+}
+
+func TestGetNoteByTitleOrFilenamePrecedence(t *testing.T) {
+	database := openTestDB(t)
+	exact := addTitledNote(t, database, "/notes/go.md", "Go")
+	prefix := addTitledNote(t, database, "/notes/go-channels.md", "Go Channels")
+	addTitledNote(t, database, "/notes/learning-go.md", "Learning Go")
+	alias := addTitledNote(t, database, "/notes/threads.md", "Lightweight threads", "goroutine")
+	addTitledNote(t, database, "/notes/goroutines.md", "Goroutines explained")
+	filename := addTitledNote(t, database, "/notes/tcp-handshake.md", "Three-way handshake")
+
+	tests := []struct {
+		term string
+		want *note.Note
+	}{
+		{"go", exact},          // exact title beats prefix and substring matches
+		{"GO", exact},          // case-insensitive
+		{"goroutine", alias},   // exact alias beats the title prefix "Goroutines explained"
+		{"go chan", prefix},    // title prefix
+		{"channels", prefix},   // title substring
+		{"tcp-hand", filename}, // filename substring
+		{"lightweight", alias}, // title prefix of the aliased note
+	}
+	for _, tt := range tests {
+		got, err := GetNoteByTitleOrFilename(database, tt.term)
+		if err != nil {
+			t.Errorf("%q: %v", tt.term, err)
+			continue
+		}
+		if got.ID != tt.want.ID {
+			t.Errorf("%q matched %q, want %q", tt.term, got.Title, tt.want.Title)
+		}
+	}
+	if _, err := GetNoteByTitleOrFilename(database, "nothing like it"); err != sql.ErrNoRows {
+		t.Errorf("no match: err = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestGetNoteByTitleOrFilenameAmbiguous(t *testing.T) {
+	database := openTestDB(t)
+	first := addTitledNote(t, database, "/notes/a/index.md", "Index")
+	second := addTitledNote(t, database, "/notes/b/index.md", "index")
+	addTitledNote(t, database, "/notes/indexing.md", "Indexing")
+
+	_, err := GetNoteByTitleOrFilename(database, "index")
+	var ambiguous *AmbiguousMatchError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("err = %v, want an *AmbiguousMatchError", err)
+	}
+	ids := []int{ambiguous.Matches[0].ID, ambiguous.Matches[1].ID}
+	slices.Sort(ids)
+	if len(ambiguous.Matches) != 2 || !slices.Equal(ids, []int{first.ID, second.ID}) {
+		t.Errorf("matches = %d notes, want the two exact titles", len(ambiguous.Matches))
+	}
+	if ambiguous.Term != "index" {
+		t.Errorf("Term = %q, want %q", ambiguous.Term, "index")
+	}
+
+	// A better match than the tie is not ambiguous.
+	if got, err := GetNoteByTitleOrFilename(database, "indexing"); err != nil || got.Title != "Indexing" {
+		t.Errorf("indexing = %v, %v; want the Indexing note", got, err)
+	}
+}
+
+func TestFindRelatedNotesRanksByOverlap(t *testing.T) {
+	database := openTestDB(t)
+	n := addTestNote(t, database, "/notes/subject.md", "body", "go", "concurrency", "Networking")
+	two := addTestNote(t, database, "/notes/two.md", "body", "go", "concurrency")
+	three := addTestNote(t, database, "/notes/three.md", "body", "GO", "concurrency", "networking")
+	oneB := addTestNote(t, database, "/notes/one-b.md", "body", "go")
+	oneA := addTestNote(t, database, "/notes/one-a.md", "body", "networking", "unrelated")
+	addTestNote(t, database, "/notes/none.md", "body", "cooking")
+
+	related, err := FindRelatedNotes(database, n, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for _, r := range related {
+		got = append(got, r.ID)
+	}
+	// Most shared tags first, ties by title; the subject and unrelated notes are left out.
+	if want := []int{three.ID, two.ID, oneA.ID, oneB.ID}; !slices.Equal(got, want) {
+		t.Errorf("related = %v, want %v", got, want)
+	}
+
+	if limited, err := FindRelatedNotes(database, n, 2); err != nil || len(limited) != 2 || limited[0].ID != three.ID {
+		t.Errorf("limit 2 returned %d notes, %v; want the top two", len(limited), err)
+	}
+}
+
+func TestFindRelatedNotesCountsLinks(t *testing.T) {
+	database := openTestDB(t)
+	n := addTestNote(t, database, "/notes/subject.md", "body", "go")
+	tagged := addTestNote(t, database, "/notes/tagged.md", "body", "go")
+	linked := addTestNote(t, database, "/notes/linked.md", "body", "go")
+	if _, err := database.Exec(`INSERT INTO links (source_id, target) VALUES (?, 'linked'), (?, 'subject');`, n.ID, linked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	related, err := FindRelatedNotes(database, n, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(related) != 2 || related[0].ID != linked.ID || related[1].ID != tagged.ID {
+		t.Errorf("related = %v, want the note linked both ways before the one sharing only a tag", related)
+	}
+}
+
+func TestGetDueNotesWeightedFavorsOverdueNotes(t *testing.T) {
+	database := openTestDB(t)
+	overdue := make(map[int]bool)
+	barelyDue := make(map[int]bool)
+	for i := 0; i < 4; i++ {
+		n := addScheduledNote(t, database, fmt.Sprintf("/notes/overdue-%d.md", i), -60*24*time.Hour, -time.Duration(30+i)*24*time.Hour)
+		overdue[n.ID] = true
+		n = addScheduledNote(t, database, fmt.Sprintf("/notes/barely-%d.md", i), -60*24*time.Hour, -time.Duration(i+1)*time.Minute)
+		barelyDue[n.ID] = true
+	}
+	addScheduledNote(t, database, "/notes/not-due.md", -60*24*time.Hour, time.Hour)
+
+	seen := make(map[int]int)
+	overdueDraws, barelyDueDraws := 0, 0
+	for run := 0; run < 200; run++ {
+		notes, err := GetDueNotesWeighted(database, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(notes) != 3 {
+			t.Fatalf("got %d notes, want 3", len(notes))
+		}
+		for _, n := range notes {
+			seen[n.ID]++
+			switch {
+			case overdue[n.ID]:
+				overdueDraws++
+			case barelyDue[n.ID]:
+				barelyDueDraws++
+			default:
+				t.Fatalf("returned %s, which isn't due", n.Filename)
+			}
+		}
+	}
+	// The groups are the same size, so on average each very overdue note should be drawn far more often.
+	if float64(overdueDraws) <= 1.5*float64(barelyDueDraws) {
+		t.Errorf("very overdue notes drawn %d times, barely due ones %d; want overdue notes far more often", overdueDraws, barelyDueDraws)
+	}
+	if least := noteByFilename(t, database, "/notes/barely-0.md"); seen[least.ID] != 0 {
+		t.Errorf("the least overdue note was drawn %d times, but it is outside the most overdue 2×limit", seen[least.ID])
+	}
+	for id := range overdue {
+		if seen[id] == 0 {
+			t.Errorf("overdue note %d was never drawn", id)
+		}
+	}
+}
+
+func TestRetentionByWeek(t *testing.T) {
+	database := openTestDB(t)
+	n := addTestNote(t, database, "/notes/reviewed.md", "body")
+	day := func(d, hour int) time.Time { return time.Date(2026, time.January, d, hour, 0, 0, 0, time.Local) }
+	reviews := []struct {
+		at     time.Time
+		rating int
+	}{
+		{day(5, 9), 2},   // Monday
+		{day(7, 20), 1},  // Wednesday
+		{day(11, 23), 3}, // Sunday, still the same week
+		{day(12, 0), 1},  // the next Monday
+		{day(12, 8), 1},
+		{day(14, 8), 2},
+		{day(26, 8), 2}, // a week later, after one with no reviews
+	}
+	for _, r := range reviews {
+		if err := LogReview(database, n.ID, r.rating, time.Second, r.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	weeks, err := RetentionByWeek(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		start           time.Time
+		again, recalled int
+	}{
+		{day(5, 0), 1, 2},
+		{day(12, 0), 2, 1},
+		{day(26, 0), 0, 1},
+	}
+	if len(weeks) != len(want) {
+		t.Fatalf("got %d weeks, want %d: %+v", len(weeks), len(want), weeks)
+	}
+	for i, w := range want {
+		got := weeks[i]
+		if !got.WeekStart.Equal(w.start) || got.Again != w.again || got.Recalled != w.recalled {
+			t.Errorf("week %d = %v again %d recalled %d, want %v again %d recalled %d", i, got.WeekStart, got.Again, got.Recalled, w.start, w.again, w.recalled)
+		}
+	}
+	if r := weeks[0].Retention(); math.Abs(r-2.0/3) > 1e-9 {
+		t.Errorf("first week retention = %v, want 2/3", r)
+	}
+	if r := (WeekStat{}).Retention(); r != 0 {
+		t.Errorf("retention of a week without reviews = %v, want 0", r)
+	}
+}
+
+func TestInsertNoteReportsSyncResult(t *testing.T) {
+	database := openTestDB(t)
+	n := &note.Note{
+		Filename:   "/notes/sync.md",
+		Title:      "Sync",
+		Content:    "# Sync\nbody",
+		CreatedAt:  time.Now(),
+		DueDate:    time.Now(),
+		Interval:   note.DefaultInterval,
+		EaseFactor: note.DefaultEaseFactor,
+	}
+	steps := []struct {
+		content string
+		want    SyncResult
+	}{
+		{"# Sync\nbody", SyncAdded},
+		{"# Sync\nbody", SyncUnchanged},
+		{"# Sync\nedited body", SyncUpdated},
+		{"# Sync\nedited body", SyncUnchanged},
+	}
+	for i, step := range steps {
+		n.Content = step.content
+		if got, err := InsertNote(database, n); err != nil || got != step.want {
+			t.Errorf("import %d = %v, %v; want %v", i+1, got, err, step.want)
+		}
+	}
+}
+
+func TestInsertNotesTxRollsBackOnFailure(t *testing.T) {
+	database := openTestDB(t)
+	existing := addTestNote(t, database, "/notes/existing.md", "original body")
+	// Make inserting one particular note fail partway through the batch.
+	if _, err := database.Exec(`CREATE TRIGGER fail_bad_note BEFORE INSERT ON notes WHEN NEW.filename = '/notes/bad.md' BEGIN SELECT RAISE(ABORT, 'injected failure'); END;`); err != nil {
+		t.Fatal(err)
+	}
+	batchNote := func(filename, content string) *note.Note {
+		return &note.Note{
+			Filename:   filename,
+			Title:      filepath.Base(filename),
+			Content:    content,
+			CreatedAt:  time.Now(),
+			DueDate:    time.Now(),
+			Interval:   note.DefaultInterval,
+			EaseFactor: note.DefaultEaseFactor,
+			Links:      []string{"existing"},
+		}
+	}
+	batch := []*note.Note{
+		batchNote(existing.Filename, "edited body"),
+		batchNote("/notes/added.md", "new body"),
+		batchNote("/notes/bad.md", "fails"),
+		batchNote("/notes/after.md", "never reached"),
+	}
+
+	if _, err := InsertNotesTx(database, batch); err == nil {
+		t.Fatal("InsertNotesTx succeeded despite the failing note")
+	}
+	if got := noteByFilename(t, database, existing.Filename).Content; got != "original body" {
+		t.Errorf("the update before the failure was kept: content = %q", got)
+	}
+	var notes, links int
+	if err := database.QueryRow(`SELECT count(*) FROM notes;`).Scan(&notes); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.QueryRow(`SELECT count(*) FROM links;`).Scan(&links); err != nil {
+		t.Fatal(err)
+	}
+	if notes != 1 || links != 0 {
+		t.Errorf("after the rollback there are %d notes and %d links, want only the original note", notes, links)
+	}
+}
+
+func TestDailyStatsResetAtMidnight(t *testing.T) {
+	database := openTestDB(t)
+	midnight := time.Date(2026, 3, 14, 0, 0, 0, 0, time.Local)
+	lateNight, earlyMorning := midnight.Add(-time.Minute), midnight.Add(time.Minute)
+	for _, wasNew := range []bool{true, false, true} {
+		if err := RecordReview(database, lateNight, wasNew); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RecordReview(database, earlyMorning, false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		at          time.Time
+		wantReviews int
+		wantNew     int
+	}{
+		{lateNight, 3, 2},
+		{midnight.Add(-20 * time.Hour), 3, 2},
+		{earlyMorning, 1, 0},
+		{midnight.Add(23 * time.Hour), 1, 0},
+		{midnight.Add(48 * time.Hour), 0, 0},
+	}
+	for _, tt := range tests {
+		reviews, newCards, err := GetDailyStats(database, tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reviews != tt.wantReviews || newCards != tt.wantNew {
+			t.Errorf("GetDailyStats(%s) = %d reviews, %d new; want %d, %d", tt.at.Format(time.DateTime), reviews, newCards, tt.wantReviews, tt.wantNew)
+		}
+	}
+}
+
+// reviewNote moves a note well into its schedule, as reviews would.
+func reviewNote(t *testing.T, database *sql.DB, n *note.Note) {
+	t.Helper()
+	n.Interval, n.EaseFactor, n.DueDate = 30, 2.9, time.Now().AddDate(0, 0, 30)
+	n.State, n.LearningStep = note.StateReview, 2
+	if err := UpdateNoteSRS(database, n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkReset reports whether a note is back at a freshly imported schedule.
+func checkReset(t *testing.T, database *sql.DB, filename string, want bool) {
+	t.Helper()
+	n := noteByFilename(t, database, filename)
+	isReset := n.Interval == note.DefaultInterval && n.EaseFactor == note.DefaultEaseFactor &&
+		n.State == note.StateNew && n.LearningStep == 0 && !n.DueDate.After(time.Now())
+	if isReset != want {
+		t.Errorf("%s: interval %v, ease %v, state %q, step %d, due %s; reset = %v, want %v",
+			filename, n.Interval, n.EaseFactor, n.State, n.LearningStep, n.DueDate.Format(time.DateTime), isReset, want)
+	}
+}
+
+func TestResetSRS(t *testing.T) {
+	database := openTestDB(t)
+	target := addTestNote(t, database, "/notes/target.md", "target")
+	other := addTestNote(t, database, "/notes/other.md", "other")
+	reviewNote(t, database, target)
+	reviewNote(t, database, other)
+
+	if err := ResetSRS(database, target.ID); err != nil {
+		t.Fatal(err)
+	}
+	checkReset(t, database, "/notes/target.md", true)
+	checkReset(t, database, "/notes/other.md", false)
+}
+
+func TestResetSRSByTag(t *testing.T) {
+	database := openTestDB(t)
+	for _, n := range []*note.Note{
+		addTestNote(t, database, "/notes/go.md", "go", "Go"),
+		addTestNote(t, database, "/notes/both.md", "both", "rust", "go"),
+		addTestNote(t, database, "/notes/rust.md", "rust", "rust"),
+		addTestNote(t, database, "/notes/gopher.md", "gopher", "gopher"),
+	} {
+		reviewNote(t, database, n)
+	}
+
+	count, err := ResetSRSByTag(database, "go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("ResetSRSByTag(go) reset %d notes, want 2", count)
+	}
+	checkReset(t, database, "/notes/go.md", true)
+	checkReset(t, database, "/notes/both.md", true)
+	checkReset(t, database, "/notes/rust.md", false)
+	checkReset(t, database, "/notes/gopher.md", false)
+}
+
+func TestResetAllSRS(t *testing.T) {
+	database := openTestDB(t)
+	filenames := []string{"/notes/a.md", "/notes/b.md", "/notes/c.md"}
+	for _, filename := range filenames {
+		reviewNote(t, database, addTestNote(t, database, filename, filename))
+	}
+
+	count, err := ResetAllSRS(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("ResetAllSRS() reset %d notes, want 3", count)
+	}
+	for _, filename := range filenames {
+		checkReset(t, database, filename, true)
+	}
+}
@@ -0,0 +1,131 @@
+// Package db handles all database interactions for Neuron CLI.
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migration is one incremental schema change.
+type migration struct {
+	description string
+	apply       func(tx *sql.Tx) error
+}
+
+// migrations are applied in order, and each one exactly once. The number of applied
+// migrations is stored in PRAGMA user_version. Append new steps to the end; never
+// reorder or edit a step that has shipped.
+//
+// Databases created before versioning started have user_version 0 but may already
+// contain some of these columns, so column additions go through ensureColumn and
+// table creation uses IF NOT EXISTS.
+var migrations = []migration{
+	{"create notes table", execStep(`CREATE TABLE IF NOT EXISTS notes (id INTEGER PRIMARY KEY, filename TEXT NOT NULL UNIQUE, title TEXT NOT NULL, tags TEXT, content TEXT NOT NULL, created_at TIMESTAMP, due_date TIMESTAMP NOT NULL, interval REAL, ease_factor REAL);`)},
+	{"add notes.aliases", addColumnStep("notes", "aliases", "TEXT")},
+	{"add notes.modified_at", addColumnStep("notes", "modified_at", "TIMESTAMP")},
+	{"add notes.content_hash", addColumnStep("notes", "content_hash", "TEXT")},
+	{"create qa_cache table", execStep(`CREATE TABLE IF NOT EXISTS qa_cache (note_id INTEGER NOT NULL, question_type TEXT NOT NULL, content_hash TEXT NOT NULL, question TEXT NOT NULL, answer TEXT NOT NULL, created_at TIMESTAMP NOT NULL, PRIMARY KEY (note_id, question_type));`)},
+	{"create links table", execStep(`CREATE TABLE IF NOT EXISTS links (source_id INTEGER NOT NULL, target TEXT NOT NULL, PRIMARY KEY (source_id, target));`)},
+	{"create daily_stats table", execStep(`CREATE TABLE IF NOT EXISTS daily_stats (date TEXT PRIMARY KEY, reviews_done INTEGER NOT NULL DEFAULT 0, new_done INTEGER NOT NULL DEFAULT 0);`)},
+	{"add notes.lapses", addColumnStep("notes", "lapses", "INTEGER NOT NULL DEFAULT 0")},
+	{"add notes.suspended", addColumnStep("notes", "suspended", "INTEGER NOT NULL DEFAULT 0")},
+	{"create meta table", execStep(`CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT NOT NULL);`)},
+	{"add notes.state", addColumnStep("notes", "state", "TEXT NOT NULL DEFAULT 'new'")},
+	{"mark reviewed notes as graduated", execStep(`UPDATE notes SET state = 'review' WHERE interval > 1.0;`)},
+	{"add notes.learning_step", addColumnStep("notes", "learning_step", "INTEGER NOT NULL DEFAULT 0")},
+	{"create review_log table", execStep(`CREATE TABLE IF NOT EXISTS review_log (id INTEGER PRIMARY KEY, note_id INTEGER NOT NULL, reviewed_at TIMESTAMP NOT NULL, rating INTEGER NOT NULL, think_ms INTEGER);`)},
+	{"add notes.question_prompt", addColumnStep("notes", "question_prompt", "TEXT NOT NULL DEFAULT ''")},
+	{"add notes.retired", addColumnStep("notes", "retired", "INTEGER NOT NULL DEFAULT 0")},
+	// SQLite can't add a constraint to an existing table, so these are rebuilt,
+	// dropping any rows already orphaned by a deleted note.
+	{"cascade note deletes to qa_cache, links and review_log", execStep(`
+		CREATE TABLE qa_cache_new (note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE, question_type TEXT NOT NULL, content_hash TEXT NOT NULL, question TEXT NOT NULL, answer TEXT NOT NULL, created_at TIMESTAMP NOT NULL, PRIMARY KEY (note_id, question_type));
+		INSERT INTO qa_cache_new SELECT note_id, question_type, content_hash, question, answer, created_at FROM qa_cache WHERE note_id IN (SELECT id FROM notes);
+		DROP TABLE qa_cache;
+		ALTER TABLE qa_cache_new RENAME TO qa_cache;
+		CREATE TABLE links_new (source_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE, target TEXT NOT NULL, PRIMARY KEY (source_id, target));
+		INSERT INTO links_new SELECT source_id, target FROM links WHERE source_id IN (SELECT id FROM notes);
+		DROP TABLE links;
+		ALTER TABLE links_new RENAME TO links;
+		CREATE TABLE review_log_new (id INTEGER PRIMARY KEY, note_id INTEGER NOT NULL REFERENCES notes(id) ON DELETE CASCADE, reviewed_at TIMESTAMP NOT NULL, rating INTEGER NOT NULL, think_ms INTEGER);
+		INSERT INTO review_log_new SELECT id, note_id, reviewed_at, rating, think_ms FROM review_log WHERE note_id IN (SELECT id FROM notes);
+		DROP TABLE review_log;
+		ALTER TABLE review_log_new RENAME TO review_log;`)},
+}
+
+// migrate brings the schema up to date, running each pending migration in its own transaction.
+func migrate(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version;`).Scan(&version); err != nil {
+		return fmt.Errorf("could not read schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this version of neuron supports (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		if err := applyMigration(db, i+1, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", i+1, migrations[i].description, err)
+		}
+	}
+	return nil
+}
+
+// applyMigration runs one migration and records the new version atomically.
+func applyMigration(db *sql.DB, version int, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := m.apply(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// PRAGMA doesn't accept bound parameters.
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d;", version)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// execStep returns a migration step that runs a single statement.
+func execStep(statement string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(statement)
+		return err
+	}
+}
+
+// addColumnStep returns a migration step that adds a column unless it already exists.
+func addColumnStep(table, column, definition string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		return ensureColumn(tx, table, column, definition)
+	}
+}
+
+// ensureColumn adds a column to an existing table if it is missing.
+func ensureColumn(tx *sql.Tx, table, column, definition string) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s);", table))
+	if err != nil {
+		return err
+	}
+	found := false
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		if name == column {
+			found = true
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || found {
+		return err
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, column, definition))
+	return err
+}
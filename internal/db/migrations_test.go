@@ -0,0 +1,188 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// openUnmigratedDB returns an empty database in a temporary directory.
+func openUnmigratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+	database, err := sql.Open("sqlite3", dsn(filepath.Join(t.TempDir(), "neuron.db"), DefaultOptions()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func schemaVersion(t *testing.T, database *sql.DB) int {
+	t.Helper()
+	var version int
+	if err := database.QueryRow(`PRAGMA user_version;`).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func TestMigrateUpgradesAnUnversionedDatabase(t *testing.T) {
+	database := openUnmigratedDB(t)
+	// The schema from before versioning, which already had the aliases column.
+	due := time.Now().Add(-time.Hour)
+	if _, err := database.Exec(`CREATE TABLE notes (id INTEGER PRIMARY KEY, filename TEXT NOT NULL UNIQUE, title TEXT NOT NULL, tags TEXT, content TEXT NOT NULL, created_at TIMESTAMP, due_date TIMESTAMP NOT NULL, interval REAL, ease_factor REAL, aliases TEXT);`); err != nil {
+		t.Fatal(err)
+	}
+	insert := `INSERT INTO notes (filename, title, tags, content, created_at, due_date, interval, ease_factor, aliases) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+	if _, err := database.Exec(insert, "/notes/new.md", "new.md", `["go"]`, "# New", due, due, 1.0, 2.5, `["fresh"]`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := database.Exec(insert, "/notes/old.md", "old.md", `[]`, "# Old", due, due, 12.0, 2.1, `[]`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := migrate(database); err != nil {
+		t.Fatal(err)
+	}
+	if got := schemaVersion(t, database); got != len(migrations) {
+		t.Errorf("schema version = %d, want %d", got, len(migrations))
+	}
+
+	fresh := noteByFilename(t, database, "/notes/new.md")
+	if fresh.Content != "# New" || len(fresh.Aliases) != 1 || fresh.Aliases[0] != "fresh" || !fresh.HasTag("go") {
+		t.Errorf("existing note changed: %+v", fresh)
+	}
+	if fresh.State != note.StateNew || fresh.Lapses != 0 || fresh.LearningStep != 0 || fresh.QuestionPrompt != "" {
+		t.Errorf("new columns: state %q, lapses %d, step %d, prompt %q; want the defaults",
+			fresh.State, fresh.Lapses, fresh.LearningStep, fresh.QuestionPrompt)
+	}
+	old := noteByFilename(t, database, "/notes/old.md")
+	if old.State != note.StateReview || old.Interval != 12 || old.EaseFactor != 2.1 {
+		t.Errorf("reviewed note: state %q, interval %g, ease %g; want review, 12, 2.1", old.State, old.Interval, old.EaseFactor)
+	}
+	for _, table := range []string{"qa_cache", "links", "daily_stats", "meta", "review_log"} {
+		if _, err := database.Exec(`SELECT count(*) FROM ` + table + `;`); err != nil {
+			t.Errorf("table %s: %v", table, err)
+		}
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	database := openTestDB(t)
+	addTestNote(t, database, "/notes/a.md", "# A")
+	if err := migrate(database); err != nil {
+		t.Fatal(err)
+	}
+	if got := noteByFilename(t, database, "/notes/a.md").Content; got != "# A" {
+		t.Errorf("content after a second migrate = %q", got)
+	}
+}
+
+func TestMigrateRefusesANewerSchema(t *testing.T) {
+	database := openUnmigratedDB(t)
+	if _, err := database.Exec(`PRAGMA user_version = 1000;`); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrate(database); err == nil {
+		t.Error("migrate accepted a schema newer than it knows")
+	}
+}
+
+// rowCount returns how many rows of table reference noteID through column.
+func rowCount(t *testing.T, database *sql.DB, table, column string, noteID int) int {
+	t.Helper()
+	var count int
+	if err := database.QueryRow(`SELECT count(*) FROM `+table+` WHERE `+column+` = ?;`, noteID).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestDeletingANoteCascades(t *testing.T) {
+	database := openTestDB(t)
+	n := addTestNote(t, database, "/notes/gone.md", "# Gone\n[[Other]]")
+	if _, err := database.Exec(`INSERT OR IGNORE INTO links (source_id, target) VALUES (?, 'Other');`, n.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCachedQA(database, n.ID, "factual", "hash", "Q?", "A."); err != nil {
+		t.Fatal(err)
+	}
+	if err := LogReview(database, n.ID, 2, 0, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := database.Exec(`DELETE FROM notes WHERE id = ?;`, n.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, ref := range []struct{ table, column string }{
+		{"links", "source_id"},
+		{"qa_cache", "note_id"},
+		{"review_log", "note_id"},
+	} {
+		if got := rowCount(t, database, ref.table, ref.column, n.ID); got != 0 {
+			t.Errorf("%s kept %d rows of the deleted note", ref.table, got)
+		}
+	}
+}
+
+func TestMigrateDropsOrphanedRows(t *testing.T) {
+	database := openUnmigratedDB(t)
+	// Migrate up to just before the foreign keys were added, then orphan some rows.
+	for i, m := range migrations[:len(migrations)-1] {
+		if err := applyMigration(database, i+1, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	n := addTestNote(t, database, "/notes/kept.md", "# Kept")
+	for _, id := range []int{n.ID, n.ID + 100} {
+		if _, err := database.Exec(`INSERT INTO review_log (note_id, reviewed_at, rating) VALUES (?, ?, 2);`, id, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := migrate(database); err != nil {
+		t.Fatal(err)
+	}
+	if got := rowCount(t, database, "review_log", "note_id", n.ID); got != 1 {
+		t.Errorf("review_log has %d rows for the kept note, want 1", got)
+	}
+	if got := rowCount(t, database, "review_log", "note_id", n.ID+100); got != 0 {
+		t.Errorf("review_log kept %d orphaned rows", got)
+	}
+}
+
+func TestConnectionOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     Options
+		journalMode string
+		foreignKeys int
+	}{
+		{"defaults", DefaultOptions(), "wal", 1},
+		{"both off", Options{BusyTimeout: 100}, "delete", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			database, err := sql.Open("sqlite3", dsn(filepath.Join(t.TempDir(), "neuron.db"), tt.options))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer database.Close()
+
+			var journalMode string
+			var foreignKeys int
+			if err := database.QueryRow(`PRAGMA journal_mode;`).Scan(&journalMode); err != nil {
+				t.Fatal(err)
+			}
+			if err := database.QueryRow(`PRAGMA foreign_keys;`).Scan(&foreignKeys); err != nil {
+				t.Fatal(err)
+			}
+			if journalMode != tt.journalMode || foreignKeys != tt.foreignKeys {
+				t.Errorf("journal_mode %s, foreign_keys %d; want %s, %d", journalMode, foreignKeys, tt.journalMode, tt.foreignKeys)
+			}
+		})
+	}
+}
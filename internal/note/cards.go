@@ -0,0 +1,121 @@
+// Package note defines the core data structure for a note and its parser.
+package note
+
+import (
+	"strings"
+)
+
+// Card is an explicit question/answer pair written in a note, reviewed as-is
+// instead of asking the LLM for a question.
+type Card struct {
+	Front string `json:"front"`
+	Back  string `json:"back"`
+}
+
+// cardsHeading is the section whose "- front :: back" list items are cards.
+const cardsHeading = "cards"
+
+// ExtractCards returns the explicit cards in content, in order. Two conventions
+// are recognized outside fenced code blocks:
+//
+//	Q: What does TCP guarantee?
+//	A: Ordered, reliable delivery.
+//
+// where lines following a Q: or A: line continue it until a blank line, and
+// "- front :: back" list items inside a "## Cards" section.
+func ExtractCards(content string) []Card {
+	var cards []Card
+	var current *Card
+	inBack := false
+	inCards := false
+	inFence := false
+
+	finish := func() {
+		if current != nil && current.Front != "" && current.Back != "" {
+			cards = append(cards, *current)
+		}
+		current = nil
+		inBack = false
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			finish()
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if isTopHeading(trimmed) {
+			finish()
+			inCards = isSectionHeading(trimmed, cardsHeading)
+			continue
+		}
+
+		if front, ok := cutLabel(trimmed, "Q:"); ok {
+			finish()
+			current = &Card{Front: front}
+			continue
+		}
+		if back, ok := cutLabel(trimmed, "A:"); ok && current != nil && !inBack {
+			current.Back = back
+			inBack = true
+			continue
+		}
+		if current != nil {
+			if trimmed == "" {
+				if inBack {
+					finish()
+				}
+				continue
+			}
+			if inBack {
+				current.Back = joinText(current.Back, trimmed)
+			} else {
+				current.Front = joinText(current.Front, trimmed)
+			}
+			continue
+		}
+
+		if inCards {
+			if item, ok := cutListItem(trimmed); ok {
+				if front, back, found := strings.Cut(item, "::"); found {
+					front, back = strings.TrimSpace(front), strings.TrimSpace(back)
+					if front != "" && back != "" {
+						cards = append(cards, Card{Front: front, Back: back})
+					}
+				}
+			}
+		}
+	}
+	finish()
+	return cards
+}
+
+// cutLabel returns the text after a case-insensitive label such as "Q:".
+func cutLabel(line, label string) (string, bool) {
+	if len(line) < len(label) || !strings.EqualFold(line[:len(label)], label) {
+		return "", false
+	}
+	return strings.TrimSpace(line[len(label):]), true
+}
+
+// cutListItem returns the text of a "- " or "* " list item.
+func cutListItem(line string) (string, bool) {
+	for _, bullet := range []string{"- ", "* "} {
+		if item, ok := strings.CutPrefix(line, bullet); ok {
+			return strings.TrimSpace(item), true
+		}
+	}
+	return "", false
+}
+
+// joinText appends a continuation line to a card side.
+func joinText(text, line string) string {
+	if text == "" {
+		return line
+	}
+	return text + "\n" + line
+}
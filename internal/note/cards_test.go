@@ -0,0 +1,77 @@
+package note
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractCards(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Card
+	}{
+		{
+			"question and answer",
+			"Q: What does TCP guarantee?\nA: Ordered, reliable delivery.\n",
+			[]Card{{"What does TCP guarantee?", "Ordered, reliable delivery."}},
+		},
+		{
+			"labels ignore case",
+			"q: Front\na: Back\n",
+			[]Card{{"Front", "Back"}},
+		},
+		{
+			"continuation lines until a blank line",
+			"Q: Name the layers\nof the OSI model.\nA: Physical, data link,\nnetwork, ...\n\nNot part of the card.\n",
+			[]Card{{"Name the layers\nof the OSI model.", "Physical, data link,\nnetwork, ..."}},
+		},
+		{
+			"several cards",
+			"Q: One?\nA: 1\n\nQ: Two?\nA: 2\n",
+			[]Card{{"One?", "1"}, {"Two?", "2"}},
+		},
+		{
+			"a new question ends the previous card",
+			"Q: One?\nA: 1\nQ: Two?\nA: 2\n",
+			[]Card{{"One?", "1"}, {"Two?", "2"}},
+		},
+		{
+			"question without an answer",
+			"Q: Unanswered?\n\nQ: Answered?\nA: Yes\n",
+			[]Card{{"Answered?", "Yes"}},
+		},
+		{
+			"answer without a question",
+			"A: Orphan\n",
+			nil,
+		},
+		{
+			"double colon items in the cards section",
+			"# Note\n\n## Cards\n- TCP :: reliable\n* UDP :: unreliable\n- not a card\n- :: no front\n",
+			[]Card{{"TCP", "reliable"}, {"UDP", "unreliable"}},
+		},
+		{
+			"double colon items outside the cards section",
+			"## Notes\n- TCP :: reliable\n\n## Cards\n- UDP :: unreliable\n\n## Later\n- IP :: addressing\n",
+			[]Card{{"UDP", "unreliable"}},
+		},
+		{
+			"both conventions",
+			"Q: Front\nA: Back\n\n## Cards\n- one :: 1\n",
+			[]Card{{"Front", "Back"}, {"one", "1"}},
+		},
+		{
+			"fenced code is skipped",
+			"```\nQ: In code\nA: Ignored\n```\n## Cards\n~~~\n- a :: b\n~~~\n",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractCards(tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("ExtractCards = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
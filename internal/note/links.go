@@ -0,0 +1,35 @@
+// Package note defines the core data structure for a note and its parser.
+package note
+
+import (
+	"regexp"
+	"strings"
+)
+
+// wikilinkPattern matches Obsidian-style [[Target]], [[Target|display]], [[Target#Heading]]
+// and embedded ![[Target]] links. The first group is everything inside the brackets.
+var wikilinkPattern = regexp.MustCompile(`!?\[\[([^\[\]]+)\]\]`)
+
+// ExtractLinks returns the unique wikilink targets in content, in order of first appearance.
+// Display text ("|display") and heading or block anchors ("#Heading", "#^block") are dropped,
+// so [[Note|see here]] and [[Note#Intro]] both yield "Note".
+func ExtractLinks(content string) []string {
+	var links []string
+	seen := make(map[string]bool)
+	for _, match := range wikilinkPattern.FindAllStringSubmatch(content, -1) {
+		target := match[1]
+		if i := strings.Index(target, "|"); i >= 0 {
+			target = target[:i]
+		}
+		if i := strings.Index(target, "#"); i >= 0 {
+			target = target[:i]
+		}
+		target = strings.TrimSpace(target)
+		if target == "" || seen[strings.ToLower(target)] {
+			continue
+		}
+		seen[strings.ToLower(target)] = true
+		links = append(links, target)
+	}
+	return links
+}
@@ -0,0 +1,33 @@
+package note
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"plain link", "See [[Note]].", []string{"Note"}},
+		{"display text", "See [[Note|this note]].", []string{"Note"}},
+		{"embed", "![[Note]]", []string{"Note"}},
+		{"embedded image with display", "![[diagram.png|300]]", []string{"diagram.png"}},
+		{"heading anchor", "[[Note#Intro]]", []string{"Note"}},
+		{"block anchor with display", "[[Note#^abc123|quote]]", []string{"Note"}},
+		{"duplicates ignore case", "[[Note]] [[note|again]] ![[NOTE]]", []string{"Note"}},
+		{"order of first appearance", "[[B]] [[A]] [[B]]", []string{"B", "A"}},
+		{"surrounding spaces trimmed", "[[ Note | display ]]", []string{"Note"}},
+		{"anchor only", "[[#Intro]]", nil},
+		{"no links", "[single] [brackets]", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractLinks(tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("ExtractLinks(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
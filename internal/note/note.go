@@ -1,19 +1,81 @@
 // Package note defines the core data structure for a note and its parser.
 package note
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Scheduling values for a note that has never been reviewed.
+const (
+	DefaultInterval   = 1.0
+	DefaultEaseFactor = 2.5
+)
+
+// Schedule states of a note.
+const (
+	StateNew      = "new"      // never reviewed
+	StateLearning = "learning" // working through the learning steps
+	StateReview   = "review"   // graduated to intervals measured in days
+)
+
+// LeechTag marks notes that keep being forgotten and probably need rewriting.
+// Reviews add it; it lives only in the database, so imports keep it.
+const LeechTag = "leech"
 
 // Note represents a single markdown note from your Zettelkasten.
 type Note struct {
-	ID        int       `db:"id"`
-	Filename  string    `db:"filename"`
-	Title     string    `db:"title"`
-	Tags      []string  // Stored as JSON string in DB
-	Content   string    `db:"content"`
-	CreatedAt time.Time `db:"created_at"`
+	ID         int       `db:"id" json:"id"`
+	Filename   string    `db:"filename" json:"filename"`
+	Title      string    `db:"title" json:"title"`
+	Tags       []string  `json:"tags"`    // Stored as JSON string in DB
+	Aliases    []string  `json:"aliases"` // Alternate names from frontmatter, stored as JSON string in DB
+	Content    string    `db:"content" json:"content"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	ModifiedAt time.Time `db:"modified_at" json:"modified_at"` // File modification time at the last import
+	Links      []string  `json:"-"`                            // Wikilink targets found by the parser, stored in the links table
+	Cards      []Card    `json:"cards,omitempty"`              // Explicit Q:/A: and "front :: back" cards, parsed from Content
+
+	// QuestionPrompt, from the question_prompt frontmatter field, replaces the
+	// built-in question prompts for this note. The note's material is appended to it.
+	QuestionPrompt string `db:"question_prompt" json:"question_prompt,omitempty"`
 
 	// Fields for Spaced Repetition
-	DueDate    time.Time `db:"due_date"`
-	Interval   float64   `db:"interval"`
-	EaseFactor float64   `db:"ease_factor"`
+	DueDate    time.Time `db:"due_date" json:"due_date"`
+	Interval   float64   `db:"interval" json:"interval"`
+	EaseFactor float64   `db:"ease_factor" json:"ease_factor"`
+	Lapses     int       `db:"lapses" json:"lapses"`       // Times the note was rated "Again"
+	Suspended  bool      `db:"suspended" json:"suspended"` // Suspended notes are left out of reviews
+	Retired    bool      `db:"retired" json:"retired"`     // Retired notes are mastered and never come up again
+
+	State        string `db:"state" json:"state"`                 // StateNew, StateLearning or StateReview
+	LearningStep int    `db:"learning_step" json:"learning_step"` // Index into the learning steps while learning
+}
+
+// HasTag reports whether the note carries tag (case-insensitive).
+func (n *Note) HasTag(tag string) bool {
+	for _, t := range n.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags appends the tags the note doesn't already carry, skipping blanks.
+func (n *Note) AddTags(tags ...string) {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !n.HasTag(tag) {
+			n.Tags = append(n.Tags, tag)
+		}
+	}
+}
+
+// ContentHash fingerprints note content so unchanged notes can be detected cheaply.
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,40 @@
+package note
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAddTags(t *testing.T) {
+	tests := []struct {
+		name string
+		have []string
+		add  []string
+		want []string
+	}{
+		{"new tags", []string{"go"}, []string{"maps", "hashing"}, []string{"go", "maps", "hashing"}},
+		{"already carried", []string{"go", "maps"}, []string{"maps", "go"}, []string{"go", "maps"}},
+		{"different case", []string{"Go"}, []string{"go", "GO"}, []string{"Go"}},
+		{"repeated in the call", nil, []string{"maps", "Maps", "maps"}, []string{"maps"}},
+		{"blanks and padding", []string{"go"}, []string{"", "  ", " maps ", "go "}, []string{"go", "maps"}},
+		{"nothing to add", []string{"go"}, nil, []string{"go"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &Note{Tags: slices.Clone(tt.have)}
+			n.AddTags(tt.add...)
+			if !slices.Equal(n.Tags, tt.want) {
+				t.Errorf("AddTags(%q) on %q = %q, want %q", tt.add, tt.have, n.Tags, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	n := &Note{Tags: []string{"Go", "maps"}}
+	for tag, want := range map[string]bool{"go": true, "GO": true, "maps": true, "map": false, "": false} {
+		if got := n.HasTag(tag); got != want {
+			t.Errorf("HasTag(%q) = %v, want %v", tag, got, want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package note
 import (
 	"bufio"
 	"bytes"
+	"log"
 	"os"
 	"strings"
 	"time"
@@ -19,6 +20,10 @@ func ParseFile(path string) (*Note, error) {
 	if err != nil {
 		return nil, err
 	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
 
 	md := goldmark.New(
 		goldmark.WithExtensions(
@@ -39,35 +44,104 @@ func ParseFile(path string) (*Note, error) {
 	note := &Note{
 		Filename:   path,
 		Content:    string(contentBytes),
-		EaseFactor: 2.5,
-		Interval:   1.0,
+		EaseFactor: DefaultEaseFactor,
+		State:      StateNew,
+		Interval:   DefaultInterval,
 		DueDate:    time.Now(),
+		ModifiedAt: info.ModTime(),
+		Links:      ExtractLinks(string(contentBytes)),
+		Cards:      ExtractCards(string(contentBytes)),
 	}
 
-	if title, ok := metaData["title"].(string); ok {
+	// goldmark-meta keys are case-sensitive, but notes use both "tags" and "Tags".
+	if title, ok := metaValue(metaData, "title").(string); ok {
 		note.Title = title
 	} else {
 		note.Title = findFirstH1(string(contentBytes))
 	}
 
-	if tags, ok := metaData["Tags"].([]any); ok { // Changed to 'any'
-		for _, t := range tags {
-			if tagStr, ok := t.(string); ok {
-				note.Tags = append(note.Tags, tagStr)
-			}
-		}
+	note.Tags = stringList(metaValue(metaData, "tags"))
+
+	if prompt, ok := metaValue(metaData, "question_prompt").(string); ok {
+		note.QuestionPrompt = strings.TrimSpace(prompt)
 	}
 
-	if createdStr, ok := metaData["Created"].(string); ok {
-		t, err := time.Parse("2006-01-02", createdStr)
-		if err == nil {
+	// Obsidian allows aliases as either a YAML list or a single string.
+	note.Aliases = stringList(metaValue(metaData, "aliases"))
+
+	// Without a usable "created" field, the file's modification time is the best guess.
+	note.CreatedAt = info.ModTime()
+	switch created := metaValue(metaData, "created").(type) {
+	case time.Time:
+		note.CreatedAt = created
+	case string:
+		if t, ok := parseDate(created); ok {
 			note.CreatedAt = t
+		} else {
+			log.Printf("Warning: unrecognized created date %q in %s; using the file's modification time.", created, path)
 		}
 	}
 
 	return note, nil
 }
 
+// dateLayouts are the accepted formats for the "created" frontmatter field, tried in order.
+// Slash dates are read day-first (15/03/2024).
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/01/2006",
+	"2/1/2006",
+	"January 2, 2006",
+	"2 January 2006",
+}
+
+// parseDate tries each of dateLayouts and reports whether any matched.
+func parseDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// metaValue looks up a frontmatter key case-insensitively. An exact match wins.
+func metaValue(metaData map[string]any, key string) any {
+	if v, ok := metaData[key]; ok {
+		return v
+	}
+	for k, v := range metaData {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// stringList converts a frontmatter value that is either a YAML list or a
+// single string into a slice of non-empty, trimmed strings.
+func stringList(value any) []string {
+	var items []string
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if str, ok := item.(string); ok && strings.TrimSpace(str) != "" {
+				items = append(items, strings.TrimSpace(str))
+			}
+		}
+	case string:
+		if strings.TrimSpace(v) != "" {
+			items = append(items, strings.TrimSpace(v))
+		}
+	}
+	return items
+}
+
 // findFirstH1 scans content for the first line starting with "# ".
 func findFirstH1(content string) string {
 	scanner := bufio.NewScanner(strings.NewReader(content))
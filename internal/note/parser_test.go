@@ -0,0 +1,131 @@
+package note
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// parseNote writes content to a markdown file and parses it.
+func parseNote(t *testing.T, content string) *Note {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "note.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	n, err := ParseFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestParseFileTagsKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"lowercase", "---\ntags: [go, sql]\n---\n# Note\n", []string{"go", "sql"}},
+		{"capitalized", "---\nTags: [go, sql]\n---\n# Note\n", []string{"go", "sql"}},
+		{"single string", "---\nTags: go\n---\n# Note\n", []string{"go"}},
+		{"exact key wins", "---\nTags: [other]\ntags: [go]\n---\n# Note\n", []string{"go"}},
+		{"none", "# Note\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNote(t, tt.content).Tags; !slices.Equal(got, tt.want) {
+				t.Errorf("Tags = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFileAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"list", "---\naliases: [TCP, Transmission Control Protocol]\n---\n# TCP\n", []string{"TCP", "Transmission Control Protocol"}},
+		{"block list", "---\naliases:\n  - TCP\n  - \" \"\n---\n# TCP\n", []string{"TCP"}},
+		{"single string", "---\naliases: TCP\n---\n# TCP\n", []string{"TCP"}},
+		{"capitalized key", "---\nAliases: TCP\n---\n# TCP\n", []string{"TCP"}},
+		{"blank string", "---\naliases: \"\"\n---\n# TCP\n", nil},
+		{"none", "# TCP\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNote(t, tt.content).Aliases; !slices.Equal(got, tt.want) {
+				t.Errorf("Aliases = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{"2024-03-15T10:30:00Z", time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC), true},
+		{"2024-03-15T10:30:00", time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC), true},
+		{"2024-03-15 10:30:45", time.Date(2024, 3, 15, 10, 30, 45, 0, time.UTC), true},
+		{"2024-03-15 10:30", time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC), true},
+		{"2024-03-15", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"  2024-03-15  ", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"15/03/2024", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"5/3/2024", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), true},
+		{"March 15, 2024", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"15 March 2024", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), true},
+		{"03/15/2024", time.Time{}, false},
+		{"yesterday", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := parseDate(tt.value)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("parseDate(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseFileCreatedFallsBackToModTime(t *testing.T) {
+	modTime := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		content string
+		want    time.Time
+	}{
+		{"parsed", "---\ncreated: 15/03/2024\n---\n# Note\n", time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"missing", "# Note\n", modTime},
+		{"unparseable", "---\ncreated: last spring\n---\n# Note\n", modTime},
+		{"not a string", "---\ncreated: [2024]\n---\n# Note\n", modTime},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "note.md")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(path, modTime, modTime); err != nil {
+				t.Fatal(err)
+			}
+			n, err := ParseFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !n.CreatedAt.Equal(tt.want) {
+				t.Errorf("CreatedAt = %v, want %v", n.CreatedAt, tt.want)
+			}
+			if !n.ModifiedAt.Equal(modTime) {
+				t.Errorf("ModifiedAt = %v, want %v", n.ModifiedAt, modTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Package note defines the core data structure for a note and its parser.
+package note
+
+import (
+	"strings"
+)
+
+// SetSection returns content with the level-two section "## heading" set to body.
+// An existing section with that heading (matched case-insensitively) is replaced up
+// to the next heading of level one or two. Otherwise the section is inserted after
+// the first H1, or after the frontmatter when there is no H1, or at the top.
+func SetSection(content, heading, body string) string {
+	lines := strings.Split(content, "\n")
+	section := []string{"## " + heading, "", strings.TrimSpace(body), ""}
+
+	for i, line := range lines {
+		if !isSectionHeading(line, heading) {
+			continue
+		}
+		end := len(lines)
+		for j := i + 1; j < len(lines); j++ {
+			if isTopHeading(lines[j]) {
+				end = j
+				break
+			}
+		}
+		return joinLines(lines[:i], section, lines[end:])
+	}
+
+	insertAt := frontmatterEnd(lines)
+	for i := insertAt; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "# ") {
+			insertAt = i + 1
+			// Keep the blank line that usually follows the title
+			if insertAt < len(lines) && strings.TrimSpace(lines[insertAt]) == "" {
+				insertAt++
+			}
+			break
+		}
+	}
+	return joinLines(lines[:insertAt], section, lines[insertAt:])
+}
+
+// isSectionHeading reports whether line is "## heading", ignoring case and spacing.
+func isSectionHeading(line, heading string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "## ") && trimmed != "##" {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(trimmed, "##")), strings.TrimSpace(heading))
+}
+
+// isTopHeading reports whether line is a level-one or level-two heading.
+func isTopHeading(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "# ") || strings.HasPrefix(trimmed, "## ")
+}
+
+// frontmatterEnd returns the index of the first line after a leading YAML frontmatter block, or 0.
+func frontmatterEnd(lines []string) int {
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// joinLines concatenates line slices into a single newline-separated string.
+func joinLines(parts ...[]string) string {
+	var all []string
+	for _, part := range parts {
+		all = append(all, part...)
+	}
+	return strings.Join(all, "\n")
+}
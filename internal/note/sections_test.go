@@ -0,0 +1,50 @@
+package note
+
+import "testing"
+
+func TestSetSection(t *testing.T) {
+	const summary = "- One\n- Two"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			"after the title",
+			"# Maps\n\nGo maps are hash tables.\n",
+			"# Maps\n\n## Summary\n\n- One\n- Two\n\nGo maps are hash tables.\n",
+		},
+		{
+			"after the frontmatter",
+			"---\ntags: [go]\n---\nNo title here.\n",
+			"---\ntags: [go]\n---\n## Summary\n\n- One\n- Two\n\nNo title here.\n",
+		},
+		{
+			"at the top",
+			"Just text.\n",
+			"## Summary\n\n- One\n- Two\n\nJust text.\n",
+		},
+		{
+			"replaces an existing section up to the next heading",
+			"# Maps\n\n## summary\nOld point.\n### Detail\nOld detail.\n\n## Details\nBuckets.\n",
+			"# Maps\n\n## Summary\n\n- One\n- Two\n\n## Details\nBuckets.\n",
+		},
+		{
+			"replaces a section at the end of the note",
+			"# Maps\n\n## Details\nBuckets.\n\n## Summary\nOld point.\n",
+			"# Maps\n\n## Details\nBuckets.\n\n## Summary\n\n- One\n- Two\n",
+		},
+		{
+			"leaves look-alike headings alone",
+			"# Maps\n\n## Summary of changes\nv2.\n",
+			"# Maps\n\n## Summary\n\n- One\n- Two\n\n## Summary of changes\nv2.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetSection(tt.content, "Summary", "\n"+summary+"\n"); got != tt.want {
+				t.Errorf("SetSection() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
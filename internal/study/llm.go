@@ -2,12 +2,11 @@
 package study
 
 import (
-	"bufio"
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/soyomarvaldezg/neuron-cli/internal/note"
@@ -23,11 +22,39 @@ const (
 	QuestionTypeMixed       QuestionType = "mixed"
 )
 
+// AnswerLength is how long a generated answer should be.
+type AnswerLength string
+
+const (
+	AnswerShort  AnswerLength = "short"  // a one-sentence answer
+	AnswerMedium AnswerLength = "medium" // 3-5 sentences with the why and an example (default)
+	AnswerLong   AnswerLength = "long"   // a thorough explanation
+)
+
+// ParseAnswerLength validates an --answer-length value. Empty means AnswerMedium.
+func ParseAnswerLength(s string) (AnswerLength, error) {
+	switch length := AnswerLength(strings.ToLower(strings.TrimSpace(s))); length {
+	case "":
+		return AnswerMedium, nil
+	case AnswerShort, AnswerMedium, AnswerLong:
+		return length, nil
+	}
+	return "", fmt.Errorf("invalid answer length %q: use short, medium, or long", s)
+}
+
+// OllamaOptions holds sampling parameters. Unset fields are left to the model's defaults.
+type OllamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	Seed        *int     `json:"seed,omitempty"`
+}
+
 // OllamaRequest represents the JSON payload for the Ollama /api/generate endpoint.
 type OllamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model   string         `json:"model"`
+	Prompt  string         `json:"prompt"`
+	Stream  bool           `json:"stream"`
+	Options *OllamaOptions `json:"options,omitempty"`
 }
 
 // OllamaResponse is not exported, so it doesn't need a special comment format.
@@ -47,6 +74,7 @@ type OllamaChatRequest struct {
 	Model    string          `json:"model"`
 	Messages []OllamaMessage `json:"messages"`
 	Stream   bool            `json:"stream"`
+	Options  *OllamaOptions  `json:"options,omitempty"`
 }
 
 // OllamaChatResponse is not exported.
@@ -56,8 +84,14 @@ type OllamaChatResponse struct {
 }
 
 // GenerateQuestion asks the LLM to generate a review question based on a note's content and question type.
+// A note's own question_prompt, when set, replaces the type-based prompts.
 func GenerateQuestion(n *note.Note, questionType QuestionType) (string, error) {
-	promptContent := extractSummary(n.Content)
+	promptContent := notePromptContent(n)
+
+	if n.QuestionPrompt != "" {
+		payload := OllamaRequest{Prompt: customQuestionPrompt(n.QuestionPrompt, "", promptContent), Stream: false, Options: withTemperature(QuestionTemperature)}
+		return sendOllamaRequest(payload)
+	}
 
 	var prompt string
 	switch questionType {
@@ -167,13 +201,36 @@ MATERIAL:
 ---`, promptContent)
 	}
 
-	payload := OllamaRequest{Model: "llama3:8b-instruct-q4_K_M", Prompt: prompt, Stream: false}
+	payload := OllamaRequest{Prompt: prompt, Stream: false, Options: withTemperature(QuestionTemperature)}
 	return sendOllamaRequest(payload)
 }
 
+// customQuestionPrompt builds the prompt for a note's own question_prompt, with
+// an optional extra rule before the note material.
+func customQuestionPrompt(questionPrompt, rule, promptContent string) string {
+	if rule != "" {
+		rule += "\n"
+	}
+	return fmt.Sprintf(`%s
+
+%sOutput ONLY the question, no preamble.
+
+MATERIAL:
+---
+%s
+---`, questionPrompt, rule, promptContent)
+}
+
 // GenerateQuestionWithVariation generates a question with a variation hint to avoid repetition.
+// Like GenerateQuestion, a note's own question_prompt replaces the type-based prompts.
 func GenerateQuestionWithVariation(n *note.Note, questionType QuestionType, attempt int) (string, error) {
-	promptContent := extractSummary(n.Content)
+	promptContent := notePromptContent(n)
+
+	if n.QuestionPrompt != "" {
+		rule := fmt.Sprintf("This is attempt #%d, so ask something DIFFERENT from the earlier questions.", attempt)
+		payload := OllamaRequest{Prompt: customQuestionPrompt(n.QuestionPrompt, rule, promptContent), Stream: false, Options: withTemperature(QuestionTemperature)}
+		return sendOllamaRequest(payload)
+	}
 
 	var prompt string
 	switch questionType {
@@ -196,7 +253,7 @@ RULES:
 MATERIAL:
 ---
 %s
----`, promptContent, attempt)
+---`, attempt, promptContent)
 
 	case QuestionTypeConceptual:
 		prompt = fmt.Sprintf(`You are an expert learning coach specializing in conceptual understanding.
@@ -217,7 +274,7 @@ RULES:
 MATERIAL:
 ---
 %s
----`, promptContent, attempt)
+---`, attempt, promptContent)
 
 	case QuestionTypeApplication:
 		prompt = fmt.Sprintf(`You are an expert learning coach specializing in practical application.
@@ -238,7 +295,7 @@ RULES:
 MATERIAL:
 ---
 %s
----`, promptContent, attempt)
+---`, attempt, promptContent)
 
 	case QuestionTypeMixed:
 		prompt = fmt.Sprintf(`You are an expert learning coach specializing in comprehensive understanding.
@@ -260,7 +317,7 @@ RULES:
 MATERIAL:
 ---
 %s
----`, promptContent, attempt)
+---`, attempt, promptContent)
 
 	default:
 		// Fallback to original behavior
@@ -280,33 +337,255 @@ RULES:
 MATERIAL:
 ---
 %s
----`, promptContent, attempt)
+---`, attempt, promptContent)
 	}
 
-	payload := OllamaRequest{Model: "llama3:8b-instruct-q4_K_M", Prompt: prompt, Stream: false}
+	payload := OllamaRequest{Prompt: prompt, Stream: false, Options: withTemperature(QuestionTemperature)}
 	return sendOllamaRequest(payload)
 }
 
-// GenerateAnswer asks the LLM to provide a concise answer to a specific question.
-func GenerateAnswer(question string, n *note.Note) (string, error) {
-	promptContent := extractSummary(n.Content)
-	prompt := fmt.Sprintf(`You are a learning coach providing pedagogically effective answers.
+// GenerateAnswer asks the LLM to answer a specific question at the given length.
+func GenerateAnswer(question string, n *note.Note, length AnswerLength) (string, error) {
+	payload := OllamaRequest{Prompt: buildAnswerPrompt(question, notePromptContent(n), length), Stream: false}
+	return sendOllamaRequest(payload)
+}
+
+// buildAnswerPrompt assembles the GenerateAnswer prompt. Unknown lengths get the medium instructions.
+func buildAnswerPrompt(question, promptContent string, length AnswerLength) string {
+	return fmt.Sprintf(`You are a learning coach providing pedagogically effective answers.
 
 QUESTION: %s
 
-YOUR TASK: Provide an answer that helps deep learning:
+YOUR TASK: %s
+
+SOURCE MATERIAL:
+---
+%s
+---`, question, answerInstructions(length), promptContent)
+}
+
+// answerInstructions describes the answer wanted at a length.
+func answerInstructions(length AnswerLength) string {
+	switch length {
+	case AnswerShort:
+		return `Answer in ONE sentence that states the key point directly.
+No preamble, examples, or elaboration.`
+	case AnswerLong:
+		return `Provide a thorough explanation that helps deep learning:
+1. Start with a direct 1-2 sentence answer
+2. Explain the "why" and "how" behind it step by step
+3. Give at least one concrete example or analogy
+4. Point out a common misconception or edge case
+5. End with a connection to a broader principle
+
+Be thorough but focused (2-3 short paragraphs).`
+	}
+	return `Provide an answer that helps deep learning:
 1. Start with a direct 1-2 sentence answer
 2. Then explain the "why" or "how" behind it
 3. If applicable, give a concrete example or analogy
 4. End with a connection to a broader principle (if relevant)
 
-Keep it concise but insightful (3-5 sentences total).
+Keep it concise but insightful (3-5 sentences total).`
+}
+
+// questionFocus describes each question type in a line, for prompts that ask for more than the question.
+var questionFocus = map[QuestionType]string{
+	QuestionTypeFactual:     "ONE factual recall question about a definition, fact, name, or specific detail, with a clear, objective answer",
+	QuestionTypeConceptual:  `ONE conceptual "why" or "how" question that tests understanding of relationships and principles`,
+	QuestionTypeApplication: "ONE application question that asks how to apply a concept to a realistic scenario",
+}
+
+// ErrNoQnA is returned by ParseQnA when a response has no labeled question and answer.
+var ErrNoQnA = errors.New("model reply had no QUESTION:/ANSWER: pair")
+
+// GenerateQnA asks for a question and its answer in a single request, so the answer
+// is ready as soon as the question is shown. A note's question_prompt is honored.
+// When the reply can't be split, the error wraps ErrNoQnA.
+func GenerateQnA(n *note.Note, questionType QuestionType, length AnswerLength) (question, answer string, err error) {
+	payload := OllamaRequest{Prompt: buildQnAPrompt(n, questionType, length), Stream: false, Options: withTemperature(QuestionTemperature)}
+	response, err := sendOllamaRequest(payload)
+	if err != nil {
+		return "", "", err
+	}
+	return ParseQnA(response)
+}
+
+// buildQnAPrompt assembles the GenerateQnA prompt.
+func buildQnAPrompt(n *note.Note, questionType QuestionType, length AnswerLength) string {
+	questionTask, ok := questionFocus[questionType]
+	if !ok {
+		questionTask = "ONE high-quality question that requires thinking, not just memorization"
+	}
+	questionTask = "Write " + questionTask + " about this material, then answer it."
+	if n.QuestionPrompt != "" {
+		questionTask = n.QuestionPrompt + "\nThen answer the question."
+	}
+	return fmt.Sprintf(`You are an expert learning coach.
+%s
+
+FOR THE ANSWER: %s
+
+Reply in exactly this format, with nothing before or after:
+QUESTION: <the question>
+ANSWER: <the answer>
+
+MATERIAL:
+---
+%s
+---`, questionTask, answerInstructions(length), notePromptContent(n))
+}
+
+// qnaLabel matches a QUESTION:/ANSWER: (or Q:/A:) label at the start of a line,
+// allowing Markdown decoration such as "**Question:**" or "### Answer:".
+var qnaLabel = regexp.MustCompile(`(?im)^[ \t>#*_-]*(question|answer|q|a)[ \t*_]*:[ \t*_]*`)
+
+// ParseQnA splits a reply into its question and answer. Text before the first
+// label is ignored; without a question label, the text before the answer label is
+// the question. The answer ends at a further question label, should the model
+// write more than one pair.
+func ParseQnA(response string) (question, answer string, err error) {
+	matches := qnaLabel.FindAllStringSubmatchIndex(response, -1)
+	questionEnd, answerStart, answerEnd := -1, -1, len(response)
+	questionStart := 0
+	for _, m := range matches {
+		isQuestion := strings.HasPrefix(strings.ToLower(response[m[2]:m[3]]), "q")
+		switch {
+		case isQuestion && answerStart < 0:
+			questionStart = m[1]
+		case !isQuestion && answerStart < 0:
+			questionEnd, answerStart = m[0], m[1]
+		case isQuestion:
+			answerEnd = m[0]
+		}
+		if answerEnd < len(response) {
+			break
+		}
+	}
+	if answerStart < 0 || questionStart > questionEnd {
+		return "", "", ErrNoQnA
+	}
+	question = strings.TrimSpace(response[questionStart:questionEnd])
+	answer = strings.TrimSpace(response[answerStart:answerEnd])
+	if question == "" || answer == "" {
+		return "", "", ErrNoQnA
+	}
+	return question, answer, nil
+}
+
+// GenerateSummary asks the LLM for a three-bullet TL;DR of the note plus one key takeaway, in Markdown.
+func GenerateSummary(n *note.Note) (string, error) {
+	prompt := fmt.Sprintf(`You are a learning coach condensing a study note.
+
+NOTE TITLE: %s
+
+YOUR TASK: Summarize the note below in Markdown using EXACTLY this format:
+- <first main point>
+- <second main point>
+- <third main point>
+
+**Key takeaway:** <the single most important idea, in one sentence>
+
+RULES:
+1. Each bullet is one short sentence
+2. Use only information from the note
+3. Output ONLY the summary, no preamble or heading
+
+NOTE:
+---
+%s
+---`, n.Title, n.Content)
+	payload := OllamaRequest{Prompt: prompt, Stream: false}
+	return sendOllamaRequest(payload)
+}
+
+// ClozeCard is a fill-in-the-blank card: Front has the key term replaced by ClozeBlank.
+type ClozeCard struct {
+	Front  string
+	Answer string
+}
+
+// ClozeBlank stands in for the hidden term on the front of a cloze card.
+const ClozeBlank = "_____"
+
+// clozeDeletion matches a {{term}} deletion, tolerating Anki-style {{c1::term}}.
+var clozeDeletion = regexp.MustCompile(`\{\{(?:c\d+::)?([^{}]+?)\}\}`)
+
+// clozeLinePrefix matches list markers and labels models like to put before each card.
+var clozeLinePrefix = regexp.MustCompile(`(?i)^\s*(?:[-*•]\s*|\d+[.)]\s*)?(?:\*\*)?(?:(?:cloze|card)\s*\d*\s*:)?(?:\*\*)?\s*`)
+
+// GenerateClozes asks the LLM for count cloze-deletion cards based on the note.
+func GenerateClozes(n *note.Note, count int) ([]ClozeCard, error) {
+	prompt := fmt.Sprintf(`You are a learning coach writing cloze-deletion flashcards.
+
+YOUR TASK: Write EXACTLY %d cloze cards from the note below.
+
+RULES:
+1. Each card is ONE self-contained sentence stating a fact from the note
+2. Wrap exactly ONE key term per sentence in double curly braces, like: A {{hash table}} offers average O(1) lookups.
+3. Prefer terms that appear in **bold** in the note
+4. Output one card per line in the form "CLOZE: <sentence>"
+5. Output ONLY the cards, no preamble or numbering
+
+NOTE:
+---
+%s
+---`, count, n.Content)
+	payload := OllamaRequest{Prompt: prompt, Stream: false}
+	response, err := sendOllamaRequest(payload)
+	if err != nil {
+		return nil, err
+	}
+	cards := ParseClozes(response)
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("could not find any cloze cards in the model response: %s", response)
+	}
+	if len(cards) > count {
+		cards = cards[:count]
+	}
+	return cards, nil
+}
+
+// ParseClozes extracts cards from lines containing a {{term}} deletion. Numbering,
+// bullets, "CLOZE:" labels, and bold markers are ignored. When a line has several
+// deletions, the first becomes the blank and the others are shown as plain text.
+func ParseClozes(response string) []ClozeCard {
+	var cards []ClozeCard
+	for _, line := range strings.Split(response, "\n") {
+		line = strings.TrimSpace(line)
+		first := clozeDeletion.FindStringSubmatchIndex(line)
+		if first == nil {
+			continue
+		}
+		answer := strings.TrimSpace(line[first[2]:first[3]])
+		front := line[:first[0]] + ClozeBlank + clozeDeletion.ReplaceAllString(line[first[1]:], "$1")
+		front = strings.TrimSpace(clozeLinePrefix.ReplaceAllString(front, ""))
+		if answer == "" || front == ClozeBlank {
+			continue
+		}
+		cards = append(cards, ClozeCard{Front: front, Answer: answer})
+	}
+	return cards
+}
+
+// GenerateHint asks the LLM for a one-sentence nudge toward the answer without giving it away.
+func GenerateHint(question string, n *note.Note) (string, error) {
+	promptContent := notePromptContent(n)
+	prompt := fmt.Sprintf(`You are a learning coach helping a student who is stuck on a question.
+
+QUESTION: %s
+
+YOUR TASK: Give ONE short sentence that nudges the student toward the answer.
+RULES:
+1. Do NOT state the answer or its key terms directly
+2. Point to the relevant idea, cue, or line of reasoning
+3. Output ONLY the hint, no preamble
 
 SOURCE MATERIAL:
 ---
 %s
 ---`, question, promptContent)
-	payload := OllamaRequest{Model: "llama3:8b-instruct-q4_K_M", Prompt: prompt, Stream: false}
+	payload := OllamaRequest{Prompt: prompt, Stream: false}
 	return sendOllamaRequest(payload)
 }
 
@@ -328,18 +607,157 @@ YOUR TASK: Provide constructive feedback in this format:
 
 Be encouraging but precise. Focus on helping them understand, not just pointing out mistakes.`, question, userAnswer, correctAnswer)
 
-	payload := OllamaRequest{Model: "llama3:8b-instruct-q4_K_M", Prompt: prompt, Stream: false}
+	payload := OllamaRequest{Prompt: prompt, Stream: false, Options: withTemperature(ComparisonTemperature)}
 	return sendOllamaRequest(payload)
 }
 
+// ScoreAnswer asks the LLM to grade the user's answer from 0 to 100 and returns
+// the score together with a one-line justification.
+func ScoreAnswer(userAnswer, correctAnswer, question string) (int, string, error) {
+	prompt := fmt.Sprintf(`You are an expert examiner grading a student's answer against a reference answer.
+
+QUESTION: %s
+
+STUDENT'S ANSWER: %s
+
+REFERENCE ANSWER: %s
+
+YOUR TASK: Grade how completely and accurately the student answered, from 0 (nothing correct) to 100 (fully correct).
+Respond in EXACTLY this format and nothing else:
+SCORE: <integer 0-100>
+REASON: <one sentence justification>`, question, userAnswer, correctAnswer)
+
+	payload := OllamaRequest{Prompt: prompt, Stream: false, Options: withTemperature(ComparisonTemperature)}
+	response, err := sendOllamaRequest(payload)
+	if err != nil {
+		return 0, "", err
+	}
+	return ParseScore(response)
+}
+
+// scorePatterns find the score in a grading response, most specific first: a
+// number labelled as the score ("Score: 82", "**Score:** **75**", "score of 60"),
+// then one out of 100 ("82/100", "60 out of 100"), then any integer ("**82**").
+var scorePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bscore\b[^\d]{0,12}?(\d{1,3})`),
+	regexp.MustCompile(`(?i)\b(\d{1,3})\s*(?:/|out of)\s*100\b`),
+	regexp.MustCompile(`(\d{1,3})`),
+}
+
+// ParseScore extracts a 0-100 score and a justification from a free-form grading response.
+// The score is the first number labelled as one or given out of 100, falling back to the
+// first integer, so "In 2 points: ... Score: 80" scores 80. The justification is a
+// "Reason:"/"Justification:" line if present, otherwise the first non-empty line after
+// the score.
+func ParseScore(response string) (int, string, error) {
+	lines := strings.Split(response, "\n")
+	score := -1
+	scoreLine := -1
+search:
+	for _, pattern := range scorePatterns {
+		for i, line := range lines {
+			if match := pattern.FindStringSubmatch(line); match != nil {
+				score, _ = strconv.Atoi(match[1])
+				scoreLine = i
+				break search
+			}
+		}
+	}
+	if score < 0 {
+		return 0, "", fmt.Errorf("no score found in response: %q", response)
+	}
+	if score > 100 {
+		score = 100
+	}
+
+	reason := ""
+	for i, line := range lines {
+		cleaned := strings.TrimSpace(strings.ReplaceAll(line, "*", ""))
+		lower := strings.ToLower(cleaned)
+		for _, prefix := range []string{"reason:", "justification:"} {
+			if strings.HasPrefix(lower, prefix) {
+				return score, strings.TrimSpace(cleaned[len(prefix):]), nil
+			}
+		}
+		if reason == "" && i > scoreLine && cleaned != "" {
+			reason = cleaned
+		}
+	}
+	return score, reason, nil
+}
+
+// DefaultReflectionPersona is the role the model plays when challenging the user's understanding.
+const DefaultReflectionPersona = `You are an expert learning coach acting as a "devil's advocate" to help deepen understanding through critical thinking.`
+
+// reflectionPersona replaces DefaultReflectionPersona when set with SetReflectionPersona.
+var reflectionPersona = DefaultReflectionPersona
+
+// SetReflectionPersona changes the persona used for reflection challenges. An empty persona restores the default.
+func SetReflectionPersona(persona string) {
+	persona = strings.TrimSpace(persona)
+	if persona == "" {
+		persona = DefaultReflectionPersona
+	}
+	reflectionPersona = persona
+}
+
+// promptLanguage is the language generated content is written in; empty means English.
+var promptLanguage string
+
+// SetLanguage makes the model write questions, answers, feedback and chat replies in
+// language. An empty language, or English, leaves the prompts as they are.
+func SetLanguage(language string) {
+	language = strings.TrimSpace(language)
+	if strings.EqualFold(language, "english") || strings.EqualFold(language, "en") {
+		language = ""
+	}
+	promptLanguage = language
+}
+
+// LanguageDirective is the instruction added to every prompt for the configured
+// language, or "" for English.
+func LanguageDirective() string {
+	if promptLanguage == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n\nWrite your entire response in %s, even if the note is written in another language.", promptLanguage)
+}
+
+// localizeChat returns messages with the language directive added to the system
+// prompt, leaving the caller's history untouched.
+func localizeChat(messages []OllamaMessage) []OllamaMessage {
+	directive := LanguageDirective()
+	if directive == "" || len(messages) == 0 || messages[0].Role != "system" {
+		return messages
+	}
+	localized := append([]OllamaMessage(nil), messages...)
+	localized[0].Content += directive
+	return localized
+}
+
 // GenerateReflectionChallenges creates challenging questions to test the user's understanding.
-func GenerateReflectionChallenges(userExplanation, noteContent string) (string, error) {
-	prompt := fmt.Sprintf(`You are an expert learning coach acting as a "devil's advocate" to help deepen understanding through critical thinking.
+// round counts the reflection rounds so far (starting at 1); later rounds are asked for new angles.
+func GenerateReflectionChallenges(userExplanation, noteContent string, round int) (string, error) {
+	payload := OllamaRequest{Prompt: buildReflectionPrompt(userExplanation, noteContent, round), Stream: false}
+	return sendOllamaRequest(payload)
+}
+
+// buildReflectionPrompt assembles the devil's-advocate prompt for one reflection round.
+func buildReflectionPrompt(userExplanation, noteContent string, round int) string {
+	variation := ""
+	if round > 1 {
+		variation = fmt.Sprintf(`
+This is reflection round #%d. Earlier rounds already challenged this explanation, so look for NEW angles:
+different edge cases, assumptions, limitations, and alternatives than the obvious ones. Do not repeat earlier challenges.
+`, round)
+	}
+
+	return fmt.Sprintf(`%s
 
 USER'S EXPLANATION: %s
 
 SOURCE MATERIAL: %s
-
+%s
 YOUR TASK: Play devil's advocate and challenge the user's understanding. Generate 4 challenging questions in this format:
 
 1. 🔥 Edge Cases: What scenarios would break or challenge their explanation?
@@ -347,98 +765,286 @@ YOUR TASK: Play devil's advocate and challenge the user's understanding. Generat
 3. ⚡ Limitations: What are the limitations or boundaries of their approach?
 4. 🔄 Alternatives: What alternative perspectives or approaches could they consider?
 
-Make questions specific and thought-provoking. Don't be overly critical - aim to expand their thinking, not tear them down.`, userExplanation, noteContent)
+Make questions specific and thought-provoking. Don't be overly critical - aim to expand their thinking, not tear them down.`, reflectionPersona, userExplanation, noteContent, variation)
+}
 
-	payload := OllamaRequest{Model: "llama3:8b-instruct-q4_K_M", Prompt: prompt, Stream: false}
-	return sendOllamaRequest(payload)
+// ErrEmptyResponse is returned when the model answers with nothing but whitespace.
+var ErrEmptyResponse = errors.New("model returned no content")
+
+// Sampling temperatures for tasks that need a particular amount of randomness:
+// questions should vary between attempts, while grading should be repeatable.
+const (
+	QuestionTemperature   = 0.8
+	ComparisonTemperature = 0.1
+)
+
+// defaultOptions are sent with every request; a task temperature replaces its Temperature.
+var defaultOptions OllamaOptions
+
+// temperatureOverride, when set, replaces the temperature of every request.
+var temperatureOverride *float64
+
+// SetDefaultOptions sets the sampling options sent with every request, e.g. from the config file.
+func SetDefaultOptions(opts OllamaOptions) {
+	defaultOptions = opts
 }
 
-// sendOllamaRequest is a private helper to reduce code duplication for the /api/generate endpoint.
-func sendOllamaRequest(payload OllamaRequest) (string, error) {
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return "", err
+// SetTemperatureOverride forces one temperature for every request, including
+// those with a task temperature. Passing nil removes the override.
+func SetTemperatureOverride(temperature *float64) {
+	temperatureOverride = temperature
+}
+
+// requestOptions combines the default options with a task temperature (nil for none).
+// It returns nil when nothing is set so the model's own defaults apply.
+func requestOptions(temperature *float64) *OllamaOptions {
+	opts := defaultOptions
+	if temperature != nil {
+		opts.Temperature = temperature
 	}
-	resp, err := http.Post("http://localhost:11434/api/generate", "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return "", fmt.Errorf("failed to send request to ollama: %w. Is Ollama running?", err)
+	if temperatureOverride != nil {
+		opts.Temperature = temperatureOverride
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
+	if opts == (OllamaOptions{}) {
+		return nil
 	}
-	var ollamaResp OllamaResponse
-	if err := json.Unmarshal(body, &ollamaResp); err != nil {
-		return "", fmt.Errorf("failed to unmarshal ollama response: %w. Response was: %s", err, string(body))
+	return &opts
+}
+
+// withTemperature returns the request options for a task that wants the given temperature.
+func withTemperature(temperature float64) *OllamaOptions {
+	return requestOptions(&temperature)
+}
+
+// DefaultMaxConcurrency is how many LLM requests may be in flight at once. A local
+// Ollama works on one request at a time, so extra ones would only pile up in memory.
+const DefaultMaxConcurrency = 1
+
+// requestSlots holds one token per request in flight; see SetMaxConcurrency.
+var requestSlots = make(chan struct{}, DefaultMaxConcurrency)
+
+// SetMaxConcurrency changes how many LLM requests may be in flight at once. Zero or
+// less restores the default. Call it before any requests are made.
+func SetMaxConcurrency(n int) {
+	if n <= 0 {
+		n = DefaultMaxConcurrency
 	}
-	return strings.TrimSpace(ollamaResp.Response), nil
+	requestSlots = make(chan struct{}, n)
 }
 
-// SendChatMessage sends a list of messages to the Ollama chat endpoint and returns the AI's response.
-func SendChatMessage(messages []OllamaMessage) (OllamaMessage, error) {
-	payload := OllamaChatRequest{
-		Model:    "llama3:8b-instruct-q4_K_M",
-		Messages: messages,
-		Stream:   false,
+// acquireSlot blocks until a request may be sent and returns the function that frees the slot.
+func acquireSlot() (release func()) {
+	slots := requestSlots
+	slots <- struct{}{}
+	return func() { <-slots }
+}
+
+// sendOllamaRequest is a private helper to reduce code duplication for the /api/generate endpoint.
+// The request is routed through the active provider, which uses the model it was created
+// with (see NewProvider and ActiveModel), so payloads leave Model empty.
+// Payloads without options get the default ones, and every prompt gets the language directive.
+// An empty response is retried once before ErrEmptyResponse is returned.
+func sendOllamaRequest(payload OllamaRequest) (string, error) {
+	if payload.Options == nil {
+		payload.Options = requestOptions(nil)
 	}
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return OllamaMessage{}, err
+	payload.Prompt += LanguageDirective()
+	release := acquireSlot()
+	defer release()
+	for attempt := 0; attempt < 2; attempt++ {
+		response, err := activeProvider.Generate(payload.Prompt, payload.Options)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(response) != "" {
+			return response, nil
+		}
 	}
-	resp, err := http.Post("http://localhost:11434/api/chat", "application/json", bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return OllamaMessage{}, fmt.Errorf("failed to send chat request to ollama: %w", err)
+	return "", ErrEmptyResponse
+}
+
+// SendChatMessage sends a list of messages to the active provider's chat endpoint and returns the AI's response.
+// Like sendOllamaRequest, an empty reply is retried once before ErrEmptyResponse is returned.
+func SendChatMessage(messages []OllamaMessage) (OllamaMessage, error) {
+	messages = localizeChat(messages)
+	opts := requestOptions(nil)
+	release := acquireSlot()
+	defer release()
+	for attempt := 0; attempt < 2; attempt++ {
+		response, err := activeProvider.Chat(messages, opts)
+		if err != nil {
+			return OllamaMessage{}, err
+		}
+		if strings.TrimSpace(response.Content) != "" {
+			return response, nil
+		}
 	}
-	defer resp.Body.Close()
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return OllamaMessage{}, err
-	}
-	var ollamaResp OllamaChatResponse
-	if err := json.Unmarshal(body, &ollamaResp); err != nil {
-		return OllamaMessage{}, fmt.Errorf("failed to unmarshal ollama chat response: %w. Response was: %s", err, string(body))
-	}
-	return ollamaResp.Message, nil
-}
-
-// extractSummary is a private helper function.
-func extractSummary(fullContent string) string {
-	var summary, takeaways strings.Builder
-	inSummary := false
-	inTakeaways := false
-	scanner := bufio.NewScanner(strings.NewReader(fullContent))
-	for scanner.Scan() {
-		line := scanner.Text()
-		lowerLine := strings.ToLower(line)
-		if strings.HasPrefix(lowerLine, "## summary") {
-			inSummary = true
-			inTakeaways = false
-			continue
+	return OllamaMessage{}, ErrEmptyResponse
+}
+
+// SendChatMessageStream is SendChatMessage for interactive sessions: the reply is
+// written to w as it is generated when the provider can stream, and in one piece otherwise.
+func SendChatMessageStream(messages []OllamaMessage, w io.Writer) (OllamaMessage, error) {
+	streamer, ok := activeProvider.(ChatStreamer)
+	if !ok {
+		response, err := SendChatMessage(messages)
+		if err != nil {
+			return OllamaMessage{}, err
 		}
-		if strings.HasPrefix(lowerLine, "## key takeaways") {
-			inSummary = false
-			inTakeaways = true
-			continue
+		_, err = io.WriteString(w, response.Content)
+		return response, err
+	}
+	messages = localizeChat(messages)
+	opts := requestOptions(nil)
+	release := acquireSlot()
+	defer release()
+	for attempt := 0; attempt < 2; attempt++ {
+		response, err := streamer.ChatStream(messages, opts, w)
+		if err != nil {
+			return OllamaMessage{}, err
 		}
-		if strings.HasPrefix(lowerLine, "##") {
-			inSummary = false
-			inTakeaways = false
+		if strings.TrimSpace(response.Content) != "" {
+			return response, nil
 		}
-		if inSummary {
-			if !strings.HasPrefix(lowerLine, "## summary") {
-				summary.WriteString(line + "\n")
-			}
+	}
+	return OllamaMessage{}, ErrEmptyResponse
+}
+
+// DefaultSummarySections are the section titles ExtractSummary collects unless
+// SetSummarySections replaces them.
+var DefaultSummarySections = []string{"summary", "key takeaways"}
+
+// summarySections holds the lowercased titles ExtractSummary looks for.
+var summarySections = DefaultSummarySections
+
+// SetSummarySections changes the section titles ExtractSummary collects. Titles
+// are matched as case-insensitive prefixes; an empty list restores the defaults.
+func SetSummarySections(titles []string) {
+	var sections []string
+	for _, title := range titles {
+		if title = strings.ToLower(strings.TrimSpace(title)); title != "" {
+			sections = append(sections, title)
 		}
-		if inTakeaways {
-			if !strings.HasPrefix(lowerLine, "## key takeaways") {
-				takeaways.WriteString(line + "\n")
+	}
+	if len(sections) == 0 {
+		sections = DefaultSummarySections
+	}
+	summarySections = sections
+}
+
+// ExtractSummary returns the summary sections of a note ("## Summary" and
+// "## Key Takeaways" by default, see SetSummarySections), falling back to the
+// full content when none of them has meaningful text. Sections are returned in
+// the order of the configured titles. Headings are matched case-insensitively
+// and tolerate extra whitespace, so "##   summary" and "  ## KEY TAKEAWAYS "
+// are both recognized. Any other heading of level two or deeper ends the
+// current section. Lines inside fenced code blocks are never headings, so a
+// "## comment" in a shell snippet stays part of its section.
+func ExtractSummary(fullContent string) string {
+	sections := make([]strings.Builder, len(summarySections))
+	current := -1
+	fence := "" // the marker of the open code fence, if any
+	// Split instead of using a bufio.Scanner so very long lines are never silently dropped.
+	for _, line := range strings.Split(fullContent, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if marker, ok := fenceMarker(line); ok {
+			switch {
+			case fence == "":
+				fence = marker
+			case strings.HasPrefix(marker, fence) && strings.TrimSpace(line) == marker:
+				fence = ""
 			}
+		} else if heading, ok := subheading(line); ok && fence == "" {
+			current = -1
+			for i, title := range summarySections {
+				if strings.HasPrefix(heading, title) {
+					current = i
+					break
+				}
+			}
+			continue
 		}
+		if current >= 0 {
+			sections[current].WriteString(line + "\n")
+		}
+	}
+	var combined strings.Builder
+	for i := range sections {
+		combined.WriteString(sections[i].String())
 	}
-	combined := summary.String() + takeaways.String()
-	if len(strings.TrimSpace(combined)) > 10 {
-		return combined
+	if len(strings.TrimSpace(combined.String())) > 10 {
+		return combined.String()
 	}
 	return fullContent
 }
+
+// DefaultMaxPromptChars caps how much note text goes into a prompt, keeping long notes
+// within the model's context window.
+const DefaultMaxPromptChars = 6000
+
+// TruncatedMarker is appended to note text that was cut to fit the prompt.
+const TruncatedMarker = "[truncated]"
+
+// maxPromptChars replaces DefaultMaxPromptChars when set with SetMaxPromptChars.
+var maxPromptChars = DefaultMaxPromptChars
+
+// SetMaxPromptChars changes the prompt content cap. Zero or less restores the default.
+func SetMaxPromptChars(max int) {
+	if max <= 0 {
+		max = DefaultMaxPromptChars
+	}
+	maxPromptChars = max
+}
+
+// notePromptContent returns the part of a note sent to the model: its summary,
+// trimmed to the prompt content cap.
+func notePromptContent(n *note.Note) string {
+	return TrimContent(ExtractSummary(n.Content), maxPromptChars)
+}
+
+// TrimContent shortens content to at most max characters, cutting at the last
+// paragraph break (or failing that, line break or space) in the second half of
+// the limit, and appends TruncatedMarker. Content within the limit is returned unchanged.
+func TrimContent(content string, max int) string {
+	if len(content) <= max {
+		return content
+	}
+	runes := []rune(content)
+	if len(runes) <= max {
+		return content
+	}
+	cut := string(runes[:max])
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		if i := strings.LastIndex(cut, sep); i >= len(cut)/2 {
+			cut = cut[:i]
+			break
+		}
+	}
+	return strings.TrimRight(cut, " \t\r\n") + "\n\n" + TruncatedMarker
+}
+
+// fenceMarker reports whether line starts a fenced code block delimiter and
+// returns its run of backticks or tildes. Whether it opens or closes a block is
+// up to the caller: a closing fence must be at least as long as the opening one,
+// use the same character, and carry no info string.
+func fenceMarker(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, c := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, c+c+c) {
+			return trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, c))], true
+		}
+	}
+	return "", false
+}
+
+// subheading reports whether line is a markdown heading of level two or deeper
+// and returns its lowercased text. Only level-two headings can start a section;
+// deeper headings are returned with their extra '#' so they never match one.
+func subheading(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "##") {
+		return "", false
+	}
+	text := strings.TrimPrefix(trimmed, "##")
+	return strings.ToLower(strings.TrimSpace(text)), true
+}
@@ -0,0 +1,609 @@
+package study
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// promptRecorder is a Provider that keeps every prompt it is sent, and the
+// system prompt of every chat.
+type promptRecorder struct {
+	prompts []string
+	systems []string
+}
+
+func (p *promptRecorder) Generate(prompt string, opts *OllamaOptions) (string, error) {
+	p.prompts = append(p.prompts, prompt)
+	return "A question?", nil
+}
+
+func (p *promptRecorder) Chat(messages []OllamaMessage, opts *OllamaOptions) (OllamaMessage, error) {
+	p.prompts = append(p.prompts, messages[len(messages)-1].Content)
+	if messages[0].Role == "system" {
+		p.systems = append(p.systems, messages[0].Content)
+	}
+	return OllamaMessage{Role: "assistant", Content: "A reply."}, nil
+}
+
+// recordPrompts makes the study helpers send their prompts to a recorder for the rest of the test.
+func recordPrompts(t *testing.T) *promptRecorder {
+	t.Helper()
+	p := &promptRecorder{}
+	SetProvider(p)
+	t.Cleanup(func() { SetProvider(NewOllamaProvider()) })
+	return p
+}
+
+func TestQuestionPromptReplacesTypePrompts(t *testing.T) {
+	const custom = "Ask me to translate one phrase into Spanish."
+	n := &note.Note{Content: "## Summary\nHola means hello.\n", QuestionPrompt: custom}
+	generators := map[string]func() (string, error){
+		"GenerateQuestion":              func() (string, error) { return GenerateQuestion(n, QuestionTypeConceptual) },
+		"GenerateQuestionWithVariation": func() (string, error) { return GenerateQuestionWithVariation(n, QuestionTypeConceptual, 3) },
+	}
+	for name, generate := range generators {
+		t.Run(name, func(t *testing.T) {
+			recorder := recordPrompts(t)
+			if _, err := generate(); err != nil {
+				t.Fatal(err)
+			}
+			prompt := recorder.prompts[0]
+			if !strings.HasPrefix(prompt, custom) || !strings.Contains(prompt, "Hola means hello.") {
+				t.Errorf("prompt doesn't use the note's question_prompt:\n%s", prompt)
+			}
+			if strings.Contains(prompt, "conceptual understanding") {
+				t.Errorf("prompt still uses the conceptual template:\n%s", prompt)
+			}
+		})
+	}
+}
+
+func TestVariationKeepsTheAttemptWithAQuestionPrompt(t *testing.T) {
+	recorder := recordPrompts(t)
+	n := &note.Note{Content: "Some material.", QuestionPrompt: "Ask about the material."}
+	for attempt := 1; attempt <= 2; attempt++ {
+		if _, err := GenerateQuestionWithVariation(n, QuestionTypeMixed, attempt); err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("attempt #%d", attempt); !strings.Contains(recorder.prompts[attempt-1], want) {
+			t.Errorf("prompt for attempt %d doesn't mention it:\n%s", attempt, recorder.prompts[attempt-1])
+		}
+	}
+}
+
+func TestVariationWithoutQuestionPromptUsesTheType(t *testing.T) {
+	recorder := recordPrompts(t)
+	n := &note.Note{Content: "Some material."}
+	if _, err := GenerateQuestionWithVariation(n, QuestionTypeFactual, 2); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(recorder.prompts[0], "factual recall") {
+		t.Errorf("prompt doesn't use the factual template:\n%s", recorder.prompts[0])
+	}
+}
+
+func TestExtractSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "both sections",
+			content: "# Note\nintro\n## Summary\nThe summary text.\n## Details\nlong details\n## Key Takeaways\n- first takeaway\n",
+			want:    "The summary text.\n- first takeaway\n\n",
+		},
+		{
+			name:    "only summary",
+			content: "# Note\n## Summary\nJust the summary here.\n",
+			want:    "Just the summary here.\n\n",
+		},
+		{
+			name:    "only key takeaways",
+			content: "# Note\nbody\n## Key Takeaways\nRemember this one thing.\n",
+			want:    "Remember this one thing.\n\n",
+		},
+		{
+			name:    "neither section returns the full content",
+			content: "# Note\nSome body text.\n## Details\nMore text.\n",
+			want:    "# Note\nSome body text.\n## Details\nMore text.\n",
+		},
+		{
+			name:    "casing and whitespace",
+			content: "# Note\n  ##   SUMMARY  \nLoud summary text.\n##key takeaways\nQuiet takeaway text.\n",
+			want:    "Loud summary text.\nQuiet takeaway text.\n\n",
+		},
+		{
+			name:    "section ends at the next heading",
+			content: "## Summary\nKept summary line.\n## Other\nDropped line.\n",
+			want:    "Kept summary line.\n",
+		},
+		{
+			name:    "deeper headings end the section without starting one",
+			content: "## Summary\nKept summary line.\n### Summary of details\nDropped line.\n",
+			want:    "Kept summary line.\n",
+		},
+		{
+			name:    "too short a summary returns the full content",
+			content: "## Summary\nTiny\n## Body\nThe real text.\n",
+			want:    "## Summary\nTiny\n## Body\nThe real text.\n",
+		},
+		{
+			name:    "headings inside a code block stay in the section",
+			content: "## Summary\nRun this:\n```sh\n## not a heading\nmake\n```\nDone.\n## Details\nDropped.\n",
+			want:    "Run this:\n```sh\n## not a heading\nmake\n```\nDone.\n",
+		},
+		{
+			name:    "a fence only closes on a matching marker",
+			content: "## Summary\n````md\n```\n## still code\n````\nAfter.\n## Details\nDropped.\n",
+			want:    "````md\n```\n## still code\n````\nAfter.\n",
+		},
+		{
+			name:    "tilde fences",
+			content: "## Summary\nExample:\n~~~\n## Key Takeaways\n~~~\n## Other\nDropped.\n",
+			want:    "Example:\n~~~\n## Key Takeaways\n~~~\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSummary(tt.content); got != tt.want {
+				t.Errorf("ExtractSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSummarySections(t *testing.T) {
+	t.Cleanup(func() { SetSummarySections(nil) })
+	SetSummarySections([]string{" TL;DR "})
+	content := "## Summary\nNot collected anymore.\n## TL;DR\nCollected instead.\n"
+	if got := ExtractSummary(content); strings.TrimSpace(got) != "Collected instead." {
+		t.Errorf("ExtractSummary() = %q, want only the TL;DR section", got)
+	}
+}
+
+func TestExtractSummaryAlternateHeaders(t *testing.T) {
+	t.Cleanup(func() { SetSummarySections(nil) })
+	SetSummarySections([]string{"TL;DR", "Overview", "Main Points"})
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "tl;dr",
+			content: "# Maps\nintro\n## TL;DR\nMaps are hash tables.\n## Details\nDropped.\n",
+			want:    "Maps are hash tables.\n",
+		},
+		{
+			name:    "overview",
+			content: "# Maps\n## Overview\nMaps are hash tables.\n## Details\nDropped.\n",
+			want:    "Maps are hash tables.\n",
+		},
+		{
+			name:    "main points",
+			content: "# Maps\n## Main Points\n- unordered\n- not thread-safe\n## Details\nDropped.\n",
+			want:    "- unordered\n- not thread-safe\n",
+		},
+		{
+			name:    "prefix and casing",
+			content: "## OVERVIEW of maps\nMaps are hash tables.\n## main points to remember\n- unordered keys\n",
+			want:    "Maps are hash tables.\n- unordered keys\n\n",
+		},
+		{
+			name:    "in the configured order",
+			content: "## Main Points\n- point one\n## Overview\nOverview text.\n## TL;DR\nShort version.\n",
+			want:    "Short version.\n\nOverview text.\n- point one\n",
+		},
+		{
+			name:    "defaults no longer match",
+			content: "## Summary\nNot collected anymore.\n## Details\nMore text.\n",
+			want:    "## Summary\nNot collected anymore.\n## Details\nMore text.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSummary(tt.content); got != tt.want {
+				t.Errorf("ExtractSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScore(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		wantScore  int
+		wantReason string
+	}{
+		{"requested format", "SCORE: 82\nREASON: Mostly right.", 82, "Mostly right."},
+		{"out of 100", "82/100\nMissed one detail.", 82, "Missed one detail."},
+		{"markdown", "**Score:** **75**\n**Reason:** Vague on the why.", 75, "Vague on the why."},
+		{"sentence", "I would give this 60 out of 100.\nJustification: half of it.", 60, "half of it."},
+		{"preamble before score", "Here is my grade:\nScore: 90\nReason: Good.", 90, "Good."},
+		{"capped at 100", "Score: 150", 100, ""},
+		{"zero", "SCORE: 0\nREASON: Nothing correct.", 0, "Nothing correct."},
+		{"number before the score", "In 2 points: it names the cause but not the fix. Score: 80\nReason: Incomplete.", 80, "Incomplete."},
+		{"number on a line before the score", "The answer covers 3 of the 4 steps.\nScore: 75", 75, ""},
+		{"number before out of 100", "Of the 2 parts, one is right: 50/100\nHalf of it.", 50, "Half of it."},
+		{"score of", "I'd give it a score of 65.", 65, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reason, err := ParseScore(tt.response)
+			if err != nil {
+				t.Fatalf("ParseScore(%q) error: %v", tt.response, err)
+			}
+			if score != tt.wantScore || reason != tt.wantReason {
+				t.Errorf("ParseScore(%q) = %d, %q; want %d, %q", tt.response, score, reason, tt.wantScore, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestParseScoreWithoutNumber(t *testing.T) {
+	if _, _, err := ParseScore("Good answer, well done."); err == nil {
+		t.Error("expected an error for a response without a score")
+	}
+}
+
+func TestTrimContent(t *testing.T) {
+	const cut = "\n\n" + TruncatedMarker
+	tests := []struct {
+		name    string
+		content string
+		max     int
+		want    string
+	}{
+		{"within the limit", "Short note.", 20, "Short note."},
+		{"exactly the limit", "Exactly ten", 11, "Exactly ten"},
+		{"multi-byte within the limit", "héllo wörld", 11, "héllo wörld"},
+		{"at a paragraph break", "First part.\n\nSecond part.\n\nThird part.", 30, "First part.\n\nSecond part." + cut},
+		{"paragraph break before the second half", "Intro.\n\nA much longer second paragraph that goes on", 40, "Intro.\n\nA much longer second paragraph" + cut},
+		{"at a line break", "line one\nline two\nline three", 22, "line one\nline two" + cut},
+		{"at a space", "one two three four five", 16, "one two three" + cut},
+		{"mid-word without a break", "abcdefghijklmnopqrstuvwxyz", 10, "abcdefghij" + cut},
+		{"on a rune boundary", "ééééééééééééé", 5, "ééééé" + cut},
+		{"spaces between multi-byte words", "日本語 日本語 日本語 日本語", 10, "日本語 日本語" + cut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TrimContent(tt.content, tt.max)
+			if got != tt.want {
+				t.Errorf("TrimContent(%q, %d) = %q, want %q", tt.content, tt.max, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("TrimContent(%q, %d) split a rune: %q", tt.content, tt.max, got)
+			}
+		})
+	}
+}
+
+func TestLongNotesAreTrimmedInPrompts(t *testing.T) {
+	SetMaxPromptChars(100)
+	t.Cleanup(func() { SetMaxPromptChars(0) })
+	n := &note.Note{Content: strings.Repeat("Filler sentence here. ", 20) + "\n\nTHE-END"}
+	recorder := recordPrompts(t)
+	if _, err := GenerateQuestion(n, QuestionTypeFactual); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateAnswer("A question?", n, AnswerMedium); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateQuestionWithVariation(n, QuestionTypeFactual, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, prompt := range recorder.prompts {
+		if !strings.Contains(prompt, TruncatedMarker) || strings.Contains(prompt, "THE-END") {
+			t.Errorf("prompt doesn't carry the trimmed note:\n%s", prompt)
+		}
+	}
+}
+
+func TestParseClozes(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []ClozeCard
+	}{
+		{
+			name:     "requested format",
+			response: "CLOZE: A {{hash table}} offers average O(1) lookups.\nCLOZE: Go maps are {{unordered}}.",
+			want: []ClozeCard{
+				{Front: "A _____ offers average O(1) lookups.", Answer: "hash table"},
+				{Front: "Go maps are _____.", Answer: "unordered"},
+			},
+		},
+		{
+			name:     "numbering, bullets and bold labels",
+			response: "1. {{Goroutines}} are cheap.\n- **Card 2:** A {{channel}} passes values.\n* Cloze: {{select}} waits on channels.",
+			want: []ClozeCard{
+				{Front: "_____ are cheap.", Answer: "Goroutines"},
+				{Front: "A _____ passes values.", Answer: "channel"},
+				{Front: "_____ waits on channels.", Answer: "select"},
+			},
+		},
+		{
+			name:     "anki syntax",
+			response: "The {{c1::mitochondria}} makes ATP.",
+			want:     []ClozeCard{{Front: "The _____ makes ATP.", Answer: "mitochondria"}},
+		},
+		{
+			name:     "several deletions keep only the first",
+			response: "A {{mutex}} guards {{shared state}}.",
+			want:     []ClozeCard{{Front: "A _____ guards shared state.", Answer: "mutex"}},
+		},
+		{
+			name:     "preamble and lines without a deletion",
+			response: "Here are your cards:\n\nCLOZE: {{TCP}} is reliable.\nThat's all!",
+			want:     []ClozeCard{{Front: "_____ is reliable.", Answer: "TCP"}},
+		},
+		{
+			name:     "blank term or nothing but the blank",
+			response: "CLOZE: {{ }} is empty.\nCLOZE: {{alone}}",
+			want:     nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseClozes(tt.response); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseClozes(%q) = %+v, want %+v", tt.response, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQnA(t *testing.T) {
+	tests := []struct {
+		name         string
+		response     string
+		wantQuestion string
+		wantAnswer   string
+	}{
+		{"requested format", "QUESTION: What is a goroutine?\nANSWER: A lightweight thread.", "What is a goroutine?", "A lightweight thread."},
+		{"short labels", "Q: Why buffer a channel?\nA: So sends don't block.", "Why buffer a channel?", "So sends don't block."},
+		{"markdown labels", "**Question:** What does defer do?\n\n### Answer:\nRuns a call when the function returns.", "What does defer do?", "Runs a call when the function returns."},
+		{"preamble", "Sure! Here you go.\nQuestion: What is a slice?\nAnswer: A view of an array.", "What is a slice?", "A view of an array."},
+		{"no question label", "What is a map?\nANSWER: A hash table.", "What is a map?", "A hash table."},
+		{"multi-line answer", "QUESTION: Name two verbs.\nANSWER: Run\nand jump.", "Name two verbs.", "Run\nand jump."},
+		{"second pair ignored", "Q: First?\nA: One.\nQ: Second?\nA: Two.", "First?", "One."},
+		{"label words inside text", "QUESTION: What is a question mark?\nANSWER: A sign that ends a question: like this?", "What is a question mark?", "A sign that ends a question: like this?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			question, answer, err := ParseQnA(tt.response)
+			if err != nil {
+				t.Fatalf("ParseQnA(%q) error: %v", tt.response, err)
+			}
+			if question != tt.wantQuestion || answer != tt.wantAnswer {
+				t.Errorf("ParseQnA(%q) = %q, %q; want %q, %q", tt.response, question, answer, tt.wantQuestion, tt.wantAnswer)
+			}
+		})
+	}
+}
+
+func TestParseQnAWithoutAPair(t *testing.T) {
+	for _, response := range []string{
+		"Just a question without an answer?",
+		"QUESTION: Where is the answer?",
+		"ANSWER: An answer with no question.",
+		"QUESTION: Empty answer?\nANSWER:   ",
+		"ANSWER: Backwards.\nQUESTION: Which comes first?",
+	} {
+		if q, a, err := ParseQnA(response); !errors.Is(err, ErrNoQnA) {
+			t.Errorf("ParseQnA(%q) = %q, %q, %v; want ErrNoQnA", response, q, a, err)
+		}
+	}
+}
+
+func TestEveryPromptHasTheLanguageDirective(t *testing.T) {
+	SetLanguage("Spanish")
+	t.Cleanup(func() { SetLanguage("") })
+	directive := LanguageDirective()
+	if !strings.Contains(directive, "Spanish") {
+		t.Fatalf("LanguageDirective() = %q, want it to name Spanish", directive)
+	}
+
+	n := &note.Note{Title: "Maps", Content: "## Summary\nGo maps are hash tables.\n"}
+	// Replies the recorder gives don't parse for every builder; only the prompt matters.
+	builders := map[string]func(){
+		"GenerateQuestion":              func() { GenerateQuestion(n, QuestionTypeFactual) },
+		"GenerateQuestionWithVariation": func() { GenerateQuestionWithVariation(n, QuestionTypeFactual, 2) },
+		"GenerateAnswer":                func() { GenerateAnswer("What is a map?", n, AnswerShort) },
+		"GenerateQnA":                   func() { GenerateQnA(n, QuestionTypeFactual, AnswerMedium) },
+		"GenerateSummary":               func() { GenerateSummary(n) },
+		"GenerateClozes":                func() { GenerateClozes(n, 3) },
+		"GenerateHint":                  func() { GenerateHint("What is a map?", n) },
+		"CompareAnswers":                func() { CompareAnswers("A list.", "A hash table.", "What is a map?") },
+		"ScoreAnswer":                   func() { ScoreAnswer("A list.", "A hash table.", "What is a map?") },
+		"GenerateReflectionChallenges":  func() { GenerateReflectionChallenges("Maps are lists.", n.Content, 1) },
+	}
+	for name, build := range builders {
+		t.Run(name, func(t *testing.T) {
+			recorder := recordPrompts(t)
+			build()
+			if len(recorder.prompts) == 0 {
+				t.Fatal("no prompt was sent")
+			}
+			if !strings.HasSuffix(recorder.prompts[0], directive) {
+				t.Errorf("prompt doesn't end with the language directive:\n%s", recorder.prompts[0])
+			}
+		})
+	}
+
+	t.Run("chat", func(t *testing.T) {
+		recorder := recordPrompts(t)
+		messages := []OllamaMessage{{Role: "system", Content: "You are a coach."}, {Role: "user", Content: "Hi"}}
+		if _, err := SendChatMessage(messages); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := SendChatMessageStream(messages, io.Discard); err != nil {
+			t.Fatal(err)
+		}
+		if len(recorder.systems) != 2 {
+			t.Fatalf("recorded %d system prompts, want 2", len(recorder.systems))
+		}
+		for _, system := range recorder.systems {
+			if system != "You are a coach."+directive {
+				t.Errorf("system prompt = %q, want the language directive appended", system)
+			}
+		}
+		if messages[0].Content != "You are a coach." {
+			t.Errorf("the caller's system message was changed to %q", messages[0].Content)
+		}
+	})
+}
+
+func TestEnglishHasNoLanguageDirective(t *testing.T) {
+	for _, language := range []string{"", "English", "en", " english "} {
+		SetLanguage(language)
+		if directive := LanguageDirective(); directive != "" {
+			t.Errorf("SetLanguage(%q) gave the directive %q, want none", language, directive)
+		}
+	}
+	recorder := recordPrompts(t)
+	if _, err := GenerateQuestion(&note.Note{Content: "Material."}, QuestionTypeFactual); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(recorder.prompts[0], "Write your entire response in") {
+		t.Errorf("English prompt has a language directive:\n%s", recorder.prompts[0])
+	}
+}
+
+func TestAnswerLengthInstructions(t *testing.T) {
+	n := &note.Note{Content: "Material."}
+	tests := []struct {
+		length  AnswerLength
+		want    string
+		notWant string
+	}{
+		{AnswerShort, "Answer in ONE sentence", "broader principle"},
+		{AnswerMedium, "Then explain the \"why\" or \"how\"", "ONE sentence"},
+		{AnswerLong, "common misconception", "ONE sentence"},
+		{"unknown", "Then explain the \"why\" or \"how\"", "common misconception"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.length), func(t *testing.T) {
+			if got := answerInstructions(tt.length); !strings.Contains(got, tt.want) || strings.Contains(got, tt.notWant) {
+				t.Errorf("answerInstructions(%q) = %q, want it to contain %q and not %q", tt.length, got, tt.want, tt.notWant)
+			}
+			// Both prompts that write an answer carry the instructions.
+			recorder := recordPrompts(t)
+			if _, err := GenerateAnswer("What is it?", n, tt.length); err != nil {
+				t.Fatal(err)
+			}
+			GenerateQnA(n, QuestionTypeFactual, tt.length)
+			for _, prompt := range recorder.prompts {
+				if !strings.Contains(prompt, answerInstructions(tt.length)) {
+					t.Errorf("prompt doesn't include the %s instructions:\n%s", tt.length, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAnswerLength(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    AnswerLength
+		wantErr bool
+	}{
+		{"", AnswerMedium, false},
+		{"short", AnswerShort, false},
+		{" Long ", AnswerLong, false},
+		{"MEDIUM", AnswerMedium, false},
+		{"brief", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAnswerLength(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAnswerLength(%q) = %q, %v; want %q, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestReflectionPromptRounds(t *testing.T) {
+	first := buildReflectionPrompt("Maps are hash tables.", "Source.", 1)
+	if strings.Contains(first, "reflection round #") {
+		t.Errorf("first round asks for new angles:\n%s", first)
+	}
+	for _, round := range []int{2, 3} {
+		prompt := buildReflectionPrompt("Maps are hash tables.", "Source.", round)
+		if want := fmt.Sprintf("This is reflection round #%d.", round); !strings.Contains(prompt, want) {
+			t.Errorf("round %d prompt is missing %q:\n%s", round, want, prompt)
+		}
+		if !strings.Contains(prompt, "Do not repeat earlier challenges") {
+			t.Errorf("round %d prompt doesn't ask for new challenges:\n%s", round, prompt)
+		}
+	}
+
+	recorder := recordPrompts(t)
+	if _, err := GenerateReflectionChallenges("Maps are hash tables.", "Source.", 4); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(recorder.prompts[0], "This is reflection round #4.") {
+		t.Errorf("GenerateReflectionChallenges didn't send the round:\n%s", recorder.prompts[0])
+	}
+}
+
+func TestGenerateHintPrompt(t *testing.T) {
+	recorder := recordPrompts(t)
+	n := &note.Note{Content: "# Maps\n\n## Summary\nGo maps are hash tables.\n\n## Details\nBuckets and overflow chains.\n"}
+	hint, err := GenerateHint("What data structure backs a Go map?", n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hint != "A question?" {
+		t.Errorf("GenerateHint() = %q, want the model's reply", hint)
+	}
+	prompt := recorder.prompts[0]
+	for _, want := range []string{
+		"QUESTION: What data structure backs a Go map?",
+		"Give ONE short sentence",
+		"Do NOT state the answer",
+		"Go maps are hash tables.",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("hint prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+	// Like questions and answers, hints only see the note's summary.
+	if strings.Contains(prompt, "Buckets and overflow chains.") {
+		t.Errorf("hint prompt has the whole note rather than its summary:\n%s", prompt)
+	}
+}
+
+func TestGenerateSummaryPrompt(t *testing.T) {
+	recorder := recordPrompts(t)
+	n := &note.Note{Title: "Maps", Content: "# Maps\n\n## Summary\nGo maps are hash tables.\n\n## Details\nBuckets and overflow chains.\n"}
+	if _, err := GenerateSummary(n); err != nil {
+		t.Fatal(err)
+	}
+	prompt := recorder.prompts[0]
+	// Unlike questions, a summary is made from the whole note.
+	for _, want := range []string{
+		"NOTE TITLE: Maps",
+		"Go maps are hash tables.",
+		"Buckets and overflow chains.",
+		"**Key takeaway:**",
+	} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("summary prompt is missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func TestSavedSummaryIsExtracted(t *testing.T) {
+	content := note.SetSection("# Maps\n\n## Details\nBuckets and overflow chains.\n", "Summary", "- Go maps are hash tables.")
+	if got := ExtractSummary(content); !strings.Contains(got, "Go maps are hash tables.") || strings.Contains(got, "Buckets") {
+		t.Errorf("ExtractSummary() of a saved summary = %q, want just the summary", got)
+	}
+}
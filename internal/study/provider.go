@@ -0,0 +1,423 @@
+// Package study contains logic related to the learning process, like SRS and LLM interaction.
+package study
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// DefaultOllamaModel is the model used when talking to a local Ollama server.
+const DefaultOllamaModel = "llama3:8b-instruct-q4_K_M"
+
+// Provider names accepted by NewProvider.
+const (
+	ProviderOllama = "ollama"
+	ProviderOpenAI = "openai"
+)
+
+// Environment variables used to configure the OpenAI-compatible provider.
+const (
+	EnvAPIKey        = "NEURON_API_KEY"
+	EnvOpenAIBaseURL = "NEURON_OPENAI_BASE_URL"
+	EnvOpenAIModel   = "NEURON_OPENAI_MODEL"
+)
+
+// Provider is an LLM backend capable of single-prompt generation and multi-turn chat.
+// opts may be nil, in which case the backend's own sampling defaults apply.
+type Provider interface {
+	Generate(prompt string, opts *OllamaOptions) (string, error)
+	Chat(messages []OllamaMessage, opts *OllamaOptions) (OllamaMessage, error)
+}
+
+// activeProvider is used by every study helper. It defaults to a local Ollama server.
+var activeProvider Provider = NewOllamaProvider()
+
+// SetProvider replaces the provider used by all study helpers.
+func SetProvider(p Provider) {
+	activeProvider = p
+}
+
+// NewProvider builds a provider by name, reading any credentials from the environment.
+// A non-empty model replaces the provider's default model.
+func NewProvider(name, model string) (Provider, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", ProviderOllama:
+		p := NewOllamaProvider()
+		if model != "" {
+			p.Model = model
+		}
+		return p, nil
+	case ProviderOpenAI:
+		apiKey := os.Getenv(EnvAPIKey)
+		if apiKey == "" {
+			return nil, fmt.Errorf("the openai provider requires an API key in $%s", EnvAPIKey)
+		}
+		p := &OpenAIProvider{
+			BaseURL: "https://api.openai.com",
+			Model:   "gpt-4o-mini",
+			APIKey:  apiKey,
+		}
+		if baseURL := os.Getenv(EnvOpenAIBaseURL); baseURL != "" {
+			p.BaseURL = baseURL
+		}
+		if envModel := os.Getenv(EnvOpenAIModel); envModel != "" {
+			p.Model = envModel
+		}
+		if model != "" {
+			p.Model = model
+		}
+		return p, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (valid providers: %s, %s)", name, ProviderOllama, ProviderOpenAI)
+	}
+}
+
+// ChatStreamer is implemented by providers that can stream a chat reply as it is generated.
+type ChatStreamer interface {
+	// ChatStream writes the reply's text to w as it arrives and returns the whole message.
+	ChatStream(messages []OllamaMessage, opts *OllamaOptions, w io.Writer) (OllamaMessage, error)
+}
+
+// ModelLister is implemented by providers that can report which models their server offers.
+type ModelLister interface {
+	// ActiveModel is the model requests are sent to.
+	ActiveModel() string
+	// ListModels returns the models the server can serve.
+	ListModels() ([]string, error)
+}
+
+// ListModels returns the models offered by the active provider's server.
+func ListModels() ([]string, error) {
+	lister, ok := activeProvider.(ModelLister)
+	if !ok {
+		return nil, fmt.Errorf("the active provider can't list its models")
+	}
+	return lister.ListModels()
+}
+
+// ActiveModel returns the model the active provider sends requests to, or "" if unknown.
+func ActiveModel() string {
+	if lister, ok := activeProvider.(ModelLister); ok {
+		return lister.ActiveModel()
+	}
+	return ""
+}
+
+// HasModel reports whether model is in models. Ollama lists untagged models
+// as "name:latest", so "llama3" matches "llama3:latest".
+func HasModel(models []string, model string) bool {
+	for _, m := range models {
+		if m == model || (!strings.Contains(model, ":") && m == model+":latest") {
+			return true
+		}
+	}
+	return false
+}
+
+// getJSON sends req and decodes the JSON response body into v, failing on non-200 responses.
+func getJSON(req *http.Request, v any) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", req.URL, resp.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal response from %s: %w", req.URL, err)
+	}
+	return nil
+}
+
+// OllamaProvider talks to the Ollama /api/generate and /api/chat endpoints.
+type OllamaProvider struct {
+	BaseURL string
+	Model   string
+}
+
+// NewOllamaProvider returns a provider for a local Ollama server using the default model.
+func NewOllamaProvider() *OllamaProvider {
+	return &OllamaProvider{BaseURL: "http://localhost:11434", Model: DefaultOllamaModel}
+}
+
+// Generate sends a single prompt to the /api/generate endpoint.
+func (p *OllamaProvider) Generate(prompt string, opts *OllamaOptions) (string, error) {
+	payload := OllamaRequest{Model: p.Model, Prompt: prompt, Stream: false, Options: opts}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.Post(p.BaseURL+"/api/generate", "application/json", bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return "", fmt.Errorf("failed to send request to ollama: %w. Is Ollama running?", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	response, err := decodeGenerateResponse(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal ollama response: %w. Response was: %s", err, string(body))
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// decodeGenerateResponse reads a /api/generate body. It is normally one JSON
+// object, but some models and proxies send newline-delimited chunks even with
+// streaming off, so every object in the body is read and their text joined.
+func decodeGenerateResponse(body []byte) (string, error) {
+	var response strings.Builder
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var chunk OllamaResponse
+		err := decoder.Decode(&chunk)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		response.WriteString(chunk.Response)
+		if chunk.Done {
+			break
+		}
+	}
+	return response.String(), nil
+}
+
+// Chat sends a conversation to the /api/chat endpoint.
+func (p *OllamaProvider) Chat(messages []OllamaMessage, opts *OllamaOptions) (OllamaMessage, error) {
+	payload := OllamaChatRequest{
+		Model:    p.Model,
+		Messages: messages,
+		Stream:   false,
+		Options:  opts,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return OllamaMessage{}, err
+	}
+	resp, err := http.Post(p.BaseURL+"/api/chat", "application/json", bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return OllamaMessage{}, fmt.Errorf("failed to send chat request to ollama: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OllamaMessage{}, err
+	}
+	var ollamaResp OllamaChatResponse
+	if err := json.Unmarshal(body, &ollamaResp); err != nil {
+		return OllamaMessage{}, fmt.Errorf("failed to unmarshal ollama chat response: %w. Response was: %s", err, string(body))
+	}
+	return ollamaResp.Message, nil
+}
+
+// ChatStream sends a conversation to the /api/chat endpoint with streaming on,
+// writing each chunk of the reply to w as it arrives.
+func (p *OllamaProvider) ChatStream(messages []OllamaMessage, opts *OllamaOptions, w io.Writer) (OllamaMessage, error) {
+	payload := OllamaChatRequest{
+		Model:    p.Model,
+		Messages: messages,
+		Stream:   true,
+		Options:  opts,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return OllamaMessage{}, err
+	}
+	resp, err := http.Post(p.BaseURL+"/api/chat", "application/json", bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return OllamaMessage{}, fmt.Errorf("failed to send chat request to ollama: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return OllamaMessage{}, fmt.Errorf("ollama chat failed (%s): %s", resp.Status, string(body))
+	}
+	return decodeChatStream(resp.Body, w)
+}
+
+// ollamaChatChunk is one line of a streamed /api/chat response.
+type ollamaChatChunk struct {
+	OllamaChatResponse
+	Error string `json:"error,omitempty"`
+}
+
+// decodeChatStream reads the newline-delimited JSON chunks of a streamed chat reply,
+// copying each piece of text to w, and returns the assembled message.
+func decodeChatStream(r io.Reader, w io.Writer) (OllamaMessage, error) {
+	reply := OllamaMessage{Role: "assistant"}
+	var content strings.Builder
+	decoder := json.NewDecoder(r)
+	for {
+		var chunk ollamaChatChunk
+		err := decoder.Decode(&chunk)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return OllamaMessage{}, fmt.Errorf("failed to decode ollama chat stream: %w", err)
+		}
+		if chunk.Error != "" {
+			return OllamaMessage{}, fmt.Errorf("ollama chat failed: %s", chunk.Error)
+		}
+		if chunk.Message.Role != "" {
+			reply.Role = chunk.Message.Role
+		}
+		content.WriteString(chunk.Message.Content)
+		if _, err := io.WriteString(w, chunk.Message.Content); err != nil {
+			return OllamaMessage{}, err
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	reply.Content = content.String()
+	return reply, nil
+}
+
+// ActiveModel returns the model the provider sends requests to.
+func (p *OllamaProvider) ActiveModel() string {
+	return p.Model
+}
+
+// ListModels returns the models pulled into the Ollama server, from /api/tags.
+func (p *OllamaProvider) ListModels() ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, p.BaseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := getJSON(req, &tags); err != nil {
+		return nil, fmt.Errorf("failed to list ollama models: %w. Is Ollama running?", err)
+	}
+	models := make([]string, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		models = append(models, m.Name)
+	}
+	return models, nil
+}
+
+// OpenAIProvider talks to any server implementing the OpenAI /v1/chat/completions API
+// (OpenAI, LiteLLM, vLLM, OpenRouter, ...).
+type OpenAIProvider struct {
+	BaseURL string
+	Model   string
+	APIKey  string
+}
+
+// openAIChatRequest is the JSON payload for /v1/chat/completions.
+// The sampling fields mirror OllamaOptions, which OpenAI takes at the top level.
+type openAIChatRequest struct {
+	Model       string          `json:"model"`
+	Messages    []OllamaMessage `json:"messages"`
+	Stream      bool            `json:"stream"`
+	Temperature *float64        `json:"temperature,omitempty"`
+	TopP        *float64        `json:"top_p,omitempty"`
+	Seed        *int            `json:"seed,omitempty"`
+}
+
+// openAIChatResponse holds the parts of a /v1/chat/completions response we use.
+type openAIChatResponse struct {
+	Choices []struct {
+		Message OllamaMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Generate wraps the prompt in a single user message and sends it as a chat.
+func (p *OpenAIProvider) Generate(prompt string, opts *OllamaOptions) (string, error) {
+	msg, err := p.Chat([]OllamaMessage{{Role: "user", Content: prompt}}, opts)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(msg.Content), nil
+}
+
+// Chat posts the conversation to /v1/chat/completions and maps the first choice back into an OllamaMessage.
+func (p *OpenAIProvider) Chat(messages []OllamaMessage, opts *OllamaOptions) (OllamaMessage, error) {
+	payload := openAIChatRequest{Model: p.Model, Messages: messages, Stream: false}
+	if opts != nil {
+		payload.Temperature, payload.TopP, payload.Seed = opts.Temperature, opts.TopP, opts.Seed
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return OllamaMessage{}, err
+	}
+	url := strings.TrimSuffix(p.BaseURL, "/") + "/v1/chat/completions"
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return OllamaMessage{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return OllamaMessage{}, fmt.Errorf("failed to send chat request to %s: %w", p.BaseURL, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OllamaMessage{}, err
+	}
+	var chatResp openAIChatResponse
+	if err := json.Unmarshal(body, &chatResp); err != nil {
+		return OllamaMessage{}, fmt.Errorf("failed to unmarshal chat completion response: %w. Response was: %s", err, string(body))
+	}
+	if chatResp.Error != nil {
+		return OllamaMessage{}, fmt.Errorf("chat completion failed (%s): %s", resp.Status, chatResp.Error.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return OllamaMessage{}, fmt.Errorf("chat completion failed (%s): %s", resp.Status, string(body))
+	}
+	if len(chatResp.Choices) == 0 {
+		return OllamaMessage{}, fmt.Errorf("chat completion returned no choices. Response was: %s", string(body))
+	}
+	return chatResp.Choices[0].Message, nil
+}
+
+// ActiveModel returns the model the provider sends requests to.
+func (p *OpenAIProvider) ActiveModel() string {
+	return p.Model
+}
+
+// ListModels returns the models the server offers, from /v1/models.
+func (p *OpenAIProvider) ListModels() ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.BaseURL, "/")+"/v1/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := getJSON(req, &list); err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+	models := make([]string, 0, len(list.Data))
+	for _, m := range list.Data {
+		models = append(models, m.ID)
+	}
+	return models, nil
+}
@@ -0,0 +1,461 @@
+package study
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// useTestServer points the study helpers at an Ollama provider on a test server
+// that serves handler, and returns how many requests it received.
+func useTestServer(t *testing.T, handler http.HandlerFunc) *atomic.Int32 {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	SetProvider(&OllamaProvider{BaseURL: server.URL, Model: "test"})
+	t.Cleanup(func() { SetProvider(NewOllamaProvider()) })
+	return &requests
+}
+
+func TestEmptyResponseIsAnError(t *testing.T) {
+	requests := useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":"","done":true}`))
+	})
+	_, err := sendOllamaRequest(OllamaRequest{Prompt: "hello"})
+	if !errors.Is(err, ErrEmptyResponse) {
+		t.Fatalf("got err %v, want ErrEmptyResponse", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("server got %d requests, want 2 (one retry)", got)
+	}
+}
+
+func TestEmptyChatResponseIsAnError(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message":{"role":"assistant","content":"  \n"},"done":true}`))
+	})
+	if _, err := SendChatMessage([]OllamaMessage{{Role: "user", Content: "hi"}}); !errors.Is(err, ErrEmptyResponse) {
+		t.Fatalf("got err %v, want ErrEmptyResponse", err)
+	}
+}
+
+func TestEmptyResponseIsRetried(t *testing.T) {
+	var calls atomic.Int32
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Write([]byte(`{"response":"","done":true}`))
+			return
+		}
+		w.Write([]byte(`{"response":"second try","done":true}`))
+	})
+	got, err := sendOllamaRequest(OllamaRequest{Prompt: "hello"})
+	if err != nil || got != "second try" {
+		t.Fatalf("got %q, %v; want the retried reply", got, err)
+	}
+}
+
+// maxInFlight sends requests at once through sendOllamaRequest and returns the
+// most the test server was handling at the same time.
+func maxInFlight(t *testing.T, requests int) int32 {
+	t.Helper()
+	var inFlight, most atomic.Int32
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := most.Load()
+			if n <= m || most.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte(`{"response":"ok","done":true}`))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sendOllamaRequest(OllamaRequest{Prompt: "hello"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	return most.Load()
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	t.Cleanup(func() { SetMaxConcurrency(0) })
+	tests := []struct {
+		max  int
+		want int32
+	}{
+		{0, DefaultMaxConcurrency},
+		{1, 1},
+		{3, 3},
+	}
+	for _, tt := range tests {
+		SetMaxConcurrency(tt.max)
+		if got := maxInFlight(t, 6); got != tt.want {
+			t.Errorf("SetMaxConcurrency(%d): %d requests in flight at once, want %d", tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestRequestsUseTheProviderModel(t *testing.T) {
+	var models []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		models = append(models, body.Model)
+		w.Write([]byte(`{"response":"ok","done":true,"message":{"role":"assistant","content":"ok"}}`))
+	})
+	if _, err := sendOllamaRequest(OllamaRequest{Prompt: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SendChatMessage([]OllamaMessage{{Role: "user", Content: "hello"}}); err != nil {
+		t.Fatal(err)
+	}
+	for _, model := range models {
+		if model != "test" {
+			t.Errorf("request sent to model %q, want the provider's %q", model, "test")
+		}
+	}
+}
+
+func TestDecodeGenerateResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"one object", `{"response":"Hello there.","done":true}`, "Hello there.", false},
+		{"newline-delimited chunks", "{\"response\":\"Hel\",\"done\":false}\n{\"response\":\"lo \",\"done\":false}\n{\"response\":\"there.\",\"done\":true}\n", "Hello there.", false},
+		{"stops at done", "{\"response\":\"Hi\",\"done\":true}\n{\"response\":\" extra\",\"done\":true}\n", "Hi", false},
+		{"no done marker", "{\"response\":\"a\"}\n{\"response\":\"b\"}", "ab", false},
+		{"empty body", "", "", false},
+		{"malformed", `{"response":`, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeGenerateResponse([]byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateReadsChunkedResponses(t *testing.T) {
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"response\":\"What is \",\"done\":false}\n{\"response\":\"Go?\",\"done\":true}\n"))
+	})
+	got, err := sendOllamaRequest(OllamaRequest{Prompt: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "What is Go?" {
+		t.Errorf("got %q, want %q", got, "What is Go?")
+	}
+}
+
+func TestModelAvailabilityChecks(t *testing.T) {
+	tags := func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"models":[{"name":"llama3:latest"},{"name":"qwen2.5:7b"}]}`))
+	}
+	tests := []struct {
+		name      string
+		model     string
+		handler   http.HandlerFunc
+		reachable bool
+		present   bool
+	}{
+		{"model present", "qwen2.5:7b", tags, true, true},
+		{"untagged model present as latest", "llama3", tags, true, true},
+		{"model missing", "mistral", tags, true, false},
+		{"server error", "llama3", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestServer(t, tt.handler)
+			models, err := ListModels()
+			if (err == nil) != tt.reachable {
+				t.Fatalf("ListModels err = %v, want reachable %v", err, tt.reachable)
+			}
+			if got := HasModel(models, tt.model); got != tt.present {
+				t.Errorf("HasModel(%q) = %v, want %v", tt.model, got, tt.present)
+			}
+		})
+	}
+}
+
+func TestListModels(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{"several models", `{"models":[{"name":"llama3:latest","size":1},{"name":"qwen2.5:7b"}]}`, []string{"llama3:latest", "qwen2.5:7b"}, false},
+		{"no models pulled", `{"models":[]}`, []string{}, false},
+		{"malformed", `{"models":`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/api/tags" {
+					t.Errorf("request %s %s, want GET /api/tags", r.Method, r.URL.Path)
+				}
+				w.Write([]byte(tt.body))
+			})
+			got, err := ListModels()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListModels() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Errorf("ListModels() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListModelsNeedsAModelLister(t *testing.T) {
+	recordPrompts(t)
+	if models, err := ListModels(); err == nil {
+		t.Errorf("ListModels() = %q with a provider that can't list models, want an error", models)
+	}
+}
+
+func TestListModelsUnreachableServer(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	SetProvider(&OllamaProvider{BaseURL: server.URL, Model: "test"})
+	t.Cleanup(func() { SetProvider(NewOllamaProvider()) })
+	if _, err := ListModels(); err == nil {
+		t.Error("ListModels succeeded against a server that isn't running")
+	}
+}
+
+// recordOptions points the study helpers at a test server and returns the raw
+// "options" object of every request it receives, or "" when a request has none.
+func recordOptions(t *testing.T) *[]string {
+	t.Helper()
+	var mu sync.Mutex
+	var options []string
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Options json.RawMessage `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		options = append(options, string(body.Options))
+		mu.Unlock()
+		w.Write([]byte(`{"response":"ok","done":true,"message":{"role":"assistant","content":"ok"}}`))
+	})
+	return &options
+}
+
+func TestRequestOptions(t *testing.T) {
+	t.Cleanup(func() {
+		SetDefaultOptions(OllamaOptions{})
+		SetTemperatureOverride(nil)
+	})
+	topP, override := 0.9, 0.3
+	tests := []struct {
+		name     string
+		defaults OllamaOptions
+		override *float64
+		task     *float64
+		want     string
+	}{
+		{name: "nothing set", want: ""},
+		{name: "defaults", defaults: OllamaOptions{TopP: &topP}, want: `{"top_p":0.9}`},
+		{name: "task temperature", defaults: OllamaOptions{TopP: &topP}, task: ptr(QuestionTemperature), want: `{"temperature":0.8,"top_p":0.9}`},
+		{name: "override beats the task", override: &override, task: ptr(QuestionTemperature), want: `{"temperature":0.3}`},
+		{name: "override without a task", override: &override, want: `{"temperature":0.3}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetDefaultOptions(tt.defaults)
+			SetTemperatureOverride(tt.override)
+			options := recordOptions(t)
+			if _, err := sendOllamaRequest(OllamaRequest{Prompt: "hello", Options: requestOptions(tt.task)}); err != nil {
+				t.Fatal(err)
+			}
+			if got := (*options)[0]; got != tt.want {
+				t.Errorf("options sent = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskTemperaturesReachOllama(t *testing.T) {
+	options := recordOptions(t)
+	n := &note.Note{Content: "Material."}
+	GenerateQuestion(n, QuestionTypeFactual)
+	ScoreAnswer("A list.", "A hash table.", "What is a map?")
+	want := []string{`{"temperature":0.8}`, `{"temperature":0.1}`}
+	if !slices.Equal(*options, want) {
+		t.Errorf("options sent = %q, want %q", *options, want)
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestSeedIsSentWithEveryRequest(t *testing.T) {
+	seed := 42
+	SetDefaultOptions(OllamaOptions{Seed: &seed})
+	t.Cleanup(func() { SetDefaultOptions(OllamaOptions{}) })
+	options := recordOptions(t)
+
+	n := &note.Note{Content: "Material."}
+	messages := []OllamaMessage{{Role: "user", Content: "hello"}}
+	GenerateQuestion(n, QuestionTypeFactual)
+	GenerateAnswer("What is it?", n, AnswerMedium)
+	ScoreAnswer("A list.", "A hash table.", "What is a map?")
+	if _, err := SendChatMessage(messages); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SendChatMessageStream(messages, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*options) != 5 {
+		t.Fatalf("server got %d requests, want 5", len(*options))
+	}
+	for i, got := range *options {
+		var sent OllamaOptions
+		if err := json.Unmarshal([]byte(got), &sent); err != nil || sent.Seed == nil || *sent.Seed != seed {
+			t.Errorf("request %d sent options %s, want seed %d", i+1, got, seed)
+		}
+	}
+}
+
+// chunkWriter keeps each write separately, to show what was streamed when.
+type chunkWriter struct {
+	chunks []string
+	err    error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.chunks = append(w.chunks, string(p))
+	return len(p), nil
+}
+
+func TestDecodeChatStream(t *testing.T) {
+	chunk := func(content string, done bool) string {
+		return fmt.Sprintf(`{"message":{"role":"assistant","content":%q},"done":%t}`+"\n", content, done)
+	}
+	tests := []struct {
+		name       string
+		body       string
+		want       string
+		wantChunks []string
+		wantErr    bool
+	}{
+		{"one chunk", chunk("Hello there.", true), "Hello there.", []string{"Hello there."}, false},
+		{"several chunks", chunk("Hel", false) + chunk("lo ", false) + chunk("there.", true), "Hello there.", []string{"Hel", "lo ", "there."}, false},
+		{"stops at done", chunk("Hi", true) + chunk(" extra", true), "Hi", []string{"Hi"}, false},
+		{"no done marker", chunk("a", false) + chunk("b", false), "ab", []string{"a", "b"}, false},
+		{"final chunk without content", chunk("Hi", false) + `{"done":true}` + "\n", "Hi", []string{"Hi", ""}, false},
+		{"error mid-stream", chunk("Hal", false) + `{"error":"model unloaded"}` + "\n", "", []string{"Hal"}, true},
+		{"malformed", chunk("Hal", false) + `{"message":`, "", []string{"Hal"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &chunkWriter{}
+			got, err := decodeChatStream(strings.NewReader(tt.body), w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if got.Content != tt.want {
+				t.Errorf("content = %q, want %q", got.Content, tt.want)
+			}
+			if !tt.wantErr && got.Role != "assistant" {
+				t.Errorf("role = %q, want assistant", got.Role)
+			}
+			if !slices.Equal(w.chunks, tt.wantChunks) {
+				t.Errorf("wrote %q, want %q", w.chunks, tt.wantChunks)
+			}
+		})
+	}
+}
+
+func TestDecodeChatStreamWriteError(t *testing.T) {
+	broken := errors.New("broken pipe")
+	_, err := decodeChatStream(strings.NewReader(`{"message":{"content":"Hi"},"done":true}`), &chunkWriter{err: broken})
+	if !errors.Is(err, broken) {
+		t.Errorf("error = %v, want the writer's error", err)
+	}
+}
+
+func TestChatStreamWritesChunksAsTheyArrive(t *testing.T) {
+	// The server sends its second chunk only after the first has been written.
+	firstWritten := make(chan struct{})
+	useTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Stream bool `json:"stream"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !body.Stream {
+			t.Error("chat request didn't ask for a stream")
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"Maps are "},"done":false}` + "\n"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-firstWritten:
+		case <-time.After(2 * time.Second):
+			t.Error("the first chunk wasn't written before the reply ended")
+		}
+		w.Write([]byte(`{"message":{"role":"assistant","content":"hash tables."},"done":true}` + "\n"))
+	})
+
+	w := &signalWriter{first: firstWritten}
+	reply, err := SendChatMessageStream([]OllamaMessage{{Role: "user", Content: "hi"}}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Content != "Maps are hash tables." || w.text.String() != reply.Content {
+		t.Errorf("reply %q, streamed %q; want both to be the whole reply", reply.Content, w.text.String())
+	}
+}
+
+// signalWriter closes first after its first write.
+type signalWriter struct {
+	text  strings.Builder
+	first chan struct{}
+}
+
+func (w *signalWriter) Write(p []byte) (int, error) {
+	if w.text.Len() == 0 {
+		defer close(w.first)
+	}
+	return w.text.Write(p)
+}
@@ -0,0 +1,49 @@
+// Package study contains logic related to the learning process, like SRS and LLM interaction.
+package study
+
+import "time"
+
+// SessionStats tallies the ratings given during a study session and how long it took.
+type SessionStats struct {
+	Start  time.Time
+	Again  int
+	Good   int
+	Easy   int
+	finish time.Time
+}
+
+// NewSessionStats starts timing a session now.
+func NewSessionStats() *SessionStats {
+	return &SessionStats{Start: time.Now()}
+}
+
+// Record counts one rating. Ratings outside 1-3 are ignored.
+func (s *SessionStats) Record(rating int) {
+	switch rating {
+	case RatingAgain:
+		s.Again++
+	case RatingGood:
+		s.Good++
+	case RatingEasy:
+		s.Easy++
+	}
+}
+
+// Reviewed returns how many cards were rated.
+func (s *SessionStats) Reviewed() int {
+	return s.Again + s.Good + s.Easy
+}
+
+// Finish stops the session clock. Elapsed uses the current time until it is called.
+func (s *SessionStats) Finish() {
+	s.finish = time.Now()
+}
+
+// Elapsed returns the session duration, rounded to the second.
+func (s *SessionStats) Elapsed() time.Duration {
+	end := s.finish
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(s.Start).Round(time.Second)
+}
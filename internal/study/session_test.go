@@ -0,0 +1,31 @@
+package study
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionStatsRecord(t *testing.T) {
+	stats := NewSessionStats()
+	for _, rating := range []int{RatingGood, RatingAgain, RatingEasy, RatingGood, 0, 4, -1, RatingGood} {
+		stats.Record(rating)
+	}
+	if stats.Again != 1 || stats.Good != 3 || stats.Easy != 1 {
+		t.Errorf("tally = %d Again, %d Good, %d Easy; want 1, 3, 1", stats.Again, stats.Good, stats.Easy)
+	}
+	if got := stats.Reviewed(); got != 5 {
+		t.Errorf("Reviewed() = %d, want 5; ratings outside 1-3 don't count", got)
+	}
+}
+
+func TestSessionStatsElapsed(t *testing.T) {
+	stats := &SessionStats{Start: time.Now().Add(-90 * time.Second)}
+	if got := stats.Elapsed(); got != 90*time.Second {
+		t.Errorf("Elapsed() of a running session = %s, want 1m30s", got)
+	}
+	stats.Finish()
+	stats.Start = stats.Start.Add(-time.Minute)
+	if got := stats.Elapsed(); got != 150*time.Second {
+		t.Errorf("Elapsed() after Finish = %s, want 2m30s", got)
+	}
+}
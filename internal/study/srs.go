@@ -2,7 +2,11 @@
 package study
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/soyomarvaldezg/neuron-cli/internal/note"
@@ -15,32 +19,329 @@ const (
 	RatingEasy  = 3 // Recalled with no effort.
 )
 
+// IsNew reports whether a note has never been reviewed. Notes without a state
+// count as new while they still have their initial interval.
+func IsNew(n *note.Note) bool {
+	if n.State == "" {
+		return n.Interval <= 1.0
+	}
+	return n.State == note.StateNew
+}
+
+// SchedulerConfig holds the tunable parameters of the SRS algorithm.
+type SchedulerConfig struct {
+	EaseFloor    float64 // Lowest ease factor a note can reach.
+	EaseCeiling  float64 // Highest ease factor a note can reach.
+	AgainPenalty float64 // Subtracted from the ease factor on "Again".
+	EasyBonus    float64 // Added to the ease factor on "Easy".
+	Fuzz         bool    // Jitter due dates by up to ±5% so notes learned together spread out.
+
+	// TargetRetention is the share of reviews you aim to recall correctly.
+	// Higher values shorten intervals, lower values lengthen them.
+	TargetRetention float64
+
+	// LeechThreshold is the number of lapses after which a note is tagged as a leech. Zero disables it.
+	LeechThreshold int
+
+	// LearningSteps are the delays a new note goes through before it graduates to
+	// day-based intervals, such as 1m, 10m and 1d. With none, notes graduate on
+	// their first review.
+	LearningSteps []time.Duration
+
+	// LapseInterval is when a reviewed note rated "Again" comes back, such as 10m
+	// for later in the session. Zero means the default of one day.
+	LapseInterval time.Duration
+
+	// TagEase is the starting ease factor of new notes with a tag, keyed by
+	// lowercase tag. Notes without a listed tag start at note.DefaultEaseFactor.
+	TagEase map[string]float64
+}
+
+// LeechTag marks notes that keep being forgotten and probably need rewriting.
+const LeechTag = note.LeechTag
+
+// DefaultLeechThreshold is the lapse count at which a note becomes a leech.
+const DefaultLeechThreshold = 8
+
+// Below MatureInterval days, a recalled note's interval grows by
+// YoungIntervalMultiplier; from then on, by its ease factor.
+const (
+	YoungIntervalMultiplier = 1.6
+	MatureInterval          = 6.0
+)
+
+// MaxEaseAdjustment bounds the configurable Again penalty and Easy bonus.
+const MaxEaseAdjustment = 1.0
+
+// ValidateEaseAdjustment reports whether v can be used as the Again penalty or
+// Easy bonus named name.
+func ValidateEaseAdjustment(name string, v float64) error {
+	if v < 0 || v > MaxEaseAdjustment {
+		return fmt.Errorf("%s must be between 0 and %.1f, got %g", name, MaxEaseAdjustment, v)
+	}
+	return nil
+}
+
+// Intervals, in days, given to a note when it graduates from the learning steps.
+const (
+	graduatingInterval     = 1.0 // after passing the last step
+	easyGraduatingInterval = 4.0 // after rating a learning step "Easy"
+)
+
+// ParseTagEase validates per-tag starting ease factors against the config's ease
+// bounds and returns them keyed by lowercase tag.
+func (c SchedulerConfig) ParseTagEase(byTag map[string]float64) (map[string]float64, error) {
+	parsed := make(map[string]float64, len(byTag))
+	for tag, ease := range byTag {
+		if ease < c.EaseFloor || ease > c.EaseCeiling {
+			return nil, fmt.Errorf("ease for tag %q must be between %.1f and %.1f, got %g", tag, c.EaseFloor, c.EaseCeiling, ease)
+		}
+		parsed[strings.ToLower(strings.TrimSpace(tag))] = ease
+	}
+	return parsed, nil
+}
+
+// InitialEase is the ease factor a new note with these tags starts at: the lowest
+// configured for any of its tags, so the harder subject wins.
+func InitialEase(tags []string) float64 {
+	ease, found := note.DefaultEaseFactor, false
+	for _, tag := range tags {
+		if e, ok := scheduler.TagEase[strings.ToLower(tag)]; ok && (!found || e < ease) {
+			ease, found = e, true
+		}
+	}
+	return ease
+}
+
+// ParseLearningSteps parses learning step delays with ParseDelay.
+func ParseLearningSteps(steps []string) ([]time.Duration, error) {
+	var delays []time.Duration
+	for _, step := range steps {
+		delay, err := ParseDelay(step)
+		if err != nil {
+			return nil, fmt.Errorf("invalid learning step %q: %w", strings.TrimSpace(step), err)
+		}
+		delays = append(delays, delay)
+	}
+	return delays, nil
+}
+
+// ParseDelay parses a scheduling delay: a Go duration such as 10m or 1h30m, or
+// whole days such as 3d.
+func ParseDelay(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	delay, err := time.ParseDuration(s)
+	if err != nil {
+		days, dayErr := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if dayErr != nil || !strings.HasSuffix(s, "d") {
+			return 0, fmt.Errorf("use a duration like 10m, 1h or 1d")
+		}
+		delay = time.Duration(days) * 24 * time.Hour
+	}
+	if delay <= 0 {
+		return 0, fmt.Errorf("it must be longer than zero")
+	}
+	return delay, nil
+}
+
+// DefaultTargetRetention is the retention the base intervals are tuned for.
+const DefaultTargetRetention = 0.9
+
+// Target retention must stay within these bounds to keep intervals sensible.
+const (
+	MinTargetRetention = 0.7
+	MaxTargetRetention = 0.99
+)
+
+// ValidateTargetRetention reports whether r can be used as a target retention.
+func ValidateTargetRetention(r float64) error {
+	if r < MinTargetRetention || r > MaxTargetRetention {
+		return fmt.Errorf("target retention must be between %.2f and %.2f, got %.2f", MinTargetRetention, MaxTargetRetention, r)
+	}
+	return nil
+}
+
+// retentionFactor scales intervals for a target retention. Under an exponential
+// forgetting curve, the time until recall drops to r is proportional to ln(r),
+// so the factor is 1 at the default target, below 1 above it and above 1 below it.
+func retentionFactor(r float64) float64 {
+	if r <= 0 || r >= 1 {
+		return 1
+	}
+	return math.Log(r) / math.Log(DefaultTargetRetention)
+}
+
+// DefaultSchedulerConfig returns the SM-2 style defaults used by Neuron CLI.
+func DefaultSchedulerConfig() SchedulerConfig {
+	return SchedulerConfig{
+		EaseFloor:    1.3,
+		EaseCeiling:  3.0,
+		AgainPenalty: 0.2,
+		EasyBonus:    0.15,
+
+		TargetRetention: DefaultTargetRetention,
+		LeechThreshold:  DefaultLeechThreshold,
+	}
+}
+
+// scheduler is used by UpdateSRSData.
+var scheduler = DefaultSchedulerConfig()
+
+// Fuzz only applies to intervals longer than fuzzMinInterval days, where a day
+// or so of jitter doesn't matter but still breaks up review pile-ups.
+const (
+	fuzzFactor      = 0.05
+	fuzzMinInterval = 3.0
+)
+
+// fuzzRand is the source of due date jitter. Use SeedFuzz for reproducible schedules.
+var fuzzRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// SeedFuzz reseeds the due date jitter so the same ratings yield the same schedule.
+func SeedFuzz(seed int64) {
+	fuzzRand = rand.New(rand.NewSource(seed))
+}
+
+// SetSchedulerConfig replaces the parameters used by UpdateSRSData.
+func SetSchedulerConfig(c SchedulerConfig) {
+	scheduler = c
+}
+
+// CurrentSchedulerConfig returns the parameters UpdateSRSData is using.
+func CurrentSchedulerConfig() SchedulerConfig {
+	return scheduler
+}
+
 // UpdateSRSData calculates the next review date for a note based on user performance.
 // Note that this function is EXPORTED (starts with a capital U).
 func UpdateSRSData(n *note.Note, rating int) {
+	if len(scheduler.LearningSteps) > 0 && (IsNew(n) || n.State == note.StateLearning) {
+		updateLearning(n, rating)
+		return
+	}
+	n.State = note.StateReview
+	n.LearningStep = 0
+
 	// 1. If rating is "Again", reset the interval.
 	if rating == RatingAgain {
 		n.Interval = 1 // Reset to 1 day
+		n.Lapses++
 		// We slightly decrease the ease factor to acknowledge difficulty
-		n.EaseFactor = math.Max(1.3, n.EaseFactor-0.2)
+		n.EaseFactor -= scheduler.AgainPenalty
 	} else {
 		// 2. For "Good" or "Easy", calculate the new interval.
 		if n.Interval < 1 {
 			n.Interval = 1
-		} else if n.Interval < 6 {
-			n.Interval = math.Ceil(n.Interval * 1.6)
+		} else if n.Interval < MatureInterval {
+			n.Interval = math.Ceil(n.Interval * YoungIntervalMultiplier)
 		} else {
 			n.Interval = math.Ceil(n.Interval * n.EaseFactor)
 		}
 
 		// 3. Adjust the ease factor. Only "Easy" increases it.
 		if rating == RatingEasy {
-			n.EaseFactor += 0.15
+			n.EaseFactor += scheduler.EasyBonus
 		}
 	}
 
+	// Keep the ease factor within bounds so intervals can't collapse or explode.
+	n.EaseFactor = math.Min(scheduler.EaseCeiling, math.Max(scheduler.EaseFloor, n.EaseFactor))
+
+	// A configured lapse interval is used as-is, even under a day, so a
+	// forgotten note can come back later in the same session.
+	if rating == RatingAgain && scheduler.LapseInterval > 0 {
+		n.Interval = scheduler.LapseInterval.Hours() / 24
+		n.DueDate = time.Now().Add(scheduler.LapseInterval)
+		return
+	}
+
 	// 4. Set the next due date.
+	setDueDate(n)
+}
+
+// updateLearning moves a new or learning note through the learning steps. "Again"
+// goes back to the first step, "Good" moves to the next one and "Easy" graduates
+// at once. Passing the last step graduates the note to day-based intervals.
+func updateLearning(n *note.Note, rating int) {
+	switch rating {
+	case RatingAgain:
+		n.LearningStep = 0
+	case RatingEasy:
+		graduate(n, easyGraduatingInterval)
+		return
+	default:
+		n.LearningStep++
+	}
+	if n.LearningStep >= len(scheduler.LearningSteps) {
+		graduate(n, graduatingInterval)
+		return
+	}
+	n.State = note.StateLearning
+	n.DueDate = time.Now().Add(scheduler.LearningSteps[n.LearningStep])
+}
+
+// graduate moves a learning note to the review state with the given interval in days.
+func graduate(n *note.Note, interval float64) {
+	n.State = note.StateReview
+	n.LearningStep = 0
+	n.Interval = interval
+	setDueDate(n)
+}
+
+// setDueDate schedules the note n.Interval days from now.
+func setDueDate(n *note.Note) {
 	// Interval is in days, so we multiply by 24 hours.
-	duration := time.Hour * 24 * time.Duration(n.Interval)
+	// The stored interval stays at the default-retention value; only the due date
+	// is scaled, so changing the target later doesn't compound on past reviews.
+	days := math.Max(1, n.Interval*retentionFactor(scheduler.TargetRetention))
+	duration := time.Duration(days * float64(24*time.Hour))
+	if scheduler.Fuzz && n.Interval > fuzzMinInterval {
+		jitter := (fuzzRand.Float64()*2 - 1) * fuzzFactor
+		duration += time.Duration(float64(duration) * jitter)
+	}
 	n.DueDate = time.Now().Add(duration)
 }
+
+// SimulatedReview is a note's schedule after one rating in SimulateReviews.
+type SimulatedReview struct {
+	Rating     int
+	State      string
+	Interval   float64 // Days
+	EaseFactor float64
+	DueIn      time.Duration // Until the next review, as scheduled by the due date
+}
+
+// SimulateReviews rates a new note with the given ease factor through ratings
+// with UpdateSRSData, as if each review happened on time, and returns the
+// schedule after every step. No stored note is touched.
+func SimulateReviews(ratings []int, ease float64) []SimulatedReview {
+	n := &note.Note{Interval: note.DefaultInterval, EaseFactor: ease, State: note.StateNew}
+	steps := make([]SimulatedReview, 0, len(ratings))
+	for _, rating := range ratings {
+		reviewed := time.Now()
+		UpdateSRSData(n, rating)
+		steps = append(steps, SimulatedReview{
+			Rating:     rating,
+			State:      n.State,
+			Interval:   n.Interval,
+			EaseFactor: n.EaseFactor,
+			DueIn:      n.DueDate.Sub(reviewed),
+		})
+	}
+	return steps
+}
+
+// IsLeech reports whether a note has been tagged as a leech.
+func IsLeech(n *note.Note) bool {
+	return n.HasTag(LeechTag)
+}
+
+// MarkLeech tags the note as a leech once its lapses reach the threshold.
+// It returns true only when the tag was newly added.
+func MarkLeech(n *note.Note) bool {
+	if scheduler.LeechThreshold <= 0 || n.Lapses < scheduler.LeechThreshold || IsLeech(n) {
+		return false
+	}
+	n.Tags = append(n.Tags, LeechTag)
+	return true
+}
@@ -0,0 +1,388 @@
+package study
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/soyomarvaldezg/neuron-cli/internal/note"
+)
+
+// useScheduler makes UpdateSRSData use c for the rest of the test.
+func useScheduler(t *testing.T, c SchedulerConfig) {
+	t.Helper()
+	previous := CurrentSchedulerConfig()
+	SetSchedulerConfig(c)
+	t.Cleanup(func() { SetSchedulerConfig(previous) })
+}
+
+// reviewedNote returns a note that has graduated to the review state.
+func reviewedNote() *note.Note {
+	return &note.Note{Interval: 10, EaseFactor: note.DefaultEaseFactor, State: note.StateReview}
+}
+
+func TestEaseFactorStaysWithinBounds(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.AgainPenalty = 0.5
+	c.EasyBonus = 0.5
+	useScheduler(t, c)
+
+	tests := []struct {
+		name   string
+		rating int
+		want   float64
+	}{
+		{"repeated Again stops at the floor", RatingAgain, c.EaseFloor},
+		{"repeated Easy stops at the ceiling", RatingEasy, c.EaseCeiling},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := reviewedNote()
+			for i := 0; i < 50; i++ {
+				UpdateSRSData(n, tt.rating)
+				if n.EaseFactor < c.EaseFloor || n.EaseFactor > c.EaseCeiling {
+					t.Fatalf("review %d: ease %.2f outside [%.2f, %.2f]", i+1, n.EaseFactor, c.EaseFloor, c.EaseCeiling)
+				}
+			}
+			if n.EaseFactor != tt.want {
+				t.Errorf("ease after 50 reviews = %.2f, want %.2f", n.EaseFactor, tt.want)
+			}
+		})
+	}
+}
+
+func TestEaseFactorStaysWithinBoundsOverMixedRatings(t *testing.T) {
+	useScheduler(t, DefaultSchedulerConfig())
+	c := CurrentSchedulerConfig()
+	n := reviewedNote()
+	ratings := []int{RatingEasy, RatingEasy, RatingAgain, RatingGood, RatingAgain, RatingAgain, RatingAgain, RatingEasy}
+	for i := 0; i < 20; i++ {
+		for _, rating := range ratings {
+			UpdateSRSData(n, rating)
+			if n.EaseFactor < c.EaseFloor || n.EaseFactor > c.EaseCeiling {
+				t.Fatalf("ease %.2f outside [%.2f, %.2f] after rating %d", n.EaseFactor, c.EaseFloor, c.EaseCeiling, rating)
+			}
+		}
+	}
+}
+
+// dueDates rates two identical review notes Good and returns their due dates.
+func dueDates() (time.Time, time.Time) {
+	a, b := reviewedNote(), reviewedNote()
+	UpdateSRSData(a, RatingGood)
+	UpdateSRSData(b, RatingGood)
+	return a.DueDate, b.DueDate
+}
+
+func TestFuzzSpreadsOutIdenticalNotes(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.Fuzz = true
+	useScheduler(t, c)
+	SeedFuzz(1)
+
+	a, b := dueDates()
+	if diff := a.Sub(b).Abs(); diff < time.Minute {
+		t.Errorf("with fuzz, identical notes are due %v apart, want them spread out", diff)
+	}
+}
+
+func TestFuzzIsReproducibleWithASeed(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.Fuzz = true
+	useScheduler(t, c)
+
+	SeedFuzz(7)
+	a1, b1 := dueDates()
+	SeedFuzz(7)
+	a2, b2 := dueDates()
+	if d := a1.Sub(a2).Abs(); d > time.Second {
+		t.Errorf("same seed gave due dates %v apart", d)
+	}
+	if d := b1.Sub(b2).Abs(); d > time.Second {
+		t.Errorf("same seed gave due dates %v apart", d)
+	}
+}
+
+func TestNoFuzzSchedulesIdenticalNotesTogether(t *testing.T) {
+	useScheduler(t, DefaultSchedulerConfig())
+	a, b := dueDates()
+	if diff := a.Sub(b).Abs(); diff > time.Second {
+		t.Errorf("without fuzz, identical notes are due %v apart, want the same time", diff)
+	}
+}
+
+func TestHigherTargetRetentionShortensIntervals(t *testing.T) {
+	var previous time.Duration
+	for i, retention := range []float64{0.7, 0.8, 0.85, 0.9, 0.95, 0.99} {
+		c := DefaultSchedulerConfig()
+		c.TargetRetention = retention
+		useScheduler(t, c)
+
+		n := reviewedNote()
+		reviewed := time.Now()
+		UpdateSRSData(n, RatingGood)
+		due := n.DueDate.Sub(reviewed)
+		if i > 0 && due >= previous {
+			t.Errorf("retention %.2f: due in %v, want less than %v at the lower target", retention, due, previous)
+		}
+		if n.Interval != 25 {
+			t.Errorf("retention %.2f: stored interval %g, want it unscaled at 25", retention, n.Interval)
+		}
+		previous = due
+	}
+}
+
+func TestAgainOnReviewedNoteCountsALapse(t *testing.T) {
+	useScheduler(t, DefaultSchedulerConfig())
+	n := reviewedNote()
+	for _, rating := range []int{RatingAgain, RatingGood, RatingEasy, RatingAgain} {
+		UpdateSRSData(n, rating)
+	}
+	if n.Lapses != 2 {
+		t.Errorf("lapses = %d, want 2", n.Lapses)
+	}
+}
+
+func TestMarkLeech(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		lapses    int
+		want      bool
+	}{
+		{"below the threshold", 3, 2, false},
+		{"reaching the threshold", 3, 3, true},
+		{"past the threshold", 3, 5, true},
+		{"zero turns it off", 0, 20, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := DefaultSchedulerConfig()
+			c.LeechThreshold = tt.threshold
+			useScheduler(t, c)
+
+			n := reviewedNote()
+			n.Lapses = tt.lapses
+			if got := MarkLeech(n); got != tt.want {
+				t.Errorf("MarkLeech = %v, want %v", got, tt.want)
+			}
+			if IsLeech(n) != tt.want {
+				t.Errorf("IsLeech = %v, want %v", IsLeech(n), tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkLeechOnlyTagsOnce(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.LeechThreshold = 2
+	useScheduler(t, c)
+
+	n := reviewedNote()
+	var marked int
+	for i := 0; i < 4; i++ {
+		UpdateSRSData(n, RatingAgain)
+		if MarkLeech(n) {
+			marked++
+			if n.Lapses != 2 {
+				t.Errorf("tagged at %d lapses, want 2", n.Lapses)
+			}
+		}
+	}
+	if marked != 1 || len(n.Tags) != 1 {
+		t.Errorf("marked %d times with tags %v, want once", marked, n.Tags)
+	}
+}
+
+func TestLearningSteps(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.LearningSteps = []time.Duration{time.Minute, 10 * time.Minute, 24 * time.Hour}
+	useScheduler(t, c)
+
+	tests := []struct {
+		name     string
+		ratings  []int
+		state    string
+		step     int
+		interval float64
+		dueIn    time.Duration
+	}{
+		{"Good starts the second step", []int{RatingGood}, note.StateLearning, 1, note.DefaultInterval, 10 * time.Minute},
+		{"Good twice reaches the last step", []int{RatingGood, RatingGood}, note.StateLearning, 2, note.DefaultInterval, 24 * time.Hour},
+		{"Again goes back to the first step", []int{RatingGood, RatingGood, RatingAgain}, note.StateLearning, 0, note.DefaultInterval, time.Minute},
+		{"passing the last step graduates", []int{RatingGood, RatingGood, RatingGood}, note.StateReview, 0, graduatingInterval, 24 * time.Hour},
+		{"Easy graduates at once", []int{RatingEasy}, note.StateReview, 0, easyGraduatingInterval, 4 * 24 * time.Hour},
+		{"graduated notes leave the steps", []int{RatingEasy, RatingAgain}, note.StateReview, 0, 1, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &note.Note{Interval: note.DefaultInterval, EaseFactor: note.DefaultEaseFactor, State: note.StateNew}
+			var reviewed time.Time
+			for _, rating := range tt.ratings {
+				reviewed = time.Now()
+				UpdateSRSData(n, rating)
+			}
+			if n.State != tt.state || n.LearningStep != tt.step || n.Interval != tt.interval {
+				t.Errorf("state %q, step %d, interval %g; want %q, %d, %g", n.State, n.LearningStep, n.Interval, tt.state, tt.step, tt.interval)
+			}
+			if dueIn := n.DueDate.Sub(reviewed); (dueIn - tt.dueIn).Abs() > time.Second {
+				t.Errorf("due in %v, want %v", dueIn, tt.dueIn)
+			}
+		})
+	}
+}
+
+func TestNoLearningStepsGraduatesOnFirstReview(t *testing.T) {
+	useScheduler(t, DefaultSchedulerConfig())
+	n := &note.Note{Interval: note.DefaultInterval, EaseFactor: note.DefaultEaseFactor, State: note.StateNew}
+	UpdateSRSData(n, RatingGood)
+	if n.State != note.StateReview {
+		t.Errorf("state = %q, want %q", n.State, note.StateReview)
+	}
+}
+
+func TestParseLearningSteps(t *testing.T) {
+	tests := []struct {
+		steps   []string
+		want    []time.Duration
+		wantErr bool
+	}{
+		{[]string{"1m", " 10m ", "1d"}, []time.Duration{time.Minute, 10 * time.Minute, 24 * time.Hour}, false},
+		{[]string{"1h30m", "3d"}, []time.Duration{90 * time.Minute, 72 * time.Hour}, false},
+		{nil, nil, false},
+		{[]string{"1m", "soon"}, nil, true},
+		{[]string{"0m"}, nil, true},
+		{[]string{"-1d"}, nil, true},
+		{[]string{"d"}, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLearningSteps(tt.steps)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLearningSteps(%q) error = %v, want error %v", tt.steps, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseLearningSteps(%q) = %v, want %v", tt.steps, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("ParseLearningSteps(%q) = %v, want %v", tt.steps, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSimulateReviews(t *testing.T) {
+	useScheduler(t, DefaultSchedulerConfig())
+	ratings := []int{RatingGood, RatingGood, RatingGood, RatingAgain, RatingEasy}
+	want := []SimulatedReview{
+		{Rating: RatingGood, State: note.StateReview, Interval: 2, EaseFactor: 2.5, DueIn: 48 * time.Hour},
+		{Rating: RatingGood, State: note.StateReview, Interval: 4, EaseFactor: 2.5, DueIn: 96 * time.Hour},
+		{Rating: RatingGood, State: note.StateReview, Interval: 7, EaseFactor: 2.5, DueIn: 7 * 24 * time.Hour},
+		{Rating: RatingAgain, State: note.StateReview, Interval: 1, EaseFactor: 2.3, DueIn: 24 * time.Hour},
+		{Rating: RatingEasy, State: note.StateReview, Interval: 2, EaseFactor: 2.45, DueIn: 48 * time.Hour},
+	}
+
+	got := SimulateReviews(ratings, note.DefaultEaseFactor)
+	if len(got) != len(want) {
+		t.Fatalf("got %d steps, want %d", len(got), len(want))
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Rating != w.Rating || g.State != w.State || g.Interval != w.Interval || math.Abs(g.EaseFactor-w.EaseFactor) > 1e-9 || (g.DueIn-w.DueIn).Abs() > time.Second {
+			t.Errorf("step %d = %+v, want %+v", i+1, g, w)
+		}
+	}
+}
+
+func TestSimulateReviewsWithLearningSteps(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.LearningSteps = []time.Duration{time.Minute, 10 * time.Minute}
+	useScheduler(t, c)
+
+	got := SimulateReviews([]int{RatingGood, RatingGood}, 2.0)
+	if got[0].State != note.StateLearning || (got[0].DueIn-10*time.Minute).Abs() > time.Second {
+		t.Errorf("first step = %+v, want learning, due in 10m", got[0])
+	}
+	if got[1].State != note.StateReview || got[1].EaseFactor != 2.0 {
+		t.Errorf("second step = %+v, want graduated at ease 2.0", got[1])
+	}
+}
+
+// dueIn returns how long from now n is due, to the nearest second.
+func dueIn(n *note.Note) time.Duration {
+	return time.Until(n.DueDate).Round(time.Second)
+}
+
+func TestLapseInterval(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	c.LapseInterval = 10 * time.Minute
+	useScheduler(t, c)
+
+	n := reviewedNote()
+	UpdateSRSData(n, RatingAgain)
+	if got := dueIn(n); got != 10*time.Minute {
+		t.Errorf("after Again, due in %s, want 10m", got)
+	}
+	if n.Lapses != 1 {
+		t.Errorf("lapses = %d, want 1", n.Lapses)
+	}
+
+	// Recalling it next time starts the intervals over from one day.
+	UpdateSRSData(n, RatingGood)
+	if n.Interval != 1 {
+		t.Errorf("after Good, interval = %g days, want 1", n.Interval)
+	}
+	if got := dueIn(n); got != 24*time.Hour {
+		t.Errorf("after Good, due in %s, want 24h", got)
+	}
+}
+
+func TestNoLapseIntervalComesBackInADay(t *testing.T) {
+	useScheduler(t, DefaultSchedulerConfig())
+	n := reviewedNote()
+	UpdateSRSData(n, RatingAgain)
+	if n.Interval != 1 || dueIn(n) != 24*time.Hour {
+		t.Errorf("after Again, interval %g days due in %s; want 1 day", n.Interval, dueIn(n))
+	}
+}
+
+func TestInitialEase(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	tagEase, err := c.ParseTagEase(map[string]float64{"Languages": 2.0, "trivia": 2.7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.TagEase = tagEase
+	useScheduler(t, c)
+
+	tests := []struct {
+		name string
+		tags []string
+		want float64
+	}{
+		{"untagged", nil, note.DefaultEaseFactor},
+		{"tag without an ease", []string{"go"}, note.DefaultEaseFactor},
+		{"tagged", []string{"trivia"}, 2.7},
+		{"tag casing", []string{"LANGUAGES"}, 2.0},
+		{"lowest of several tags", []string{"trivia", "go", "languages"}, 2.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InitialEase(tt.tags); got != tt.want {
+				t.Errorf("InitialEase(%q) = %g, want %g", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTagEaseBounds(t *testing.T) {
+	c := DefaultSchedulerConfig()
+	for _, ease := range []float64{c.EaseFloor - 0.1, c.EaseCeiling + 0.1} {
+		if _, err := c.ParseTagEase(map[string]float64{"go": ease}); err == nil {
+			t.Errorf("ParseTagEase accepted %g outside [%g, %g]", ease, c.EaseFloor, c.EaseCeiling)
+		}
+	}
+}
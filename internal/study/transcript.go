@@ -0,0 +1,84 @@
+// Package study contains logic related to the learning process, like SRS and LLM interaction.
+package study
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// transcriptHeading marks the start of one message in a Markdown transcript.
+var transcriptHeading = regexp.MustCompile(`^### (system|user|assistant)$`)
+
+// SaveTranscript writes a conversation to path. Files ending in .json get a JSON
+// array of messages; anything else gets Markdown with one "### role" section per message.
+func SaveTranscript(path string, messages []OllamaMessage) error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var err error
+		data, err = json.MarshalIndent(messages, "", "  ")
+		if err != nil {
+			return err
+		}
+	} else {
+		data = []byte(MarkdownTranscript(messages))
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// MarkdownTranscript renders a conversation as Markdown that LoadTranscript can read back.
+func MarkdownTranscript(messages []OllamaMessage) string {
+	var b strings.Builder
+	b.WriteString("# Neuron Transcript\n")
+	for _, m := range messages {
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", m.Role, strings.TrimSpace(m.Content))
+	}
+	return b.String()
+}
+
+// LoadTranscript reads a conversation written by SaveTranscript.
+func LoadTranscript(path string) ([]OllamaMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var messages []OllamaMessage
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("could not parse transcript %s: %w", path, err)
+		}
+	} else {
+		messages = ParseMarkdownTranscript(string(data))
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("transcript %s contains no messages", path)
+	}
+	return messages, nil
+}
+
+// ParseMarkdownTranscript reads the messages back out of MarkdownTranscript output.
+func ParseMarkdownTranscript(text string) []OllamaMessage {
+	var messages []OllamaMessage
+	var current *OllamaMessage
+	var body []string
+	flush := func() {
+		if current != nil {
+			current.Content = strings.TrimSpace(strings.Join(body, "\n"))
+			messages = append(messages, *current)
+		}
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if match := transcriptHeading.FindStringSubmatch(strings.TrimRight(line, "\r")); match != nil {
+			flush()
+			current = &OllamaMessage{Role: match[1]}
+			body = nil
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return messages
+}
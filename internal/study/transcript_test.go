@@ -0,0 +1,79 @@
+package study
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var testTranscript = []OllamaMessage{
+	{Role: "system", Content: "You are a coach."},
+	{Role: "user", Content: "Explain maps.\n\n## Not a message heading\n- a list item"},
+	{Role: "assistant", Content: "Maps are hash tables.\n\n```go\nm := map[string]int{}\n```"},
+}
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	for _, name := range []string{"chat.json", "chat.JSON", "chat.md", "chat.txt"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := SaveTranscript(path, testTranscript); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadTranscript(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, testTranscript) {
+				t.Errorf("LoadTranscript() = %+v, want %+v", got, testTranscript)
+			}
+		})
+	}
+}
+
+func TestSaveTranscriptFormat(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath, mdPath := filepath.Join(dir, "chat.json"), filepath.Join(dir, "chat.md")
+	for _, path := range []string{jsonPath, mdPath} {
+		if err := SaveTranscript(path, testTranscript); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if data, _ := os.ReadFile(jsonPath); !strings.HasPrefix(string(data), "[") {
+		t.Errorf("%s isn't a JSON array:\n%s", jsonPath, data)
+	}
+	if data, _ := os.ReadFile(mdPath); !strings.HasPrefix(string(data), "# Neuron Transcript\n\n### system\n") {
+		t.Errorf("%s isn't a Markdown transcript:\n%s", mdPath, data)
+	}
+}
+
+func TestParseMarkdownTranscript(t *testing.T) {
+	text := "# Neuron Transcript\r\n\r\n### user\r\n\r\nHello\r\n\r\n### assistant\r\n\r\nHi there\r\n"
+	want := []OllamaMessage{{Role: "user", Content: "Hello"}, {Role: "assistant", Content: "Hi there"}}
+	if got := ParseMarkdownTranscript(text); !slices.Equal(got, want) {
+		t.Errorf("ParseMarkdownTranscript() with CRLF line endings = %+v, want %+v", got, want)
+	}
+	if got := ParseMarkdownTranscript("# Notes\n\n### Other heading\nText"); got != nil {
+		t.Errorf("ParseMarkdownTranscript() without role headings = %+v, want none", got)
+	}
+}
+
+func TestLoadTranscriptErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"empty.md":    "# Neuron Transcript\n",
+		"broken.json": "[{\"role\": ",
+		"empty.json":  "[]",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"empty.md", "broken.json", "empty.json", "missing.md"} {
+		if messages, err := LoadTranscript(filepath.Join(dir, name)); err == nil {
+			t.Errorf("LoadTranscript(%s) = %+v, want an error", name, messages)
+		}
+	}
+}